@@ -6,18 +6,22 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 
+	"clipboardpro/internal/backup"
 	"clipboardpro/internal/clipboard"
 	"clipboardpro/internal/config"
 	"clipboardpro/internal/database"
+	"clipboardpro/internal/retention"
 	"clipboardpro/internal/ui/components"
 )
 
@@ -33,30 +37,48 @@ const (
 	UpdateCheckInterval = 24 * time.Hour
 )
 
+// Options select how the application runs.
+type Options struct {
+	// Ephemeral keeps the history in memory only, for shared machines and
+	// demos. Settings changes last for the session and backups are off.
+	Ephemeral bool
+}
+
 type ClipboardProApp struct {
+	options    Options
 	fyneApp    fyne.App
 	window     fyne.Window
 	config     *config.Config
-	repository *database.Repository
+	repository database.Store
 	monitor    *clipboard.Monitor
+	backups    *backup.Manager
 
 	itemList  *components.ItemList
 	searchBar *components.SearchBar
 	toolbar   *components.Toolbar
 	statusBar *widget.Label
 
+	templatePrompt *components.TemplatePrompt
+
+	mainContent  fyne.CanvasObject
+	lockScreen   *components.LockScreen
+	locked       atomic.Bool // The lock screen is shown
+	lastActivity time.Time   // Last user interaction, for the idle lock
+	lastInput    inputState  // Input state at the last idle check
+
 	updateChecker *UpdateChecker
 
 	ctx        context.Context
 	cancelFunc context.CancelFunc
 }
 
-func NewClipboardProApp() (*ClipboardProApp, error) {
+func NewClipboardProApp(options Options) (*ClipboardProApp, error) {
 	fyneApp := app.NewWithID(AppID)
 
 	ctx, cancel := context.WithCancel(context.Background())
 
 	clipboardApp := &ClipboardProApp{
+		options:    options,
 		fyneApp:    fyneApp,
 		ctx:        ctx,
 		cancelFunc: cancel,
@@ -117,14 +139,26 @@ func (a *ClipboardProApp) initConfig() error {
 	if err != nil {
 		log.Printf("Creating default configuration: %v", err)
 		a.config = config.Default()
-		if err := a.config.Save(configPath); err != nil {
-			log.Printf("Failed to save default config: %v", err)
+		if !a.options.Ephemeral {
+			if err := a.config.Save(configPath); err != nil {
+				log.Printf("Failed to save default config: %v", err)
+			}
 		}
 	}
 	return nil
 }
 
 func (a *ClipboardProApp) initDatabase() error {
+	if a.options.Ephemeral {
+		repository, err := database.NewMemoryRepository()
+		if err != nil {
+			return fmt.Errorf("failed to initialize database: %w", err)
+		}
+		a.repository = repository
+		log.Println("Ephemeral session: the history is kept in memory only")
+		return nil
+	}
+
 	configDir, err := a.getConfigDir()
 	if err != nil {
 		return fmt.Errorf("failed to get config directory: %w", err)
@@ -138,32 +172,74 @@ func (a *ClipboardProApp) initDatabase() error {
 
 func (a *ClipboardProApp) initServices() {
 	a.monitor = clipboard.NewMonitor(a.repository, a.config)
+
+	// An ephemeral history is never written to disk, backups included
+	if !a.options.Ephemeral {
+		configDir, _ := a.getConfigDir()
+		a.backups = backup.NewManager(a.repository, filepath.Join(configDir, "backups"))
+	}
 }
 
 func (a *ClipboardProApp) initUIComponents() {
 	a.itemList = components.NewItemList(a.repository, a)
 	a.searchBar = components.NewSearchBar(a.itemList)
-	a.toolbar = components.NewToolbar(a.itemList, a.showSettings, a.clearAll, a.showAbout, a.checkForUpdates)
+	a.toolbar = components.NewToolbar(a.itemList, a.showSettings, a.clearAll, a.showAbout, a.checkForUpdates, a.showTrash, a.showExport, a.showImport, a.lockNow)
 	a.statusBar = widget.NewLabel("Starting ClipBoard Pro...")
+
+	a.templatePrompt = components.NewTemplatePrompt(a.GetWindow)
+	a.monitor.SetTemplateResolver(a.templatePrompt)
 }
 
 func (a *ClipboardProApp) createMainWindow() {
-	a.window = a.fyneApp.NewWindow(a.GetAppName())
+	title := a.GetAppName()
+	if a.options.Ephemeral {
+		title += " (Ephemeral Session)"
+	}
+	a.window = a.fyneApp.NewWindow(title)
 	a.window.SetMaster()
 	a.window.Resize(fyne.NewSize(900, 700))
 	a.window.CenterOnScreen()
 
-	content := a.createMainContent()
-	a.window.SetContent(content)
+	a.mainContent = components.NewActivityArea(a.createMainContent(), a.RecordActivity)
+	a.window.SetContent(a.mainContent)
 
-	a.window.SetCloseIntercept(func() {
-		a.cleanup()
-		a.fyneApp.Quit()
-	})
+	a.registerShortcuts()
+	a.initLock()
+
+	a.window.SetCloseIntercept(a.quit)
 
 	a.showWelcomeIfFirstRun()
 }
 
+func (a *ClipboardProApp) registerShortcuts() {
+	canvas := a.window.Canvas()
+
+	canvas.AddShortcut(&desktop.CustomShortcut{
+		KeyName:  fyne.KeyZ,
+		Modifier: fyne.KeyModifierShortcutDefault,
+	}, func(fyne.Shortcut) {
+		if !a.locked.Load() {
+			a.itemList.Undo()
+		}
+	})
+
+	canvas.AddShortcut(&desktop.CustomShortcut{
+		KeyName:  fyne.KeyZ,
+		Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift,
+	}, func(fyne.Shortcut) {
+		if !a.locked.Load() {
+			a.itemList.Redo()
+		}
+	})
+
+	canvas.AddShortcut(&desktop.CustomShortcut{
+		KeyName:  fyne.KeyL,
+		Modifier: fyne.KeyModifierShortcutDefault,
+	}, func(fyne.Shortcut) {
+		a.lockNow()
+	})
+}
+
 func (a *ClipboardProApp) createMainContent() fyne.CanvasObject {
 	welcomeContent := container.NewVBox(
 		widget.NewIcon(theme.InfoIcon()),
@@ -202,7 +278,11 @@ func (a *ClipboardProApp) showWelcomeIfFirstRun() {
 	firstRunFile := filepath.Join(configDir, ".first_run")
 
 	if _, err := os.Stat(firstRunFile); os.IsNotExist(err) {
-		os.WriteFile(firstRunFile, []byte(""), 0644)
+		// Ephemeral sessions write nothing to disk, so they welcome the
+		// user again until a regular session has run
+		if !a.options.Ephemeral {
+			os.WriteFile(firstRunFile, []byte(""), 0644)
+		}
 
 		welcomeText := `Welcome to ClipBoard Pro!
 
@@ -213,6 +293,7 @@ Key features:
 - Search through your history
 - Pin important items
 - Organize with custom titles
+- Template placeholders like {{date}} and {{input:Name}}
 - Automatic updates
 
 ClipBoard Pro runs in the background and can be accessed from the system tray.`
@@ -229,7 +310,26 @@ ClipBoard Pro runs in the background and can be accessed from the system tray.`
 func (a *ClipboardProApp) ShowAndRun() {
 	defer a.repository.Close()
 
-	// Start background services
+	// An encrypted history must be unlocked before anything reads or writes it
+	if a.repository.IsLocked() {
+		a.statusBar.SetText("History is locked")
+		components.NewUnlockDialog(a.repository.Unlock, a.startServices, a.quit, a.window).Show()
+	} else {
+		a.startServices()
+		if a.config.LockEnabled {
+			a.lockApp()
+		}
+	}
+
+	a.window.Show()
+	a.fyneApp.Run()
+
+	a.cleanup()
+}
+
+// startServices starts clipboard monitoring and the background routines,
+// and loads the history.
+func (a *ClipboardProApp) startServices() {
 	go func() {
 		if err := a.monitor.Start(a.ctx); err != nil {
 			log.Printf("Failed to start clipboard monitor: %v", err)
@@ -254,8 +354,8 @@ func (a *ClipboardProApp) ShowAndRun() {
 			case <-ticker.C:
 				if a.itemList != nil {
 					fyne.Do(func() {
-						if !a.itemList.IsSearching() {
-							a.itemList.LoadRecentItems()
+						if !a.locked.Load() && !a.itemList.IsSearching() {
+							a.itemList.RefreshIfChanged()
 						}
 					})
 				}
@@ -264,6 +364,11 @@ func (a *ClipboardProApp) ShowAndRun() {
 	}()
 
 	go a.startCleanupRoutine()
+	go a.startExpiryRoutine()
+	if a.backups != nil {
+		go a.startBackupRoutine()
+	}
+	go a.startIdleLockRoutine()
 
 	if a.config.CheckUpdatesOnStartup {
 		go func() {
@@ -280,15 +385,19 @@ func (a *ClipboardProApp) ShowAndRun() {
 
 	go func() {
 		time.Sleep(2 * time.Second)
+		status := "Ready • Monitoring clipboard"
+		if a.options.Ephemeral {
+			status += " • Ephemeral session, history is not saved"
+		}
 		fyne.Do(func() {
-			a.statusBar.SetText("Ready • Monitoring clipboard")
+			a.statusBar.SetText(status)
 		})
 	}()
+}
 
-	a.window.Show()
-	a.fyneApp.Run()
-
+func (a *ClipboardProApp) quit() {
 	a.cleanup()
+	a.fyneApp.Quit()
 }
 
 func (a *ClipboardProApp) cleanup() {
@@ -314,13 +423,87 @@ func (a *ClipboardProApp) startCleanupRoutine() {
 		case <-a.ctx.Done():
 			return
 		case <-ticker.C:
-			if err := a.repository.CleanupOldItems(a.ctx, a.config.MaxHistoryDays, a.config.MaxHistoryItems); err != nil {
+			if _, err := retention.Cleanup(a.ctx, a.repository, retention.PolicyFromConfig(a.config)); err != nil {
 				log.Printf("Cleanup failed: %v", err)
 			}
+			if err := a.repository.PurgeTrash(a.ctx, a.config.TrashRetentionDays); err != nil {
+				log.Printf("Trash purge failed: %v", err)
+			}
 		}
 	}
 }
 
+// startExpiryRoutine deletes items shortly after they expire, rather than
+// waiting for the hourly cleanup.
+func (a *ClipboardProApp) startExpiryRoutine() {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+			next, err := a.repository.NextExpiry(a.ctx)
+			if err != nil {
+				log.Printf("Expiry check failed: %v", err)
+				continue
+			}
+			if next.IsZero() || next.After(time.Now()) {
+				continue
+			}
+
+			purged, err := a.repository.PurgeExpiredItems(a.ctx, time.Now())
+			if err != nil {
+				log.Printf("Expired item purge failed: %v", err)
+			} else if purged > 0 {
+				log.Printf("Deleted %d expired items", purged)
+			}
+		}
+	}
+}
+
+// startBackupRoutine takes a backup whenever the newest one is older than
+// the configured interval, then rotates old backups.
+func (a *ClipboardProApp) startBackupRoutine() {
+	ticker := time.NewTicker(10 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		if a.config.BackupEnabled {
+			a.backupIfDue()
+		}
+
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (a *ClipboardProApp) backupIfDue() {
+	due, err := a.backups.Due(time.Duration(a.config.BackupIntervalHours) * time.Hour)
+	if err != nil {
+		log.Printf("Failed to check backups: %v", err)
+		return
+	}
+	if !due {
+		return
+	}
+
+	b, err := a.backups.Create(a.ctx)
+	if err != nil {
+		log.Printf("Backup failed: %v", err)
+		return
+	}
+	log.Printf("Backed up history to %s", b.Path)
+
+	if _, err := a.backups.Rotate(a.config.BackupKeepCount, a.config.BackupKeepDays); err != nil {
+		log.Printf("Backup rotation failed: %v", err)
+	}
+}
+
 func (a *ClipboardProApp) checkForUpdates() {
 	if a.updateChecker != nil {
 		a.updateChecker.CheckForUpdates(a.ctx, true)
@@ -336,15 +519,19 @@ func (a *ClipboardProApp) showSettings() {
 	fyne.Do(func() {
 		settingsDialog := components.NewSettingsDialog(a.config, a.window, func(newConfig *config.Config) {
 			a.config = newConfig
-			configDir, _ := a.getConfigDir()
-			if err := a.config.Save(filepath.Join(configDir, "config.json")); err != nil {
-				log.Printf("Failed to save config: %v", err)
+			a.monitor.SetConfig(newConfig)
+			if !a.options.Ephemeral {
+				configDir, _ := a.getConfigDir()
+				if err := a.config.Save(filepath.Join(configDir, "config.json")); err != nil {
+					log.Printf("Failed to save config: %v", err)
+				}
 			}
 			a.itemList.Refresh()
 			fyne.Do(func() {
 				a.statusBar.SetText("Settings saved")
 			})
-		})
+		}, a.showBackups, a.showEncryption, a.previewCleanup,
+			components.NewStoragePanel(a.repository, a.itemList, a.monitor.Pause, a.monitor.Resume, a.window))
 		settingsDialog.Show()
 	})
 }
@@ -356,28 +543,108 @@ func (a *ClipboardProApp) clearAll() {
 	}
 
 	fyne.Do(func() {
-		dialog.ShowConfirm("Clear All", "Are you sure you want to clear all clipboard history? This action cannot be undone.",
+		dialog.ShowConfirm("Clear All", "Are you sure you want to clear all clipboard history? Cleared items, including pinned ones, can be restored from the trash.",
 			func(confirmed bool) {
 				if !confirmed {
 					return
 				}
-				go func() {
-					ctx := context.Background()
-					if err := a.repository.ClearAllItems(ctx); err != nil {
-						fyne.Do(func() {
-							dialog.ShowError(fmt.Errorf("failed to clear all items: %w", err), a.window)
-						})
-						return
-					}
-					fyne.Do(func() {
-						a.itemList.Refresh()
-						a.statusBar.SetText("All clipboard history cleared")
-					})
-				}()
+				a.itemList.ClearAll()
 			}, a.window)
 	})
 }
 
+func (a *ClipboardProApp) showTrash() {
+	if a.window == nil {
+		log.Printf("Warning: Window is nil, cannot show trash")
+		return
+	}
+
+	fyne.Do(func() {
+		components.NewTrashDialog(a.repository, a.itemList, a.window).Show()
+	})
+}
+
+func (a *ClipboardProApp) showBackups() {
+	if a.window == nil {
+		log.Printf("Warning: Window is nil, cannot show backups")
+		return
+	}
+
+	if a.backups == nil {
+		dialog.ShowInformation("Backups",
+			"Backups are not available in an ephemeral session, which keeps the history in memory only.", a.window)
+		return
+	}
+
+	fyne.Do(func() {
+		components.NewBackupsDialog(a.backups, a.itemList, a.onHistoryRestored, a.monitor.Pause, a.monitor.Resume, a.window).Show()
+	})
+}
+
+// onHistoryRestored reloads the list after a backup was restored. A backup
+// encrypted with another passphrase leaves the history locked until that
+// passphrase is entered.
+func (a *ClipboardProApp) onHistoryRestored() {
+	if !a.repository.IsLocked() {
+		a.itemList.Refresh()
+		return
+	}
+
+	a.monitor.Pause()
+	components.NewUnlockDialog(a.repository.Unlock, func() {
+		a.monitor.Resume()
+		a.itemList.Refresh()
+	}, a.quit, a.window).Show()
+}
+
+// previewCleanup shows what a cleanup with the given policy would remove.
+func (a *ClipboardProApp) previewCleanup(policy retention.Policy) {
+	go func() {
+		plan, err := retention.Preview(a.ctx, a.repository, policy)
+
+		fyne.Do(func() {
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("failed to preview cleanup: %w", err), a.window)
+				return
+			}
+			components.NewCleanupPreviewDialog(plan, a.itemList, a.window).Show()
+		})
+	}()
+}
+
+func (a *ClipboardProApp) showEncryption() {
+	if a.window == nil {
+		log.Printf("Warning: Window is nil, cannot show encryption")
+		return
+	}
+
+	fyne.Do(func() {
+		components.NewEncryptionDialog(a.repository, a.backups, a.itemList.Refresh, a.monitor.Pause, a.monitor.Resume, a.window).Show()
+	})
+}
+
+func (a *ClipboardProApp) showExport() {
+	if a.window == nil {
+		log.Printf("Warning: Window is nil, cannot show export")
+		return
+	}
+
+	fyne.Do(func() {
+		components.NewExportDialog(a.repository, a.window).Show()
+	})
+}
+
+func (a *ClipboardProApp) showImport() {
+	if a.window == nil {
+		log.Printf("Warning: Window is nil, cannot show import")
+		return
+	}
+
+	fyne.Do(func() {
+		components.NewImportDialog(a.repository, a.itemList, a.window).Show()
+	})
+}
+
 func (a *ClipboardProApp) showAbout() {
 	if a.window == nil {
 		log.Printf("Warning: Window is nil, cannot show about")
@@ -402,17 +669,16 @@ func (a *ClipboardProApp) showAbout() {
 	})
 }
 
+// getConfigDir returns the configuration directory. Ephemeral sessions
+// only read from it, so they leave it uncreated.
 func (a *ClipboardProApp) getConfigDir() (string, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
+	if a.options.Ephemeral {
+		return config.DirPath()
 	}
-
-	configDir := filepath.Join(homeDir, ".clipboardpro")
-	return configDir, os.MkdirAll(configDir, 0755)
+	return config.Dir()
 }
 
-func (a *ClipboardProApp) GetRepository() *database.Repository {
+func (a *ClipboardProApp) GetRepository() database.Store {
 	return a.repository
 }
 
@@ -425,5 +691,16 @@ func (a *ClipboardProApp) GetWindow() fyne.Window {
 }
 
 func (a *ClipboardProApp) CopyItemToClipboard(id int64) error {
+	if a.locked.Load() {
+		return errAppLocked
+	}
 	return a.monitor.CopyItemToClipboard(a.ctx, id)
 }
+
+func (a *ClipboardProApp) CopyTextToClipboard(text string) error {
+	if a.locked.Load() {
+		return errAppLocked
+	}
+	a.monitor.CopyTextToClipboard(text)
+	return nil
+}
@@ -0,0 +1,138 @@
+package app
+
+import (
+	"errors"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"clipboardpro/internal/ui/components"
+	"clipboardpro/internal/vault"
+)
+
+// errAppLocked is returned by actions that are unavailable while the lock
+// screen is shown.
+var errAppLocked = errors.New("ClipBoard Pro is locked")
+
+// initLock prepares the lock screen and the events that engage it.
+func (a *ClipboardProApp) initLock() {
+	a.lockScreen = components.NewLockScreen(func(secret string) error {
+		return vault.VerifySecret(secret, a.config.LockSecretHash)
+	}, a.unlockApp)
+	a.lastActivity = time.Now()
+
+	// Keys typed into a focused widget and scrolling do not reach these,
+	// so they are noticed by comparing inputState at each idle check
+	canvas := a.window.Canvas()
+	canvas.SetOnTypedKey(func(*fyne.KeyEvent) { a.RecordActivity() })
+	canvas.SetOnTypedRune(func(rune) { a.RecordActivity() })
+
+	lifecycle := a.fyneApp.Lifecycle()
+	lifecycle.SetOnEnteredForeground(a.RecordActivity)
+	lifecycle.SetOnExitedForeground(func() {
+		if a.config.LockEnabled && a.config.LockOnFocusLoss {
+			a.lockApp()
+		}
+	})
+}
+
+// RecordActivity notes that the user is interacting with the app, which
+// postpones the idle lock.
+func (a *ClipboardProApp) RecordActivity() {
+	a.lastActivity = time.Now()
+}
+
+// lockNow locks the app from the toolbar, explaining how to set the lock up
+// if it is not configured yet.
+func (a *ClipboardProApp) lockNow() {
+	if a.config.LockSecretHash == "" {
+		dialog.ShowInformation("Lock",
+			"Set a PIN or passphrase in Settings → Security to lock ClipBoard Pro.", a.window)
+		return
+	}
+	a.lockApp()
+}
+
+// lockApp covers the main window with the lock screen and closes any open
+// dialogs. Clipboard capture keeps running. An encrypted history that is
+// still locked shows nothing yet and has its own prompt open, so it is left
+// alone.
+func (a *ClipboardProApp) lockApp() {
+	if a.locked.Load() || a.config.LockSecretHash == "" || a.repository.IsLocked() {
+		return
+	}
+	a.locked.Store(true)
+
+	// Template prompts are answered first, or the copies waiting on them
+	// would never finish once their dialogs are gone
+	a.templatePrompt.Cancel()
+
+	canvas := a.window.Canvas()
+	for overlay := canvas.Overlays().Top(); overlay != nil; overlay = canvas.Overlays().Top() {
+		canvas.Overlays().Remove(overlay)
+	}
+
+	a.window.SetContent(a.lockScreen.Create())
+	a.lockScreen.Reset(canvas)
+}
+
+func (a *ClipboardProApp) unlockApp() {
+	if !a.locked.Load() {
+		return
+	}
+	a.locked.Store(false)
+	a.lastActivity = time.Now()
+
+	a.window.SetContent(a.mainContent)
+	a.itemList.Refresh()
+}
+
+// startIdleLockRoutine locks the app once it has not been used for the
+// configured number of minutes.
+func (a *ClipboardProApp) startIdleLockRoutine() {
+	ticker := time.NewTicker(15 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+			fyne.Do(func() {
+				if input := a.inputState(); input != a.lastInput {
+					a.lastInput = input
+					a.RecordActivity()
+				}
+
+				idle := time.Duration(a.config.LockIdleMinutes) * time.Minute
+				if a.config.LockEnabled && idle > 0 && time.Since(a.lastActivity) >= idle {
+					a.lockApp()
+				}
+			})
+		}
+	}
+}
+
+// inputState is what the user can change in the main window without events
+// the app receives: the focused widget, the text length and cursor of a
+// focused entry, and the scroll position of the list. The text itself is
+// left out, as it may be a passphrase.
+type inputState struct {
+	focused        fyne.Focusable
+	length         int
+	row, column    int
+	scrollPosition float32
+}
+
+func (a *ClipboardProApp) inputState() inputState {
+	state := inputState{
+		focused:        a.window.Canvas().Focused(),
+		scrollPosition: a.itemList.ScrollOffset(),
+	}
+	if entry, ok := state.focused.(*widget.Entry); ok {
+		state.length, state.row, state.column = len(entry.Text), entry.CursorRow, entry.CursorColumn
+	}
+	return state
+}
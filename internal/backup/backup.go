@@ -0,0 +1,275 @@
+// Package backup takes snapshots of the clipboard database, rotates them and
+// restores them.
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"clipboardpro/internal/database"
+)
+
+const (
+	filePrefix = "clipboard-"
+	fileSuffix = ".db"
+	timeLayout = "20060102-150405"
+
+	// Restores take a backup of the current database first, marked with
+	// this suffix before the extension.
+	beforeRestoreMarker = "-before-restore"
+)
+
+// ErrBusy is returned when a backup or restore is already running.
+var ErrBusy = errors.New("a backup or restore is already in progress")
+
+// Backup is a snapshot of the database in the backups directory.
+type Backup struct {
+	Path          string
+	CreatedAt     time.Time
+	Size          int64
+	BeforeRestore bool // Taken automatically before a restore
+
+	// Filled in by List; nil if the backup could not be read
+	Stats *database.BackupStats
+
+	modTime time.Time // Orders backups taken within the same second
+}
+
+// Name returns the backup's file name.
+func (b *Backup) Name() string {
+	return filepath.Base(b.Path)
+}
+
+// Manager creates, lists, rotates and restores backups in one directory.
+type Manager struct {
+	repository database.Store
+	dir        string
+	mu         sync.Mutex // Serialises backups and restores
+}
+
+func NewManager(repository database.Store, dir string) *Manager {
+	return &Manager{
+		repository: repository,
+		dir:        dir,
+	}
+}
+
+// Dir returns the directory backups are stored in.
+func (m *Manager) Dir() string {
+	return m.dir
+}
+
+// Create takes a backup now.
+func (m *Manager) Create(ctx context.Context) (*Backup, error) {
+	if !m.mu.TryLock() {
+		return nil, ErrBusy
+	}
+	defer m.mu.Unlock()
+
+	return m.create(ctx, "")
+}
+
+func (m *Manager) create(ctx context.Context, marker string) (*Backup, error) {
+	if err := os.MkdirAll(m.dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	now := time.Now()
+	path := filepath.Join(m.dir, filePrefix+now.Format(timeLayout)+marker+fileSuffix)
+	for i := 2; fileExists(path); i++ {
+		path = filepath.Join(m.dir, fmt.Sprintf("%s%s%s-%d%s", filePrefix, now.Format(timeLayout), marker, i, fileSuffix))
+	}
+
+	if err := m.repository.BackupTo(ctx, path); err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	return &Backup{Path: path, CreatedAt: now, Size: info.Size(), BeforeRestore: marker != ""}, nil
+}
+
+// List returns the backups, newest first, with their item counts.
+func (m *Manager) List(ctx context.Context) ([]*Backup, error) {
+	backups, err := m.scan()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range backups {
+		stats, err := database.ReadBackupStats(ctx, b.Path)
+		if err != nil {
+			log.Printf("Failed to read backup %s: %v", b.Name(), err)
+			continue
+		}
+		b.Stats = stats
+	}
+
+	return backups, nil
+}
+
+// Unencrypted returns the readable backups, newest first, whose history is
+// not encrypted, such as those taken before encryption was turned on.
+func (m *Manager) Unencrypted(ctx context.Context) ([]*Backup, error) {
+	backups, err := m.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var unencrypted []*Backup
+	for _, b := range backups {
+		if b.Stats != nil && !b.Stats.Encrypted {
+			unencrypted = append(unencrypted, b)
+		}
+	}
+	return unencrypted, nil
+}
+
+// scan finds the backup files, newest first, without opening them.
+func (m *Manager) scan() ([]*Backup, error) {
+	entries, err := os.ReadDir(m.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	var backups []*Backup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, filePrefix) || !strings.HasSuffix(name, fileSuffix) {
+			continue
+		}
+
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, filePrefix), fileSuffix)
+		if len(stamp) < len(timeLayout) {
+			continue
+		}
+		createdAt, err := time.ParseInLocation(timeLayout, stamp[:len(timeLayout)], time.Local)
+		if err != nil {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		backups = append(backups, &Backup{
+			Path:          filepath.Join(m.dir, name),
+			CreatedAt:     createdAt,
+			Size:          info.Size(),
+			BeforeRestore: strings.Contains(stamp, beforeRestoreMarker),
+			modTime:       info.ModTime(),
+		})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		if !backups[i].CreatedAt.Equal(backups[j].CreatedAt) {
+			return backups[i].CreatedAt.After(backups[j].CreatedAt)
+		}
+		return backups[i].modTime.After(backups[j].modTime)
+	})
+
+	return backups, nil
+}
+
+// Latest returns the newest backup, or nil if there is none.
+func (m *Manager) Latest() (*Backup, error) {
+	backups, err := m.scan()
+	if err != nil || len(backups) == 0 {
+		return nil, err
+	}
+	return backups[0], nil
+}
+
+// Rotate deletes backups beyond the newest keepCount and, if keepDays is
+// positive, backups older than keepDays. The newest backup is always kept.
+func (m *Manager) Rotate(keepCount, keepDays int) (int, error) {
+	backups, err := m.scan()
+	if err != nil {
+		return 0, err
+	}
+
+	cutoff := time.Time{}
+	if keepDays > 0 {
+		cutoff = time.Now().AddDate(0, 0, -keepDays)
+	}
+
+	removed := 0
+	for i, b := range backups {
+		if i == 0 {
+			continue
+		}
+		if i < keepCount && !b.CreatedAt.Before(cutoff) {
+			continue
+		}
+		if err := os.Remove(b.Path); err != nil {
+			return removed, fmt.Errorf("failed to delete backup %s: %w", b.Name(), err)
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+// Delete removes a backup.
+func (m *Manager) Delete(b *Backup) error {
+	if err := os.Remove(b.Path); err != nil {
+		return fmt.Errorf("failed to delete backup: %w", err)
+	}
+	return nil
+}
+
+// Restore replaces the database contents with a backup. The current
+// contents are backed up first, and that backup is returned so the restore
+// can be undone. Clipboard monitoring should be paused while this runs.
+func (m *Manager) Restore(ctx context.Context, b *Backup) (*Backup, error) {
+	if !m.mu.TryLock() {
+		return nil, ErrBusy
+	}
+	defer m.mu.Unlock()
+
+	if _, err := database.ReadBackupStats(ctx, b.Path); err != nil {
+		return nil, fmt.Errorf("backup %s cannot be read: %w", b.Name(), err)
+	}
+
+	safety, err := m.create(ctx, beforeRestoreMarker)
+	if err != nil {
+		return nil, fmt.Errorf("failed to back up current history: %w", err)
+	}
+
+	if err := m.repository.RestoreFrom(ctx, b.Path); err != nil {
+		return safety, err
+	}
+
+	return safety, nil
+}
+
+// Due reports whether a scheduled backup should be taken, given the interval
+// between backups.
+func (m *Manager) Due(interval time.Duration) (bool, error) {
+	latest, err := m.Latest()
+	if err != nil {
+		return false, err
+	}
+	return latest == nil || time.Since(latest.CreatedAt) >= interval, nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
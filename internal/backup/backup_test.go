@@ -0,0 +1,181 @@
+package backup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"clipboardpro/internal/database"
+)
+
+// writeBackups creates empty backup files taken the given numbers of days
+// ago, and returns their names in the same order.
+func writeBackups(t *testing.T, dir string, days ...int) []string {
+	t.Helper()
+	now := time.Now()
+	names := make([]string, len(days))
+	for i, d := range days {
+		names[i] = filePrefix + now.AddDate(0, 0, -d).Format(timeLayout) + fileSuffix
+		if err := os.WriteFile(filepath.Join(dir, names[i]), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return names
+}
+
+func backupNames(t *testing.T, m *Manager) []string {
+	t.Helper()
+	backups, err := m.scan()
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	var names []string
+	for _, b := range backups {
+		names = append(names, b.Name())
+	}
+	return names
+}
+
+func TestRotate(t *testing.T) {
+	tests := []struct {
+		name                string
+		days                []int // Age of each backup, newest first
+		keepCount, keepDays int
+		want                []int // Indexes into days of the backups kept
+	}{
+		{"count only", []int{0, 1, 2, 5, 10, 40}, 3, 0, []int{0, 1, 2}},
+		{"count above the number of backups", []int{0, 1, 2}, 10, 0, []int{0, 1, 2}},
+		{"days only", []int{0, 1, 2, 5, 10, 40}, 100, 7, []int{0, 1, 2, 3}},
+		{"count is stricter", []int{0, 1, 2, 5, 10, 40}, 2, 7, []int{0, 1}},
+		{"days are stricter", []int{0, 1, 2, 5, 10, 40}, 5, 3, []int{0, 1, 2}},
+		{"newest is kept when all are too old", []int{20, 30, 40}, 5, 7, []int{0}},
+		{"newest is kept with no count", []int{0, 1}, 0, 0, []int{0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			names := writeBackups(t, dir, tt.days...)
+			m := NewManager(nil, dir)
+
+			removed, err := m.Rotate(tt.keepCount, tt.keepDays)
+			if err != nil {
+				t.Fatalf("Rotate: %v", err)
+			}
+
+			var want []string
+			for _, i := range tt.want {
+				want = append(want, names[i])
+			}
+			if got := backupNames(t, m); !slices.Equal(got, want) {
+				t.Errorf("kept %v, want %v", got, want)
+			}
+			if removed != len(tt.days)-len(tt.want) {
+				t.Errorf("removed = %d, want %d", removed, len(tt.days)-len(tt.want))
+			}
+		})
+	}
+}
+
+func TestRestore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	repository, err := database.NewRepository(filepath.Join(dir, "history.db"))
+	if err != nil {
+		t.Fatalf("NewRepository: %v", err)
+	}
+	defer repository.Close()
+	m := NewManager(repository, filepath.Join(dir, "backups"))
+
+	save := func(content string) {
+		t.Helper()
+		if err := repository.SaveClipboardItem(ctx, &database.ClipboardItem{Type: "text", Content: content, Hash: content}); err != nil {
+			t.Fatalf("SaveClipboardItem: %v", err)
+		}
+	}
+	count := func() int {
+		t.Helper()
+		n, err := repository.CountItems(ctx, "")
+		if err != nil {
+			t.Fatalf("CountItems: %v", err)
+		}
+		return n
+	}
+
+	save("first")
+	b, err := m.Create(ctx)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	save("second")
+	save("third")
+
+	safety, err := m.Restore(ctx, b)
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if got := count(); got != 1 {
+		t.Errorf("%d items after restoring, want 1", got)
+	}
+
+	// The safety copy holds the history as it was before the restore
+	if !safety.BeforeRestore {
+		t.Error("safety backup is not marked as taken before a restore")
+	}
+	stats, err := database.ReadBackupStats(ctx, safety.Path)
+	if err != nil {
+		t.Fatalf("ReadBackupStats: %v", err)
+	}
+	if stats.Items != 3 {
+		t.Errorf("safety backup holds %d items, want 3", stats.Items)
+	}
+	backups, err := m.List(ctx)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(backups) != 2 || !backups[0].BeforeRestore {
+		t.Errorf("List = %d backups, want the safety backup listed first", len(backups))
+	}
+
+	// Restoring the safety copy undoes the restore
+	if _, err := m.Restore(ctx, safety); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if got := count(); got != 3 {
+		t.Errorf("%d items after undoing the restore, want 3", got)
+	}
+}
+
+func TestRestoreUnreadableBackup(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	repository, err := database.NewRepository(filepath.Join(dir, "history.db"))
+	if err != nil {
+		t.Fatalf("NewRepository: %v", err)
+	}
+	defer repository.Close()
+
+	backupDir := filepath.Join(dir, "backups")
+	if err := os.Mkdir(backupDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	names := writeBackups(t, backupDir, 0)
+	if err := os.WriteFile(filepath.Join(backupDir, names[0]), []byte("not a database"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := NewManager(repository, backupDir)
+
+	backups, err := m.List(ctx)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if _, err := m.Restore(ctx, backups[0]); err == nil {
+		t.Fatal("Restore accepted a file that is not a database")
+	}
+	if got := backupNames(t, m); !slices.Equal(got, names) {
+		t.Errorf("backups = %v, want no safety backup for a failed restore", got)
+	}
+}
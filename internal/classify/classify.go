@@ -0,0 +1,261 @@
+// Package classify recognises what kind of text a clipboard item holds, such
+// as a URL, a colour or a piece of source code.
+package classify
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Subtypes of text items. Plain text has no subtype.
+const (
+	URL    = "url"
+	Email  = "email"
+	Phone  = "phone"
+	Color  = "color"
+	JSON   = "json"
+	XML    = "xml"
+	HTML   = "html"
+	YAML   = "yaml"
+	Path   = "path"
+	Number = "number"
+	UUID   = "uuid"
+	IP     = "ip"
+	JWT    = "jwt"
+	Code   = "code"
+)
+
+// Subtypes lists all subtypes, in the order they are offered for filtering.
+var Subtypes = []string{URL, Email, Phone, Color, JSON, XML, HTML, YAML, Path, Number, UUID, IP, JWT, Code}
+
+// sampleSize bounds how much of a long text is scanned for code.
+const sampleSize = 64 * 1024
+
+var (
+	uuidPattern   = regexp.MustCompile(`^\{?[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\}?$`)
+	hexPattern    = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{4}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+	rgbPattern    = regexp.MustCompile(`^(?i)rgba?\(\s*\d{1,3}%?\s*[,\s]\s*\d{1,3}%?\s*[,\s]\s*\d{1,3}%?\s*(?:[,/]\s*[\d.]+%?\s*)?\)$`)
+	emailPattern  = regexp.MustCompile(`^(?i)(?:mailto:)?[a-z0-9._%+'-]+@[a-z0-9.-]+\.[a-z]{2,}$`)
+	numberPattern = regexp.MustCompile(`^[-+]?(?:(?:\d+|\d{1,3}(?:,\d{3})+)(?:\.\d+)?|\.\d+)(?:[eE][-+]?\d+)?$|^0[xX][0-9a-fA-F]+$`)
+	phonePattern  = regexp.MustCompile(`^\+?[\d\s().-]+$`)
+	windowsPath   = regexp.MustCompile(`^(?:[a-zA-Z]:\\|\\\\[^\\\s]+\\)[^<>:"|?*\n]*$`)
+	yamlLine      = regexp.MustCompile(`^\s*(?:-\s+)?(?:[\w.-]+|"[^"]*"|'[^']*'):(?:\s|$)|^\s*-(?:\s|$)|^\s+\S|^---$|^\.\.\.$`)
+)
+
+// Text returns the subtype of a text, or "" for plain text. For source
+// code, it also returns the language guessed, or "" if it is unclear.
+func Text(content string) (subtype, language string) {
+	text := strings.TrimSpace(content)
+	if text == "" {
+		return "", ""
+	}
+
+	if !strings.ContainsAny(text, "\r\n") {
+		if subtype := singleLine(text); subtype != "" {
+			return subtype, ""
+		}
+	}
+
+	switch {
+	case isJSON(text):
+		return JSON, ""
+	case isHTML(text):
+		return HTML, ""
+	case isXML(text):
+		return XML, ""
+	}
+
+	if language := guessLanguage(text); language != "" {
+		return Code, language
+	}
+	if isYAML(text) {
+		return YAML, ""
+	}
+
+	return "", ""
+}
+
+// singleLine recognises values that fit on one line.
+func singleLine(text string) string {
+	switch {
+	case uuidPattern.MatchString(text):
+		return UUID
+	case isIP(text):
+		return IP
+	case isJWT(text):
+		return JWT
+	case hexPattern.MatchString(text), rgbPattern.MatchString(text):
+		return Color
+	case emailPattern.MatchString(text):
+		return Email
+	case isURL(text):
+		return URL
+	case numberPattern.MatchString(text):
+		return Number
+	case isPhone(text):
+		return Phone
+	case isPath(text):
+		return Path
+	}
+	return ""
+}
+
+func isIP(text string) bool {
+	if _, err := netip.ParseAddr(text); err == nil {
+		return true
+	}
+	if _, err := netip.ParsePrefix(text); err == nil {
+		return true
+	}
+	_, err := netip.ParseAddrPort(text)
+	return err == nil
+}
+
+func isURL(text string) bool {
+	if strings.ContainsAny(text, " \t") {
+		return false
+	}
+	if strings.HasPrefix(strings.ToLower(text), "www.") {
+		text = "http://" + text
+	}
+
+	u, err := url.Parse(text)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https", "ftp", "ftps", "ws", "wss":
+		return u.Host != ""
+	case "file":
+		return u.Path != ""
+	}
+	return false
+}
+
+// isPhone accepts 7 to 15 digits with the usual separators, but not dates.
+func isPhone(text string) bool {
+	if !phonePattern.MatchString(text) {
+		return false
+	}
+	digits := 0
+	for _, r := range text {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	if digits < 7 || digits > 15 {
+		return false
+	}
+	for _, layout := range []string{"2006-01-02", "2006.01.02", "02.01.2006", "01-02-2006"} {
+		if _, err := time.Parse(layout, text); err == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func isPath(text string) bool {
+	if windowsPath.MatchString(text) {
+		return true
+	}
+	for _, prefix := range []string{"/", "~/", "./", "../"} {
+		if strings.HasPrefix(text, prefix) && len(text) > len(prefix) && !strings.HasPrefix(text, "//") {
+			return !strings.ContainsAny(text, "<>|*?\"")
+		}
+	}
+	return false
+}
+
+func isJSON(text string) bool {
+	if text[0] != '{' && text[0] != '[' {
+		return false
+	}
+	return json.Valid([]byte(text))
+}
+
+// htmlRoot matches markup whose first element, after any XML declaration
+// and comments, is an HTML one. HTML tags nested in other elements do not
+// count, as XML documents use names such as <a> and <p> too.
+var htmlRoot = regexp.MustCompile(`(?is)^(?:<\?xml.*?\?>\s*)?(?:<!--.*?-->\s*)*` +
+	`(?:<!doctype html|<(?:html|head|body|div|span|p|a|table|ul|ol|li|script|style|img|br|h[1-6])[\s>/])`)
+
+func isHTML(text string) bool {
+	return text[0] == '<' && text[len(text)-1] == '>' && htmlRoot.MatchString(text)
+}
+
+// isXML reports whether text is a well-formed XML document.
+func isXML(text string) bool {
+	if text[0] != '<' || text[len(text)-1] != '>' {
+		return false
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader([]byte(text)))
+	elements := 0
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return elements > 0
+		}
+		if err != nil {
+			return false
+		}
+		if _, ok := token.(xml.StartElement); ok {
+			elements++
+		}
+	}
+}
+
+// isYAML accepts mappings and lists of at least two lines, where every line
+// is a key, a list item, an indented continuation or a comment.
+func isYAML(text string) bool {
+	lines := 0
+	keys := 0
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if !yamlLine.MatchString(line) {
+			return false
+		}
+		if strings.Contains(trimmed, ":") || strings.HasPrefix(trimmed, "- ") {
+			keys++
+		}
+		lines++
+	}
+	return lines >= 2 && keys >= 2
+}
+
+// Label describes a subtype for display, for example "URL" or "Go code".
+func Label(subtype, language string) string {
+	switch subtype {
+	case "":
+		return "Text"
+	case URL, JSON, XML, HTML, YAML, UUID, JWT:
+		return strings.ToUpper(subtype)
+	case IP:
+		return "IP address"
+	case Phone:
+		return "Phone number"
+	case Color:
+		return "Colour"
+	case Path:
+		return "File path"
+	case Code:
+		if name := LanguageName(language); name != "" {
+			return name + " code"
+		}
+		return "Source code"
+	default:
+		return strings.ToUpper(subtype[:1]) + subtype[1:]
+	}
+}
@@ -0,0 +1,99 @@
+package classify
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		subtype  string
+		language string
+	}{
+		{"empty", "  \n", "", ""},
+		{"prose", "Meeting moved to Thursday afternoon.", "", ""},
+		{"url", "https://example.com/a?b=c", URL, ""},
+		{"www url", "www.example.com", URL, ""},
+		{"email", "someone@example.org", Email, ""},
+		{"mailto", "mailto:someone@example.org", Email, ""},
+		{"phone", "+44 20 7946 0958", Phone, ""},
+		{"date is not a phone", "2026-01-31", "", ""},
+		{"hex colour", "#ff8800", Color, ""},
+		{"rgb colour", "rgb(255, 136, 0)", Color, ""},
+		{"number", "1,234.5", Number, ""},
+		{"hex number", "0x1F", Number, ""},
+		{"uuid", "123e4567-e89b-12d3-a456-426614174000", UUID, ""},
+		{"ipv4", "192.168.1.1", IP, ""},
+		{"ipv6 prefix", "2001:db8::/32", IP, ""},
+		{"address and port", "10.0.0.1:8080", IP, ""},
+		{"jwt", "eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0.c2ln", JWT, ""},
+		{"unix path", "/usr/local/bin", Path, ""},
+		{"windows path", `C:\Users\someone\file.txt`, Path, ""},
+		{"json object", `{"a": [1, 2]}`, JSON, ""},
+		{"invalid json", `{"a": }`, "", ""},
+		{"html document", "<!DOCTYPE html><html><body>Hi</body></html>", HTML, ""},
+		{"html fragment", "<div class=\"x\"><p>Hi</p></div>", HTML, ""},
+		{"xml", "<?xml version=\"1.0\"?><note><to>A</to></note>", XML, ""},
+		{"xml with html-like children", "<root><a/></root>", XML, ""},
+		{"xml with p elements", "<doc>\n  <p>one</p>\n  <p>two</p>\n</doc>", XML, ""},
+		{"xhtml", "<?xml version=\"1.0\"?>\n<html><body/></html>", HTML, ""},
+		{"yaml", "name: app\nitems:\n  - one\n  - two", YAML, ""},
+		{"go", "package main\n\nfunc main() {\n\tx := 1\n}", Code, "go"},
+		{"python", "def add(a, b):\n    return a + b\n\nclass Point:\n    pass", Code, "python"},
+		{"javascript", "const x = 1;\nfunction f(a) {\n  console.log(a);\n}", Code, "javascript"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subtype, language := Text(tt.content)
+			if subtype != tt.subtype || language != tt.language {
+				t.Errorf("Text(%q) = %q, %q; want %q, %q", tt.content, subtype, language, tt.subtype, tt.language)
+			}
+		})
+	}
+}
+
+func TestLabel(t *testing.T) {
+	tests := []struct {
+		subtype, language, want string
+	}{
+		{"", "", "Text"},
+		{URL, "", "URL"},
+		{IP, "", "IP address"},
+		{Code, "go", "Go code"},
+		{Code, "", "Source code"},
+		{Number, "", "Number"},
+	}
+
+	for _, tt := range tests {
+		if got := Label(tt.subtype, tt.language); got != tt.want {
+			t.Errorf("Label(%q, %q) = %q, want %q", tt.subtype, tt.language, got, tt.want)
+		}
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    color.NRGBA
+		wantErr bool
+	}{
+		{"#f80", color.NRGBA{0xff, 0x88, 0x00, 0xff}, false},
+		{"#ff880080", color.NRGBA{0xff, 0x88, 0x00, 0x80}, false},
+		{"rgb(255, 136, 0)", color.NRGBA{0xff, 0x88, 0x00, 0xff}, false},
+		{"not a colour", color.NRGBA{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseColor(tt.text)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseColor(%q) error = %v, want error %v", tt.text, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParseColor(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package classify
+
+import (
+	"regexp"
+	"strings"
+)
+
+// language describes how to recognise source code in one language. Each
+// pattern found adds its weight to the language's score.
+type language struct {
+	id       string
+	name     string
+	patterns []weighted
+}
+
+type weighted struct {
+	pattern *regexp.Regexp
+	weight  int
+}
+
+func w(weight int, pattern string) weighted {
+	return weighted{regexp.MustCompile(`(?m)` + pattern), weight}
+}
+
+// minCodeScore is the score a text needs to be taken for code. It is set so
+// that a single characteristic keyword is not enough.
+const minCodeScore = 4
+
+var languages = []language{
+	{"go", "Go", []weighted{
+		w(4, `^package \w+$`),
+		w(3, `^func (?:\(\w+ \*?\w+\) )?\w+\(`),
+		w(2, `:= `),
+		w(2, `^import \($`),
+		w(2, `\bif err != nil \{`),
+		w(1, `\bfmt\.\w+\(`),
+	}},
+	{"python", "Python", []weighted{
+		w(3, `^\s*def \w+\(.*\)(?:\s*->\s*[\w\[\], .]+)?:\s*$`),
+		w(3, `^\s*class \w+(?:\(.*\))?:\s*$`),
+		w(2, `^(?:from [\w.]+ )?import \w+`),
+		w(2, `^\s*(?:if|elif|for|while|with|try|except.*)\b.*:\s*$`),
+		w(2, `\bself\.\w+`),
+		w(1, `\bprint\(`),
+		w(1, `\bNone\b|\bTrue\b|\bFalse\b`),
+	}},
+	{"javascript", "JavaScript", []weighted{
+		w(2, `\b(?:const|let|var) \w+ = `),
+		w(3, `\bfunction\s*\w*\s*\([^)]*\)\s*\{`),
+		w(2, `=>\s*[{(]?`),
+		w(2, `\bconsole\.log\(`),
+		w(2, `\brequire\(['"]|\bimport .+ from ['"]|\bexport (?:default|const|function)\b`),
+		w(1, `===|!==`),
+		w(1, `\bdocument\.\w+|\bwindow\.\w+`),
+	}},
+	{"typescript", "TypeScript", []weighted{
+		w(3, `\binterface \w+ \{`),
+		w(3, `\b(?:const|let|var) \w+: \w+`),
+		w(2, `\bfunction \w+\([^)]*: \w+`),
+		w(2, `\):\s*\w+(?:<[^>]+>)?\s*\{`),
+		w(2, `\btype \w+ = `),
+		w(1, `\bimport .+ from ['"]|\bexport (?:default|const|function|interface|type)\b`),
+	}},
+	{"java", "Java", []weighted{
+		w(3, `\bpublic (?:static )?(?:final )?(?:class|interface|enum) \w+`),
+		w(3, `\bpublic static void main\(`),
+		w(2, `\b(?:private|protected|public) (?:static )?(?:final )?[\w<>\[\]]+ \w+\s*[(;=]`),
+		w(2, `^import java\.`),
+		w(2, `\bSystem\.out\.print`),
+		w(1, `@Override\b`),
+	}},
+	{"csharp", "C#", []weighted{
+		w(4, `^using System(?:\.\w+)*;`),
+		w(3, `^\s*namespace [\w.]+`),
+		w(2, `\bConsole\.Write(?:Line)?\(`),
+		w(2, `\{ get; (?:private )?set; \}`),
+		w(2, `\b(?:public|private|internal) (?:static )?(?:async )?(?:void|Task|string|int|bool) \w+\(`),
+	}},
+	{"c", "C", []weighted{
+		w(4, `^#include <\w+\.h>`),
+		w(2, `\bint main\(`),
+		w(2, `\bprintf\(`),
+		w(2, `\bmalloc\(|\bfree\(|\bsizeof\(`),
+		w(1, `^#define \w+`),
+		w(1, `\bstruct \w+ \{`),
+	}},
+	{"cpp", "C++", []weighted{
+		w(4, `^#include <\w+>`),
+		w(3, `\bstd::\w+`),
+		w(2, `\bcout\s*<<|\bcin\s*>>`),
+		w(2, `\btemplate\s*<`),
+		w(2, `^using namespace \w+;`),
+		w(1, `\bclass \w+ (?::\s*public \w+ )?\{`),
+	}},
+	{"rust", "Rust", []weighted{
+		w(3, `\bfn \w+(?:<[^>]*>)?\([^)]*\)(?:\s*->\s*[^{]+)?\s*\{`),
+		w(3, `\blet mut \w+`),
+		w(2, `\bimpl(?:<[^>]*>)? \w+`),
+		w(2, `^use \w+(?:::\w+)+`),
+		w(2, `\bprintln!\(|\bvec!\[`),
+		w(1, `\bpub (?:fn|struct|enum)\b`),
+		w(1, `&mut \w+|Option<|Result<`),
+	}},
+	{"ruby", "Ruby", []weighted{
+		w(3, `^\s*def \w+[?!]?(?:\(.*\))?\s*$`),
+		w(2, `^\s*end$`),
+		w(2, `^require ['"]`),
+		w(2, `\bputs\b`),
+		w(2, `\.each do \|\w+\|`),
+		w(1, `@\w+ = `),
+	}},
+	{"php", "PHP", []weighted{
+		w(5, `^<\?php`),
+		w(2, `\$\w+\s*=`),
+		w(2, `\bfunction \w+\(\$`),
+		w(2, `\becho\b`),
+		w(1, `->\w+\(`),
+	}},
+	{"swift", "Swift", []weighted{
+		w(3, `^import (?:UIKit|Foundation|SwiftUI)$`),
+		w(3, `\bfunc \w+\([^)]*\)(?:\s*->\s*\w+)?\s*\{`),
+		w(2, `\b(?:let|var) \w+: \w+`),
+		w(2, `\bguard let\b|\bif let\b`),
+	}},
+	{"kotlin", "Kotlin", []weighted{
+		w(3, `\bfun \w+\(`),
+		w(2, `\bval \w+\s*[=:]`),
+		w(2, `^package [\w.]+$`),
+		w(1, `\bprintln\(`),
+		w(1, `\bdata class\b`),
+	}},
+	{"shell", "Shell", []weighted{
+		w(5, `^#!/(?:usr/)?bin/(?:env )?(?:ba|z)?sh`),
+		w(2, `^\s*(?:sudo|apt(?:-get)?|brew|npm|yarn|pip3?|git|docker|kubectl|cd|ls|mkdir|rm|cp|mv|chmod|curl|wget|export|echo)\s`),
+		w(2, `\$\{?\w+\}?`),
+		w(2, `\bfi$|\bdone$|\besac$`),
+		w(1, ` \|\s*(?:grep|sed|awk|xargs|sort|head|tail)\b`),
+		w(1, ` && | \|\| `),
+	}},
+	{"sql", "SQL", []weighted{
+		w(3, `(?i)\bSELECT\b[\s\S]+?\bFROM\b`),
+		w(3, `(?i)\bINSERT INTO\b|\bUPDATE \w+ SET\b|\bDELETE FROM\b`),
+		w(3, `(?i)\bCREATE (?:TABLE|INDEX|VIEW)\b|\bALTER TABLE\b`),
+		w(2, `(?i)\bWHERE\b|\bJOIN\b|\bGROUP BY\b|\bORDER BY\b`),
+	}},
+	{"css", "CSS", []weighted{
+		w(3, `^\s*[.#]?[\w-]+(?:[\s>+~,.#:][\w-]*)*\s*\{\s*$`),
+		w(3, `^\s*[\w-]+:\s*[^;{}]+;\s*$`),
+		w(2, `@media\b|@import\b|@keyframes\b`),
+		w(1, `\b\d+(?:px|em|rem|vh|vw)\b`),
+	}},
+}
+
+// guessLanguage returns the language a text is most likely written in, or
+// "" if it does not look like code.
+func guessLanguage(text string) string {
+	if len(text) > sampleSize {
+		text = text[:sampleSize]
+	}
+
+	best, bestScore := "", 0
+	for _, lang := range languages {
+		score := 0
+		for _, p := range lang.patterns {
+			if p.pattern.MatchString(text) {
+				score += p.weight
+			}
+		}
+		if score > bestScore {
+			best, bestScore = lang.id, score
+		}
+	}
+
+	if bestScore < minCodeScore || !looksLikeCode(text) {
+		return ""
+	}
+	return best
+}
+
+// looksLikeCode rejects prose that happens to contain a keyword or two, by
+// requiring a share of lines to contain code punctuation.
+func looksLikeCode(text string) bool {
+	lines, codeLines := 0, 0
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		lines++
+		if strings.ContainsAny(line, "{}();=<>[]$:#") {
+			codeLines++
+		}
+	}
+	return lines > 0 && codeLines*2 >= lines
+}
+
+// LanguageName returns the display name of a language, or "" if unknown.
+func LanguageName(id string) string {
+	for _, lang := range languages {
+		if lang.id == id {
+			return lang.name
+		}
+	}
+	return ""
+}
+
+// Languages returns the identifiers of the languages that can be guessed.
+func Languages() []string {
+	ids := make([]string, len(languages))
+	for i, lang := range languages {
+		ids[i] = lang.id
+	}
+	return ids
+}
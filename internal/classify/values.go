@@ -0,0 +1,129 @@
+package classify
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var jwtPattern = regexp.MustCompile(`^eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*$`)
+
+// isJWT reports whether text is a JSON Web Token with a readable header.
+func isJWT(text string) bool {
+	if !jwtPattern.MatchString(text) {
+		return false
+	}
+	header, _, err := DecodeJWT(text)
+	if err != nil {
+		return false
+	}
+	var fields map[string]any
+	if json.Unmarshal(header, &fields) != nil {
+		return false
+	}
+	_, ok := fields["alg"]
+	return ok
+}
+
+// DecodeJWT returns the JSON header and payload of a JSON Web Token. The
+// signature is not verified.
+func DecodeJWT(token string) (header, payload []byte, err error) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return nil, nil, errors.New("a JWT has three parts separated by dots")
+	}
+
+	if header, err = decodeSegment(parts[0]); err != nil {
+		return nil, nil, fmt.Errorf("invalid JWT header: %w", err)
+	}
+	if payload, err = decodeSegment(parts[1]); err != nil {
+		return nil, nil, fmt.Errorf("invalid JWT payload: %w", err)
+	}
+	return header, payload, nil
+}
+
+func decodeSegment(segment string) ([]byte, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(segment, "="))
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(data) {
+		return nil, errors.New("not JSON")
+	}
+	return data, nil
+}
+
+var rgbValues = regexp.MustCompile(`[\d.]+%?`)
+
+// ParseColor returns the colour of a text recognised as Color, such as
+// "#ff8800", "#f80c" or "rgba(255, 136, 0, 0.5)".
+func ParseColor(text string) (color.NRGBA, error) {
+	text = strings.TrimSpace(text)
+	switch {
+	case hexPattern.MatchString(text):
+		return parseHexColor(text[1:])
+	case rgbPattern.MatchString(text):
+		return parseRGBColor(text)
+	}
+	return color.NRGBA{}, fmt.Errorf("%q is not a colour", text)
+}
+
+func parseHexColor(hex string) (color.NRGBA, error) {
+	if len(hex) <= 4 {
+		// Short forms repeat each digit: #f80 is #ff8800
+		var long strings.Builder
+		for _, r := range hex {
+			long.WriteRune(r)
+			long.WriteRune(r)
+		}
+		hex = long.String()
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, err
+	}
+	return color.NRGBA{R: uint8(value >> 24), G: uint8(value >> 16), B: uint8(value >> 8), A: uint8(value)}, nil
+}
+
+func parseRGBColor(text string) (color.NRGBA, error) {
+	values := rgbValues.FindAllString(text, -1)
+	c := color.NRGBA{A: 255}
+	channels := []*uint8{&c.R, &c.G, &c.B, &c.A}
+	for i, value := range values {
+		if i == len(channels) {
+			break
+		}
+
+		limit := 255.0
+		if i == 3 {
+			limit = 1 // Alpha is a fraction
+		}
+		number, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+		if err != nil {
+			return color.NRGBA{}, err
+		}
+		if strings.HasSuffix(value, "%") {
+			number = number / 100 * limit
+		}
+		*channels[i] = uint8(min(max(number, 0), limit) / limit * 255)
+	}
+	return c, nil
+}
+
+// FormatColor writes a colour in hex and rgb() notation, for display.
+func FormatColor(c color.NRGBA) (hex, rgb string) {
+	if c.A == 255 {
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B), fmt.Sprintf("rgb(%d, %d, %d)", c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A),
+		fmt.Sprintf("rgba(%d, %d, %d, %.2g)", c.R, c.G, c.B, float64(c.A)/255)
+}
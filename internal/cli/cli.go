@@ -0,0 +1,112 @@
+// Package cli implements the command-line subcommands that run without
+// starting the user interface.
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/term"
+
+	"clipboardpro/internal/config"
+	"clipboardpro/internal/database"
+)
+
+type command struct {
+	summary string
+	run     func(ctx context.Context, args []string) error
+}
+
+var commands = map[string]command{
+	"export": {"Export clipboard history to a file", runExport},
+	"import": {"Import clipboard history from an export or another clipboard manager", runImport},
+}
+
+// IsCommand reports whether name is a known subcommand.
+func IsCommand(name string) bool {
+	_, ok := commands[name]
+	return ok
+}
+
+// Run executes the subcommand named by args[0].
+func Run(ctx context.Context, args []string) error {
+	if len(args) == 0 || !IsCommand(args[0]) {
+		usage()
+		return fmt.Errorf("unknown command")
+	}
+
+	err := commands[args[0]].run(ctx, args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return nil // Usage has already been printed
+	}
+	return err
+}
+
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(os.Stderr, "Usage: clipboardpro [command] [flags]")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintln(os.Stderr, "\nRun without a command to start the application, or with -ephemeral to keep")
+	fmt.Fprintln(os.Stderr, "its history in memory only.")
+}
+
+// passphraseEnv names the environment variable holding the passphrase of an
+// encrypted history, for scripts. Otherwise it is asked for on the terminal.
+const passphraseEnv = "CLIPBOARDPRO_PASSPHRASE"
+
+// openRepository opens the database used by the application, unlocking it
+// if it is encrypted.
+func openRepository() (database.Store, error) {
+	configDir, err := config.Dir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config directory: %w", err)
+	}
+
+	repository, err := database.NewRepository(filepath.Join(configDir, "clipboard.db"))
+	if err != nil {
+		return nil, err
+	}
+
+	if repository.IsLocked() {
+		if err := unlock(repository); err != nil {
+			repository.Close()
+			return nil, err
+		}
+	}
+	return repository, nil
+}
+
+func unlock(repository database.Store) error {
+	passphrase, ok := os.LookupEnv(passphraseEnv)
+	if !ok {
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			return fmt.Errorf("history is encrypted: set %s to its passphrase", passphraseEnv)
+		}
+
+		fmt.Fprint(os.Stderr, "Passphrase: ")
+		input, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return fmt.Errorf("failed to read passphrase: %w", err)
+		}
+		passphrase = string(input)
+	}
+
+	if err := repository.Unlock(passphrase); err != nil {
+		return fmt.Errorf("failed to unlock history: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"clipboardpro/internal/database"
+	"clipboardpro/internal/export"
+)
+
+const dateLayout = "2006-01-02"
+
+func runExport(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := flags.String("format", string(export.FormatJSONL), "output format: jsonl, csv, markdown or zip")
+	out := flags.String("out", "", "output file (default clipboard-history-<date>.<ext>)")
+	images := flags.String("images", string(export.ImagesInline), "image handling for jsonl and markdown: base64, files or none")
+	from := flags.String("from", "", "only items copied on or after this date (YYYY-MM-DD)")
+	to := flags.String("to", "", "only items copied on or before this date (YYYY-MM-DD)")
+	itemType := flags.String("type", "", "only items of this type: text or image")
+	tags := flags.String("tag", "", "only items with these comma-separated tags")
+	pinned := flags.Bool("pinned", false, "only pinned items")
+	sensitive := flags.Bool("include-sensitive", false, "include sensitive items: JWTs, expiring or delete-after-copy items and items tagged sensitive")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	opts := export.Options{
+		Format: export.Format(*format),
+		Images: export.ImageMode(*images),
+		Filter: database.ExportFilter{
+			Type:             *itemType,
+			Tags:             *tags,
+			PinnedOnly:       *pinned,
+			IncludeSensitive: *sensitive,
+		},
+	}
+
+	var err error
+	if opts.Filter.From, err = parseDate(*from); err != nil {
+		return fmt.Errorf("invalid -from date: %w", err)
+	}
+	if opts.Filter.To, err = parseDate(*to); err != nil {
+		return fmt.Errorf("invalid -to date: %w", err)
+	}
+	if !opts.Filter.To.IsZero() {
+		opts.Filter.To = opts.Filter.To.AddDate(0, 0, 1) // Include the whole day
+	}
+
+	path := *out
+	if path == "" {
+		path = export.DefaultFileName(opts.Format, time.Now())
+	}
+
+	repository, err := openRepository()
+	if err != nil {
+		return err
+	}
+	defer repository.Close()
+
+	result, err := export.Export(ctx, repository, path, opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported %d items (%d images) to %s\n", result.Items, result.Images, result.Path)
+	return nil
+}
+
+func parseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation(dateLayout, value, time.Local)
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"clipboardpro/internal/importer"
+)
+
+func runImport(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	from := flags.String("from", "clipboardpro", "where the history comes from: "+sourceIDs())
+	policy := flags.String("policy", string(importer.KeepNewest), "what to do with items that already exist: "+policyNames())
+	dryRun := flags.Bool("dry-run", false, "show what would be imported without changing anything")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: clipboardpro import [flags] [FILE]")
+		fmt.Fprintln(os.Stderr, "\nFILE may be left out for sources with a usual location.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	source, ok := importer.Lookup(*from)
+	if !ok {
+		return fmt.Errorf("unknown source %q, expected one of: %s", *from, sourceIDs())
+	}
+	if !slices.Contains(importer.Policies, importer.Policy(*policy)) {
+		return fmt.Errorf("unknown policy %q, expected one of: %s", *policy, policyNames())
+	}
+
+	var path string
+	switch flags.NArg() {
+	case 0:
+		if path = source.DefaultPath(); path == "" {
+			flags.Usage()
+			return fmt.Errorf("no %s history found, give the file to import", source.Name())
+		}
+	case 1:
+		path = flags.Arg(0)
+	default:
+		flags.Usage()
+		return fmt.Errorf("expected one file to import")
+	}
+
+	repository, err := openRepository()
+	if err != nil {
+		return err
+	}
+	defer repository.Close()
+
+	plan, err := importer.PlanSource(ctx, repository, source, path, importer.Policy(*policy))
+	if err != nil {
+		return err
+	}
+
+	if *dryRun {
+		fmt.Println(plan.Summary)
+		fmt.Println("Dry run, nothing was imported.")
+		return nil
+	}
+
+	report, err := plan.Apply(ctx, repository)
+	if err != nil {
+		return err
+	}
+	fmt.Println(report)
+	return nil
+}
+
+func sourceIDs() string {
+	var ids []string
+	for _, source := range importer.Sources() {
+		ids = append(ids, source.ID())
+	}
+	return strings.Join(ids, ", ")
+}
+
+func policyNames() string {
+	names := make([]string, len(importer.Policies))
+	for i, p := range importer.Policies {
+		names[i] = string(p)
+	}
+	return strings.Join(names, ", ")
+}
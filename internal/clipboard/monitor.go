@@ -2,31 +2,48 @@ package clipboard
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"sync/atomic"
 	"time"
 
 	"golang.design/x/clipboard"
 
 	"clipboardpro/internal/config"
 	"clipboardpro/internal/database"
+	"clipboardpro/internal/placeholder"
+	"clipboardpro/internal/sourceapp"
 	"clipboardpro/internal/util"
 )
 
+// ErrCopyCancelled is returned when the user cancels a template prompt.
+var ErrCopyCancelled = errors.New("copy cancelled")
+
 type Monitor struct {
-	repository *database.Repository
-	config     *config.Config
-	lastHash   string
+	repository database.Store
+	config     atomic.Pointer[config.Config]
+	lastHash   atomic.Pointer[string] // Written by copies as well as the monitor loop
 	eventChan  chan MonitorEvent
 	isRunning  bool
+	paused     atomic.Bool
+
+	templateResolver TemplateResolver
 }
 
-func NewMonitor(repository *database.Repository, config *config.Config) *Monitor {
-	return &Monitor{
+func NewMonitor(repository database.Store, config *config.Config) *Monitor {
+	m := &Monitor{
 		repository: repository,
-		config:     config,
 		eventChan:  make(chan MonitorEvent, 100),
 	}
+	m.config.Store(config)
+	return m
+}
+
+// SetConfig replaces the configuration, for example after the settings
+// have been saved.
+func (m *Monitor) SetConfig(config *config.Config) {
+	m.config.Store(config)
 }
 
 func (m *Monitor) Start(ctx context.Context) error {
@@ -58,8 +75,26 @@ func (m *Monitor) Stop() {
 	log.Println("Clipboard monitor stopped")
 }
 
+// Pause stops saving clipboard changes until Resume is called, for example
+// while a backup is restored.
+func (m *Monitor) Pause() {
+	m.paused.Store(true)
+	log.Println("Clipboard monitor paused")
+}
+
+// Resume continues saving clipboard changes after Pause.
+func (m *Monitor) Resume() {
+	m.paused.Store(false)
+	log.Println("Clipboard monitor resumed")
+}
+
+// IsPaused reports whether the monitor is paused.
+func (m *Monitor) IsPaused() bool {
+	return m.paused.Load()
+}
+
 func (m *Monitor) monitorLoop(ctx context.Context) {
-	ticker := time.NewTicker(time.Duration(m.config.MonitorInterval) * time.Millisecond)
+	ticker := time.NewTicker(time.Duration(m.config.Load().MonitorInterval) * time.Millisecond)
 	defer ticker.Stop()
 
 	for {
@@ -67,7 +102,9 @@ func (m *Monitor) monitorLoop(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			m.checkClipboard(ctx)
+			if !m.paused.Load() {
+				m.checkClipboard(ctx)
+			}
 		}
 	}
 }
@@ -99,9 +136,11 @@ func (m *Monitor) checkClipboard(ctx context.Context) {
 }
 
 func (m *Monitor) processClipboardData(ctx context.Context, data *ClipboardData) {
+	cfg := m.config.Load()
+
 	// Check size limit
-	if data.Size > m.config.MaxItemSize {
-		log.Printf("Clipboard item too large: %d bytes (max: %d)", data.Size, m.config.MaxItemSize)
+	if data.Size > cfg.MaxItemSize {
+		log.Printf("Clipboard item too large: %d bytes (max: %d)", data.Size, cfg.MaxItemSize)
 		return
 	}
 
@@ -109,12 +148,10 @@ func (m *Monitor) processClipboardData(ctx context.Context, data *ClipboardData)
 	hash := util.GenerateHash(data.Content, data.ImageData)
 
 	// Skip if same as last item
-	if hash == m.lastHash {
+	if last := m.lastHash.Swap(&hash); last != nil && *last == hash {
 		return
 	}
 
-	m.lastHash = hash
-
 	// Create database item
 	item := &database.ClipboardItem{
 		Type:      data.Type,
@@ -123,6 +160,11 @@ func (m *Monitor) processClipboardData(ctx context.Context, data *ClipboardData)
 		Size:      data.Size,
 		Hash:      hash,
 		Timestamp: data.Timestamp,
+		SourceApp: sourceapp.Frontmost(ctx),
+	}
+	if rule, ok := cfg.ExpiryRuleFor(data.Type, data.Content); ok {
+		item.ExpiresAt = rule.ExpiresAt(data.Timestamp)
+		item.BurnAfterCopy = rule.BurnAfterCopy
 	}
 
 	// Save to database
@@ -150,9 +192,19 @@ func (m *Monitor) CopyItemToClipboard(ctx context.Context, id int64) error {
 		return fmt.Errorf("failed to get item: %w", err)
 	}
 
+	hash := item.Hash
+
 	switch item.Type {
 	case "text":
-		clipboard.Write(clipboard.FmtText, []byte(item.Content))
+		content := item.Content
+		if placeholder.Has(content) {
+			content, err = m.expandTemplate(content)
+			if err != nil {
+				return err
+			}
+			hash = util.GenerateHash(content, nil)
+		}
+		clipboard.Write(clipboard.FmtText, []byte(content))
 	case "image":
 		if len(item.ImageData) > 0 {
 			clipboard.Write(clipboard.FmtImage, item.ImageData)
@@ -162,12 +214,62 @@ func (m *Monitor) CopyItemToClipboard(ctx context.Context, id int64) error {
 	}
 
 	// Update the hash to current so we don't re-capture this item
-	m.lastHash = item.Hash
+	m.lastHash.Store(&hash)
+
+	if item.BurnAfterCopy {
+		if err := m.repository.BurnItem(ctx, id); err != nil {
+			return err
+		}
+	} else if err := m.repository.RecordUsage(ctx, id); err != nil {
+		log.Printf("Failed to record item usage: %v", err)
+	}
 
 	log.Printf("Copied item to clipboard: %s", item.Type)
 	return nil
 }
 
+// CopyTextToClipboard puts text on the clipboard without saving it to the
+// history, for example the result of a transform.
+func (m *Monitor) CopyTextToClipboard(text string) {
+	clipboard.Write(clipboard.FmtText, []byte(text))
+	hash := util.GenerateHash(text, nil)
+	m.lastHash.Store(&hash)
+}
+
+// SetTemplateResolver sets the resolver used to prompt for template input and
+// to preview expanded text. Without one, inputs expand to empty strings.
+func (m *Monitor) SetTemplateResolver(resolver TemplateResolver) {
+	m.templateResolver = resolver
+}
+
+func (m *Monitor) expandTemplate(content string) (string, error) {
+	env := placeholder.Env{
+		Now: time.Now(),
+		Clipboard: func() string {
+			return string(clipboard.Read(clipboard.FmtText))
+		},
+	}
+
+	if m.templateResolver == nil {
+		return placeholder.Expand(content, env), nil
+	}
+
+	if labels := placeholder.Prompts(content); len(labels) > 0 {
+		inputs, ok := m.templateResolver.ResolveInputs(labels)
+		if !ok {
+			return "", ErrCopyCancelled
+		}
+		env.Inputs = inputs
+	}
+
+	expanded, ok := m.templateResolver.ConfirmExpansion(placeholder.Expand(content, env))
+	if !ok {
+		return "", ErrCopyCancelled
+	}
+
+	return expanded, nil
+}
+
 func (m *Monitor) EventChannel() <-chan MonitorEvent {
 	return m.eventChan
 }
@@ -18,3 +18,11 @@ type MonitorEvent struct {
 	Data  *ClipboardData
 	Error error
 }
+
+// TemplateResolver collects input for template placeholders and confirms the
+// expanded text before it is written to the clipboard. Both methods block
+// until the user responds and report false when the copy was cancelled.
+type TemplateResolver interface {
+	ResolveInputs(labels []string) (map[string]string, bool)
+	ConfirmExpansion(expanded string) (string, bool)
+}
@@ -5,14 +5,74 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
 )
 
+// RetentionRule limits how long unpinned items are kept. A rule applies to
+// the items that match all of its conditions, and each item is governed by
+// the first rule that matches it. Zero limits are off.
+type RetentionRule struct {
+	Type      string `json:"type"`                 // "text", "image", or empty for any type
+	Tag       string `json:"tag,omitempty"`        // Tag the items must have, or empty for any
+	SourceApp string `json:"source_app,omitempty"` // Application the items were copied from, or empty for any
+	Sensitive bool   `json:"sensitive,omitempty"`  // Only sensitive items, such as JWTs and expiring items
+
+	MaxAgeDays int   `json:"max_age_days"`
+	MaxCount   int   `json:"max_count"`
+	MaxBytes   int64 `json:"max_bytes"` // Total size of the matching items
+}
+
+// Matches reports whether the rule applies to an item of the given type,
+// tags and source application, which is sensitive or not.
+func (r RetentionRule) Matches(itemType string, tags []string, sourceApp string, sensitive bool) bool {
+	return (r.Type == "" || r.Type == itemType) &&
+		(r.Tag == "" || slices.Contains(tags, r.Tag)) &&
+		(r.SourceApp == "" || strings.EqualFold(r.SourceApp, sourceApp)) &&
+		(!r.Sensitive || sensitive)
+}
+
+// ExpiryRule gives newly captured items matching it an expiry time or
+// deletes them after their first copy. The first matching rule applies.
+type ExpiryRule struct {
+	Type          string `json:"type"`           // "text", "image", or empty for any type
+	Pattern       string `json:"pattern"`        // Regular expression the text must match, or empty for any
+	ExpireMinutes int    `json:"expire_minutes"` // 0 for no expiry
+	BurnAfterCopy bool   `json:"burn_after_copy"`
+}
+
+// Matches reports whether the rule applies to an item. Rules with an
+// invalid pattern match nothing.
+func (r ExpiryRule) Matches(itemType, content string) bool {
+	if r.Type != "" && r.Type != itemType {
+		return false
+	}
+	if r.Pattern == "" {
+		return true
+	}
+	re, err := regexp.Compile(r.Pattern)
+	return err == nil && re.MatchString(content)
+}
+
+// ExpiresAt returns when an item captured at captured expires under the
+// rule, or the zero time if it does not.
+func (r ExpiryRule) ExpiresAt(captured time.Time) time.Time {
+	if r.ExpireMinutes <= 0 {
+		return time.Time{}
+	}
+	return captured.Add(time.Duration(r.ExpireMinutes) * time.Minute)
+}
+
 type Config struct {
-	MaxHistoryItems   int  `json:"max_history_items"`
-	MaxHistoryDays    int  `json:"max_history_days"`
-	StartWithSystem   bool `json:"start_with_system"`
-	ShowNotifications bool `json:"show_notifications"`
-	DarkMode          bool `json:"dark_mode"`
+	RetentionRules     []RetentionRule `json:"retention_rules"`
+	StorageQuotaMB     int             `json:"storage_quota_mb"` // 0 for no quota
+	TrashRetentionDays int             `json:"trash_retention_days"`
+	ExpiryRules        []ExpiryRule    `json:"expiry_rules"`
+	StartWithSystem    bool            `json:"start_with_system"`
+	ShowNotifications  bool            `json:"show_notifications"`
+	DarkMode           bool            `json:"dark_mode"`
 
 	MonitorInterval int `json:"monitor_interval_ms"`
 	MaxItemSize     int `json:"max_item_size_bytes"`
@@ -20,21 +80,75 @@ type Config struct {
 	// Update settings
 	CheckUpdatesOnStartup bool `json:"check_updates_on_startup"`
 	AutoDownloadUpdates   bool `json:"auto_download_updates"`
+
+	// Backup settings
+	BackupEnabled       bool `json:"backup_enabled"`
+	BackupIntervalHours int  `json:"backup_interval_hours"`
+	BackupKeepCount     int  `json:"backup_keep_count"`
+	BackupKeepDays      int  `json:"backup_keep_days"` // 0 keeps backups regardless of age
+
+	// App lock settings
+	LockEnabled     bool   `json:"lock_enabled"`
+	LockIdleMinutes int    `json:"lock_idle_minutes"` // 0 disables locking when idle
+	LockOnFocusLoss bool   `json:"lock_on_focus_loss"`
+	LockSecretHash  string `json:"lock_secret_hash"` // Argon2id hash of the PIN or passphrase
+}
+
+// ExpiryRuleFor returns the first expiry rule matching an item, if any.
+func (c *Config) ExpiryRuleFor(itemType, content string) (ExpiryRule, bool) {
+	for _, rule := range c.ExpiryRules {
+		if rule.Matches(itemType, content) {
+			return rule, true
+		}
+	}
+	return ExpiryRule{}, false
+}
+
+// Dir returns the directory holding the configuration and database,
+// creating it if needed.
+func Dir() (string, error) {
+	configDir, err := DirPath()
+	if err != nil {
+		return "", err
+	}
+	return configDir, os.MkdirAll(configDir, 0755)
+}
+
+// DirPath returns the directory Dir returns, without creating it.
+func DirPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".clipboardpro"), nil
 }
 
 func Default() *Config {
 	return &Config{
-		MaxHistoryItems:   1000,
-		MaxHistoryDays:    30,
-		StartWithSystem:   true,
-		ShowNotifications: true,
-		DarkMode:          false,
+		RetentionRules: []RetentionRule{
+			{Type: "image", MaxAgeDays: 30, MaxCount: 200, MaxBytes: 200 * 1024 * 1024},
+			{MaxAgeDays: 30, MaxCount: 1000},
+		},
+		StorageQuotaMB:     0,
+		TrashRetentionDays: 30,
+		StartWithSystem:    true,
+		ShowNotifications:  true,
+		DarkMode:           false,
 
 		MonitorInterval: 500,
 		MaxItemSize:     10 * 1024 * 1024, // 10MB
 
 		CheckUpdatesOnStartup: true,
 		AutoDownloadUpdates:   false,
+
+		BackupEnabled:       true,
+		BackupIntervalHours: 24,
+		BackupKeepCount:     7,
+		BackupKeepDays:      30,
+
+		LockEnabled:     false,
+		LockIdleMinutes: 5,
+		LockOnFocusLoss: false,
 	}
 }
 
@@ -52,6 +166,9 @@ func Load(path string) (*Config, error) {
 	if err := json.Unmarshal(data, config); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
+	if err := config.migrateHistoryLimits(data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
 
 	config.validate()
 
@@ -76,12 +193,42 @@ func (c *Config) Save(path string) error {
 	return nil
 }
 
+// migrateHistoryLimits turns the single day and item limits of older
+// configs into a retention rule for all items.
+func (c *Config) migrateHistoryLimits(data []byte) error {
+	var legacy struct {
+		RetentionRules  *[]RetentionRule `json:"retention_rules"`
+		MaxHistoryItems int              `json:"max_history_items"`
+		MaxHistoryDays  int              `json:"max_history_days"`
+	}
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return err
+	}
+	if legacy.RetentionRules != nil || (legacy.MaxHistoryItems == 0 && legacy.MaxHistoryDays == 0) {
+		return nil
+	}
+
+	c.RetentionRules = []RetentionRule{{MaxAgeDays: legacy.MaxHistoryDays, MaxCount: legacy.MaxHistoryItems}}
+	return nil
+}
+
 func (c *Config) validate() {
-	if c.MaxHistoryItems <= 0 {
-		c.MaxHistoryItems = 1000
+	for i := range c.RetentionRules {
+		rule := &c.RetentionRules[i]
+		rule.MaxAgeDays = max(rule.MaxAgeDays, 0)
+		rule.MaxCount = max(rule.MaxCount, 0)
+		rule.MaxBytes = max(rule.MaxBytes, 0)
+		rule.Tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(rule.Tag), "#"))
+		rule.SourceApp = strings.TrimSpace(rule.SourceApp)
+	}
+	for i := range c.ExpiryRules {
+		c.ExpiryRules[i].ExpireMinutes = max(c.ExpiryRules[i].ExpireMinutes, 0)
+	}
+	if c.StorageQuotaMB < 0 {
+		c.StorageQuotaMB = 0
 	}
-	if c.MaxHistoryDays <= 0 {
-		c.MaxHistoryDays = 30
+	if c.TrashRetentionDays <= 0 {
+		c.TrashRetentionDays = 30
 	}
 	if c.MonitorInterval <= 0 {
 		c.MonitorInterval = 500
@@ -89,4 +236,19 @@ func (c *Config) validate() {
 	if c.MaxItemSize <= 0 {
 		c.MaxItemSize = 10 * 1024 * 1024
 	}
+	if c.BackupIntervalHours <= 0 {
+		c.BackupIntervalHours = 24
+	}
+	if c.BackupKeepCount <= 0 {
+		c.BackupKeepCount = 7
+	}
+	if c.BackupKeepDays < 0 {
+		c.BackupKeepDays = 0
+	}
+	if c.LockIdleMinutes < 0 {
+		c.LockIdleMinutes = 0
+	}
+	if c.LockSecretHash == "" {
+		c.LockEnabled = false
+	}
 }
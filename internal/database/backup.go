@@ -0,0 +1,192 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/driver/sqliteshim"
+)
+
+// backupTables are the tables copied back when restoring a backup.
+var backupTables = []string{"clipboard_items", "item_revisions", "meta"}
+
+// BackupTo writes a consistent snapshot of the database to path, which must
+// not exist yet.
+func (r *Repository) BackupTo(ctx context.Context, path string) error {
+	if _, err := r.db.ExecContext(ctx, "VACUUM INTO ?", path); err != nil {
+		return fmt.Errorf("failed to back up database: %w", err)
+	}
+	return nil
+}
+
+// RestoreFrom replaces the contents of the database with those of a backup
+// in a single transaction. Backups made by older versions are accepted:
+// columns they lack get their defaults and are backfilled as in a migration.
+//
+// The backup's encryption state is restored with it. If it was encrypted
+// with a different passphrase, the repository is left locked.
+func (r *Repository) RestoreFrom(ctx context.Context, path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("failed to open backup: %w", err)
+	}
+
+	missing := make(map[string]bool) // "table.column" absent from the backup
+	err := r.queue(ctx, func(ctx context.Context) error {
+		return r.restoreTables(ctx, path, missing)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, col := range r.addedColumns() {
+		if missing[col.table+"."+col.name] && col.backfill != nil {
+			if err := col.backfill(ctx); err != nil {
+				return fmt.Errorf("failed to backfill column %s.%s: %w", col.table, col.name, err)
+			}
+		}
+	}
+
+	return r.loadEncryption(ctx)
+}
+
+// restoreTables copies the tables of the backup at path over the current
+// ones, recording in missing the columns the backup lacks.
+func (r *Repository) restoreTables(ctx context.Context, path string, missing map[string]bool) error {
+	// ATTACH applies to a single connection, so everything runs on one
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to restore backup: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "ATTACH DATABASE ? AS backup", path); err != nil {
+		return fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "DETACH DATABASE backup")
+
+	err = conn.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		for _, table := range backupTables {
+			current, err := tableColumns(ctx, tx, "main", table)
+			if err != nil {
+				return err
+			}
+			saved, err := tableColumns(ctx, tx, "backup", table)
+			if err != nil {
+				return err
+			}
+
+			var common []string
+			for _, column := range current {
+				if slices.Contains(saved, column) {
+					common = append(common, column)
+				} else {
+					missing[table+"."+column] = true
+				}
+			}
+
+			if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM main.%s", table)); err != nil {
+				return err
+			}
+			if len(saved) == 0 {
+				continue // Table did not exist when the backup was made
+			}
+
+			columns := strings.Join(common, ", ")
+			if _, err := tx.ExecContext(ctx, fmt.Sprintf(
+				"INSERT INTO main.%s (%s) SELECT %s FROM backup.%s", table, columns, columns, table)); err != nil {
+				return fmt.Errorf("failed to restore %s: %w", table, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to restore backup: %w", err)
+	}
+	return nil
+}
+
+// tableColumns returns the columns of a table in the given schema, or none if
+// the table does not exist.
+func tableColumns(ctx context.Context, db bun.IDB, schema, table string) ([]string, error) {
+	var columns []string
+	err := db.NewRaw("SELECT name FROM pragma_table_info(?, ?) ORDER BY cid", table, schema).Scan(ctx, &columns)
+	return columns, err
+}
+
+// BackupStats describes the contents of a backup file.
+type BackupStats struct {
+	Items     int // Items outside the trash
+	Pinned    int
+	Encrypted bool // The history in the backup is encrypted
+}
+
+// ReadBackupStats counts the items in a database file without modifying it.
+func ReadBackupStats(ctx context.Context, path string) (*BackupStats, error) {
+	db, err := OpenReadOnly(path)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	// Backups made before the trash existed have no deleted_at column
+	where := "deleted_at IS NULL"
+	var hasTrash int
+	if err := db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM pragma_table_info('clipboard_items') WHERE name = 'deleted_at'").Scan(&hasTrash); err != nil {
+		return nil, err
+	}
+	if hasTrash == 0 {
+		where = "1"
+	}
+
+	var stats BackupStats
+	err = db.QueryRowContext(ctx, fmt.Sprintf(
+		"SELECT COUNT(*), COALESCE(SUM(pinned), 0) FROM clipboard_items WHERE %s", where)).Scan(&stats.Items, &stats.Pinned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	// Backups made before encryption existed have no meta table
+	err = db.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'meta')").Scan(&stats.Encrypted)
+	if err == nil && stats.Encrypted {
+		err = db.QueryRowContext(ctx,
+			"SELECT EXISTS (SELECT 1 FROM meta WHERE key = ?)", encryptionMetaKey).Scan(&stats.Encrypted)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	return &stats, nil
+}
+
+// OpenReadOnly opens an SQLite database file read-only, for example a backup
+// or another application's database.
+func OpenReadOnly(path string) (*sql.DB, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+
+	uriPath := filepath.ToSlash(path)
+	if !strings.HasPrefix(uriPath, "/") {
+		uriPath = "/" + uriPath // Windows drive letters
+	}
+
+	dsn := (&url.URL{Scheme: "file", Path: uriPath, RawQuery: "mode=ro"}).String()
+	db, err := sql.Open(sqliteshim.ShimName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
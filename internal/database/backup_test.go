@@ -0,0 +1,108 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/uptrace/bun/driver/sqliteshim"
+
+	"clipboardpro/internal/classify"
+)
+
+func TestBackupToAndRestoreFrom(t *testing.T) {
+	ctx := context.Background()
+	repository := newTestRepository(t)
+	ids := importTestItems(t, repository, 3, func(i int, item *ClipboardItem) {
+		item.Pinned = i == 2
+	})
+
+	path := filepath.Join(t.TempDir(), "backup.db")
+	if err := repository.BackupTo(ctx, path); err != nil {
+		t.Fatalf("BackupTo: %v", err)
+	}
+	if err := repository.BackupTo(ctx, path); err == nil {
+		t.Error("BackupTo overwrote an existing file")
+	}
+
+	// Changes made after the backup are undone by restoring it
+	if err := repository.TrashItems(ctx, []int64{ids[0]}, "test"); err != nil {
+		t.Fatalf("TrashItems: %v", err)
+	}
+	if err := repository.SaveClipboardItem(ctx, &ClipboardItem{Type: "text", Content: "later", Hash: "later"}); err != nil {
+		t.Fatalf("SaveClipboardItem: %v", err)
+	}
+
+	if err := repository.RestoreFrom(ctx, path); err != nil {
+		t.Fatalf("RestoreFrom: %v", err)
+	}
+	if got := listAll(t, repository, ListQuery{Limit: 10}); !slices.Equal(got, []int64{ids[1], ids[2], ids[0]}) {
+		t.Errorf("restored items %v, want %v", got, []int64{ids[1], ids[2], ids[0]})
+	}
+	if trashed, err := repository.GetTrashedItems(ctx, 10); err != nil || len(trashed) != 0 {
+		t.Errorf("GetTrashedItems = %d items, %v; want the trash emptied", len(trashed), err)
+	}
+}
+
+func TestRestoreFromOlderSchema(t *testing.T) {
+	ctx := context.Background()
+
+	// The schema of the first release: no revisions, meta table or any of
+	// the columns added since
+	path := filepath.Join(t.TempDir(), "old.db")
+	old, err := sql.Open(sqliteshim.ShimName, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statements := []string{
+		`CREATE TABLE clipboard_items (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			type VARCHAR NOT NULL,
+			content VARCHAR,
+			image_data BLOB,
+			timestamp TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			size INTEGER NOT NULL,
+			hash VARCHAR NOT NULL UNIQUE,
+			pinned BOOLEAN DEFAULT FALSE,
+			title VARCHAR,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`INSERT INTO clipboard_items (id, type, content, timestamp, size, hash, pinned) VALUES
+			(1, 'text', 'older pin', '2025-01-01 10:00:00', 9, 'a', TRUE),
+			(2, 'text', '{"key": "value"}', '2025-01-02 10:00:00', 16, 'b', FALSE),
+			(3, 'text', 'newer pin', '2025-01-03 10:00:00', 9, 'c', TRUE)`,
+	}
+	for _, statement := range statements {
+		if _, err := old.Exec(statement); err != nil {
+			t.Fatalf("creating old backup: %v", err)
+		}
+	}
+	old.Close()
+
+	repository := newTestRepository(t)
+	importTestItems(t, repository, 2, nil)
+	if err := repository.RestoreFrom(ctx, path); err != nil {
+		t.Fatalf("RestoreFrom: %v", err)
+	}
+
+	// Pins are ordered newest first, as they were listed before positions
+	if got := pinnedOrder(t, repository); !slices.Equal(got, []int64{3, 1}) {
+		t.Errorf("pinned order = %v, want [3 1]", got)
+	}
+	item, err := repository.GetItemByID(ctx, 2)
+	if err != nil {
+		t.Fatalf("GetItemByID: %v", err)
+	}
+	if item.Subtype != classify.JSON {
+		t.Errorf("subtype = %q, want %q from the backfill", item.Subtype, classify.JSON)
+	}
+	if item.CopyCount != 0 || item.IsTrashed() || item.Notes != "" {
+		t.Errorf("added columns are not at their defaults: %+v", item)
+	}
+	if count, err := repository.CountItems(ctx, ""); err != nil || count != 3 {
+		t.Errorf("CountItems = %d, %v; want the 3 items of the backup", count, err)
+	}
+}
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+
+	"clipboardpro/internal/classify"
+)
+
+// classifyItem sets the subtype and language of a text item from its
+// content, unless they are already known.
+func classifyItem(item *ClipboardItem) {
+	if item.Type != "text" || item.Subtype != "" {
+		return
+	}
+	item.Subtype, item.Language = classify.Text(item.Content)
+}
+
+// classifyItems classifies the text items saved before subtypes existed.
+// The content of an encrypted history cannot be read while migrating, so
+// its items are classified when it is next re-encrypted or decrypted.
+func (r *Repository) classifyItems(ctx context.Context) error {
+	encrypted, err := r.db.NewSelect().
+		Model((*Meta)(nil)).
+		Where("key = ?", encryptionMetaKey).
+		Exists(ctx)
+	if err != nil || encrypted {
+		return err
+	}
+
+	const batchSize = 500
+	var lastID int64
+
+	for {
+		var items []*ClipboardItem
+		if err := r.db.NewSelect().
+			Model(&items).
+			Column("id", "type", "content").
+			Where("type = 'text'").
+			Where("id > ?", lastID).
+			Order("id ASC").
+			Limit(batchSize).
+			Scan(ctx); err != nil {
+			return err
+		}
+
+		err := r.write(ctx, func(ctx context.Context, tx bun.Tx) error {
+			for _, item := range items {
+				classifyItem(item)
+				if item.Subtype == "" {
+					continue
+				}
+				if _, err := tx.NewUpdate().
+					Model(item).
+					Column("subtype", "language").
+					WherePK().
+					Exec(ctx); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(items) < batchSize {
+			return nil
+		}
+		lastID = items[len(items)-1].ID
+	}
+}
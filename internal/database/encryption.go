@@ -0,0 +1,515 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/uptrace/bun"
+
+	"clipboardpro/internal/util"
+	"clipboardpro/internal/vault"
+)
+
+// ErrLocked is returned when the database is encrypted and has not been
+// unlocked with its passphrase yet.
+var ErrLocked = errors.New("history is encrypted and locked")
+
+const (
+	encryptionMetaKey = "encryption"
+
+	// checkPlaintext is encrypted with the key to verify passphrases.
+	checkPlaintext = "clipboardpro"
+)
+
+// encryptionMeta is stored in the meta table while encryption is on.
+type encryptionMeta struct {
+	Params vault.Params `json:"params"`
+	Check  string       `json:"check"`
+}
+
+// loadEncryption reads whether the database is encrypted. An encrypted
+// database starts locked. If the current key still matches, for example
+// after restoring a backup made with the same passphrase, it stays unlocked.
+func (r *Repository) loadEncryption(ctx context.Context) error {
+	var meta Meta
+	err := r.db.NewSelect().Model(&meta).Where("key = ?", encryptionMetaKey).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		r.mu.Lock()
+		r.encryption, r.key = nil, nil
+		r.mu.Unlock()
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read encryption settings: %w", err)
+	}
+
+	var enc encryptionMeta
+	if err := json.Unmarshal([]byte(meta.Value), &enc); err != nil {
+		return fmt.Errorf("failed to read encryption settings: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.key != nil {
+		if _, err := r.key.DecryptString(enc.Check); err != nil {
+			r.key = nil
+		}
+	}
+	r.encryption = &enc
+	return nil
+}
+
+// IsEncrypted reports whether the history is encrypted at rest.
+func (r *Repository) IsEncrypted() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.encryption != nil
+}
+
+// IsLocked reports whether the history is encrypted and not unlocked yet.
+func (r *Repository) IsLocked() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.encryption != nil && r.key == nil
+}
+
+// Unlock derives the key from passphrase and makes the history readable.
+// vault.ErrWrongPassphrase is returned if the passphrase does not match.
+func (r *Repository) Unlock(passphrase string) error {
+	r.mu.RLock()
+	enc := r.encryption
+	r.mu.RUnlock()
+	if enc == nil {
+		return nil
+	}
+
+	key, err := verifyPassphrase(enc, passphrase)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.key = key
+	r.mu.Unlock()
+	r.changes.Add(1)
+
+	if err := r.sealPlainNotes(context.Background(), key); err != nil {
+		return fmt.Errorf("failed to encrypt notes: %w", err)
+	}
+	return nil
+}
+
+// sealPlainNotes encrypts the notes left unencrypted by versions that did
+// not encrypt notes. It needs the key, so it runs once the history is
+// unlocked rather than while migrating.
+func (r *Repository) sealPlainNotes(ctx context.Context, key *vault.Key) error {
+	var items []*ClipboardItem
+	if err := r.db.NewSelect().
+		Model(&items).
+		Column("id", "notes").
+		Where("notes IS NOT NULL AND notes != ''").
+		Scan(ctx); err != nil {
+		return err
+	}
+	items = slices.DeleteFunc(items, func(item *ClipboardItem) bool {
+		return vault.IsEncryptedString(item.Notes)
+	})
+	if len(items) == 0 {
+		return nil
+	}
+
+	return r.write(ctx, func(ctx context.Context, tx bun.Tx) error {
+		for _, item := range items {
+			notes, err := key.EncryptString(item.Notes)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.NewUpdate().
+				Model((*ClipboardItem)(nil)).
+				Set("notes = ?", notes).
+				Where("id = ?", item.ID).
+				Exec(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// EnableEncryption encrypts the whole history with a key derived from
+// passphrase.
+func (r *Repository) EnableEncryption(ctx context.Context, passphrase string) error {
+	if r.IsEncrypted() {
+		return errors.New("history is already encrypted")
+	}
+	if err := r.rekey(ctx, passphrase); err != nil {
+		return fmt.Errorf("failed to encrypt history: %w", err)
+	}
+	return nil
+}
+
+// ChangePassphrase re-encrypts the whole history with a new key derived
+// from newPassphrase and a fresh salt.
+func (r *Repository) ChangePassphrase(ctx context.Context, current, newPassphrase string) error {
+	if err := r.checkPassphrase(current); err != nil {
+		return err
+	}
+	if err := r.rekey(ctx, newPassphrase); err != nil {
+		return fmt.Errorf("failed to change passphrase: %w", err)
+	}
+	return nil
+}
+
+// DisableEncryption decrypts the whole history and turns encryption off.
+func (r *Repository) DisableEncryption(ctx context.Context, current string) error {
+	if err := r.checkPassphrase(current); err != nil {
+		return err
+	}
+	if err := r.rewrite(ctx, nil, nil); err != nil {
+		return fmt.Errorf("failed to decrypt history: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) checkPassphrase(passphrase string) error {
+	r.mu.RLock()
+	enc := r.encryption
+	r.mu.RUnlock()
+	if enc == nil {
+		return errors.New("history is not encrypted")
+	}
+
+	_, err := verifyPassphrase(enc, passphrase)
+	return err
+}
+
+func verifyPassphrase(enc *encryptionMeta, passphrase string) (*vault.Key, error) {
+	key, err := vault.DeriveKey(passphrase, enc.Params)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := key.DecryptString(enc.Check); err != nil {
+		return nil, vault.ErrWrongPassphrase
+	}
+	return key, nil
+}
+
+// rekey rewrites the history with a new key derived from passphrase.
+func (r *Repository) rekey(ctx context.Context, passphrase string) error {
+	if passphrase == "" {
+		return errors.New("passphrase must not be empty")
+	}
+
+	params, err := vault.NewParams()
+	if err != nil {
+		return err
+	}
+	key, err := vault.DeriveKey(passphrase, params)
+	if err != nil {
+		return err
+	}
+	check, err := key.EncryptString(checkPlaintext)
+	if err != nil {
+		return err
+	}
+
+	return r.rewrite(ctx, key, &encryptionMeta{Params: params, Check: check})
+}
+
+// rewrite re-encrypts every item and revision with newKey, or decrypts them
+// if newKey is nil, and recomputes their hashes. It runs in one transaction
+// and then vacuums the database and empties the write-ahead log, so no old
+// copies remain in free pages or in the log.
+func (r *Repository) rewrite(ctx context.Context, newKey *vault.Key, newMeta *encryptionMeta) error {
+	oldKey, err := r.cipherKey()
+	if err != nil {
+		return err
+	}
+
+	err = r.write(ctx, func(ctx context.Context, tx bun.Tx) error {
+		if err := rewriteItems(ctx, tx, oldKey, newKey); err != nil {
+			return err
+		}
+		if err := rewriteRevisions(ctx, tx, oldKey, newKey); err != nil {
+			return err
+		}
+
+		if newMeta == nil {
+			_, err := tx.NewDelete().Model((*Meta)(nil)).Where("key = ?", encryptionMetaKey).Exec(ctx)
+			return err
+		}
+
+		value, err := json.Marshal(newMeta)
+		if err != nil {
+			return err
+		}
+		_, err = tx.NewInsert().
+			Model(&Meta{Key: encryptionMetaKey, Value: string(value)}).
+			On("CONFLICT (key) DO UPDATE").
+			Set("value = EXCLUDED.value").
+			Exec(ctx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.encryption, r.key = newMeta, newKey
+	r.mu.Unlock()
+
+	err = r.queue(ctx, func(ctx context.Context) error {
+		if _, err := r.db.ExecContext(ctx, "VACUUM"); err != nil {
+			return err
+		}
+
+		// VACUUM goes through the log like any write, so the log is
+		// checkpointed into the database and truncated
+		var busy, logFrames, checkpointed int
+		if err := r.db.QueryRowContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)").Scan(&busy, &logFrames, &checkpointed); err != nil {
+			return err
+		}
+		if busy != 0 {
+			return errors.New("the database is in use by another connection")
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to compact database: %w", err)
+	}
+	return nil
+}
+
+func rewriteItems(ctx context.Context, tx bun.Tx, oldKey, newKey *vault.Key) error {
+	const batchSize = 100
+	var lastID int64
+
+	for {
+		var items []*ClipboardItem
+		if err := tx.NewSelect().
+			Model(&items).
+			Column("id", "type", "subtype", "language", "content", "title", "notes", "tags", "source_app", "image_data").
+			Where("id > ?", lastID).
+			Order("id ASC").
+			Limit(batchSize).
+			Scan(ctx); err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			if err := openItem(oldKey, item); err != nil {
+				return fmt.Errorf("item %d: %w", item.ID, err)
+			}
+			item.Hash = util.GenerateHash(item.Content, item.ImageData)
+			classifyItem(item)
+
+			sealed, err := sealItem(newKey, item)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.NewUpdate().
+				Model(sealed).
+				Column("content", "title", "notes", "tags", "source_app", "image_data", "hash", "subtype", "language").
+				WherePK().
+				Exec(ctx); err != nil {
+				return err
+			}
+		}
+
+		if len(items) < batchSize {
+			return nil
+		}
+		lastID = items[len(items)-1].ID
+	}
+}
+
+func rewriteRevisions(ctx context.Context, tx bun.Tx, oldKey, newKey *vault.Key) error {
+	var revisions []*ItemRevision
+	if err := tx.NewSelect().Model(&revisions).Column("id", "content").Scan(ctx); err != nil {
+		return err
+	}
+
+	for _, revision := range revisions {
+		if err := openRevision(oldKey, revision); err != nil {
+			return fmt.Errorf("revision %d: %w", revision.ID, err)
+		}
+		revision.Hash = storedHash(newKey, util.GenerateHash(revision.Content, nil))
+
+		content, err := encryptString(newKey, revision.Content)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.NewUpdate().
+			Model((*ItemRevision)(nil)).
+			Set("content = ?", content).
+			Set("hash = ?", revision.Hash).
+			Where("id = ?", revision.ID).
+			Exec(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cipherKey returns the key to encrypt and decrypt with, nil if the history
+// is not encrypted, or ErrLocked.
+func (r *Repository) cipherKey() (*vault.Key, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.encryption == nil {
+		return nil, nil
+	}
+	if r.key == nil {
+		return nil, ErrLocked
+	}
+	return r.key, nil
+}
+
+// storedHash returns the hash stored for content with the given plain hash.
+// Encrypted databases store a keyed hash so the hash reveals nothing about
+// the content.
+func storedHash(key *vault.Key, hash string) string {
+	if key == nil {
+		return hash
+	}
+	return key.Hash(hash)
+}
+
+// sealItem returns a copy of item with its content, title, notes, tags,
+// source application, image and hash prepared for storage. Item is not modified.
+//
+// The type, subtype, language, size and times of items stay unencrypted so
+// lists can be sorted and filtered by type in SQL. They reveal what kind of
+// text an item holds, such as a URL or a JWT, but not the text itself.
+func sealItem(key *vault.Key, item *ClipboardItem) (*ClipboardItem, error) {
+	sealed := *item
+	sealed.Hash = storedHash(key, item.Hash)
+	if key == nil {
+		return &sealed, nil
+	}
+
+	var err error
+	if sealed.Content, err = key.EncryptString(item.Content); err != nil {
+		return nil, err
+	}
+	if sealed.Title, err = key.EncryptString(item.Title); err != nil {
+		return nil, err
+	}
+	if sealed.Notes, err = key.EncryptString(item.Notes); err != nil {
+		return nil, err
+	}
+	if sealed.Tags, err = key.EncryptString(item.Tags); err != nil {
+		return nil, err
+	}
+	if sealed.SourceApp, err = key.EncryptString(item.SourceApp); err != nil {
+		return nil, err
+	}
+	if sealed.ImageData, err = key.EncryptBytes(item.ImageData); err != nil {
+		return nil, err
+	}
+	return &sealed, nil
+}
+
+// openItem decrypts the content, title, notes, tags, source application,
+// image and preview of a loaded item in place.
+func openItem(key *vault.Key, item *ClipboardItem) error {
+	if key == nil {
+		return nil
+	}
+
+	var err error
+	if item.Content, err = key.DecryptString(item.Content); err != nil {
+		return err
+	}
+	if item.Title, err = key.DecryptString(item.Title); err != nil {
+		return err
+	}
+	if item.Notes, err = key.DecryptString(item.Notes); err != nil {
+		return err
+	}
+	if item.Tags, err = key.DecryptString(item.Tags); err != nil {
+		return err
+	}
+	if item.SourceApp, err = key.DecryptString(item.SourceApp); err != nil {
+		return err
+	}
+	if item.ImageData, err = key.DecryptBytes(item.ImageData); err != nil {
+		return err
+	}
+	item.Preview, err = key.DecryptString(item.Preview)
+	return err
+}
+
+// openFullItem decrypts an item loaded with all its columns and restores
+// its plain content hash, which callers compare with clipboard contents.
+func openFullItem(key *vault.Key, item *ClipboardItem) error {
+	if key == nil {
+		return nil
+	}
+	if err := openItem(key, item); err != nil {
+		return err
+	}
+	item.Hash = util.GenerateHash(item.Content, item.ImageData)
+	return nil
+}
+
+// openItems decrypts loaded items in place. Previews are shortened to
+// previewLength characters, as encrypted list rows load the whole content.
+func openItems(key *vault.Key, items []*ClipboardItem) error {
+	if key == nil {
+		return nil
+	}
+
+	for _, item := range items {
+		if err := openItem(key, item); err != nil {
+			return fmt.Errorf("failed to decrypt item %d: %w", item.ID, err)
+		}
+		item.Preview = truncateRunes(item.Preview, previewLength)
+	}
+	return nil
+}
+
+func openRevision(key *vault.Key, revision *ItemRevision) error {
+	var err error
+	revision.Content, err = decryptString(key, revision.Content)
+	return err
+}
+
+func encryptString(key *vault.Key, s string) (string, error) {
+	if key == nil {
+		return s, nil
+	}
+	return key.EncryptString(s)
+}
+
+func decryptString(key *vault.Key, s string) (string, error) {
+	if key == nil {
+		return s, nil
+	}
+	return key.DecryptString(s)
+}
+
+// matchesSearch reports whether a decrypted item matches a search term the
+// way the SQL LIKE filter does: case-insensitively, in content, title or notes.
+func matchesSearch(item *ClipboardItem, content, term string) bool {
+	term = strings.ToLower(term)
+	return strings.Contains(strings.ToLower(content), term) ||
+		strings.Contains(strings.ToLower(item.Title), term) ||
+		strings.Contains(strings.ToLower(item.Notes), term)
+}
+
+func truncateRunes(s string, n int) string {
+	for i := range s {
+		if n == 0 {
+			return s[:i]
+		}
+		n--
+	}
+	return s
+}
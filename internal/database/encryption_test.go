@@ -0,0 +1,66 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEnableEncryptionLeavesNoPlaintext(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "history.db")
+	repository, err := NewRepository(path)
+	if err != nil {
+		t.Fatalf("NewRepository: %v", err)
+	}
+	defer repository.Close()
+
+	secret := "correct horse battery staple"
+	if err := repository.SaveClipboardItem(ctx, &ClipboardItem{Type: "text", Content: secret, Hash: "secret"}); err != nil {
+		t.Fatalf("SaveClipboardItem: %v", err)
+	}
+
+	before := filepath.Join(dir, "before.db")
+	if err := repository.BackupTo(ctx, before); err != nil {
+		t.Fatalf("BackupTo: %v", err)
+	}
+	if err := repository.EnableEncryption(ctx, "passphrase"); err != nil {
+		t.Fatalf("EnableEncryption: %v", err)
+	}
+	after := filepath.Join(dir, "after.db")
+	if err := repository.BackupTo(ctx, after); err != nil {
+		t.Fatalf("BackupTo: %v", err)
+	}
+
+	if info, err := os.Stat(path + "-wal"); err == nil && info.Size() != 0 {
+		t.Errorf("write-ahead log holds %d bytes after encrypting, want it truncated", info.Size())
+	}
+	for _, file := range []string{path, path + "-wal"} {
+		data, err := os.ReadFile(file)
+		if err != nil && !os.IsNotExist(err) {
+			t.Fatalf("ReadFile: %v", err)
+		}
+		if bytes.Contains(data, []byte(secret)) {
+			t.Errorf("%s still contains the plaintext", filepath.Base(file))
+		}
+	}
+
+	for _, tt := range []struct {
+		path string
+		want bool
+	}{
+		{before, false},
+		{after, true},
+	} {
+		stats, err := ReadBackupStats(ctx, tt.path)
+		if err != nil {
+			t.Fatalf("ReadBackupStats(%s): %v", filepath.Base(tt.path), err)
+		}
+		if stats.Encrypted != tt.want {
+			t.Errorf("ReadBackupStats(%s).Encrypted = %v, want %v", filepath.Base(tt.path), stats.Encrypted, tt.want)
+		}
+	}
+}
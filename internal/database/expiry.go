@@ -0,0 +1,121 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// SetExpiry sets when an item is deleted for good. The zero time clears
+// the expiry.
+func (r *Repository) SetExpiry(ctx context.Context, id int64, expiresAt time.Time) error {
+	err := r.write(ctx, func(ctx context.Context, tx bun.Tx) error {
+		query := tx.NewUpdate().
+			Model((*ClipboardItem)(nil)).
+			Set("updated_at = ?", time.Now()).
+			Where("id = ?", id)
+		if expiresAt.IsZero() {
+			query = query.Set("expires_at = NULL")
+		} else {
+			query = query.Set("expires_at = ?", expiresAt)
+		}
+
+		_, err := query.Exec(ctx)
+		return err
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to set expiry: %w", err)
+	}
+
+	return nil
+}
+
+// SetBurnAfterCopy sets whether an item is deleted for good once it has
+// been copied.
+func (r *Repository) SetBurnAfterCopy(ctx context.Context, id int64, burn bool) error {
+	err := r.write(ctx, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewUpdate().
+			Model((*ClipboardItem)(nil)).
+			Set("burn_after_copy = ?", burn).
+			Set("updated_at = ?", time.Now()).
+			Where("id = ?", id).
+			Exec(ctx)
+		return err
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to set delete after copy: %w", err)
+	}
+
+	return nil
+}
+
+// BurnItem permanently deletes an item, bypassing the trash, once a burn
+// after copy item has been copied.
+func (r *Repository) BurnItem(ctx context.Context, id int64) error {
+	err := r.write(ctx, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewDelete().
+			Model((*ClipboardItem)(nil)).
+			Where("id = ?", id).
+			Exec(ctx)
+		return err
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to delete copied item: %w", err)
+	}
+
+	return r.deleteOrphanedRevisions(ctx)
+}
+
+// NextExpiry returns when the next item expires, or the zero time if no
+// item has an expiry.
+func (r *Repository) NextExpiry(ctx context.Context) (time.Time, error) {
+	var items []*ClipboardItem
+	err := r.db.NewSelect().
+		Model(&items).
+		Column("id", "expires_at").
+		Where("expires_at IS NOT NULL").
+		OrderExpr("julianday(expires_at) ASC").
+		Limit(1).
+		Scan(ctx)
+
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to find next expiry: %w", err)
+	}
+	if len(items) == 0 {
+		return time.Time{}, nil
+	}
+
+	return items[0].ExpiresAt, nil
+}
+
+// PurgeExpiredItems permanently deletes the items, trashed or not, that
+// expired by now and returns how many it deleted. Expiring items are meant
+// to disappear, so they do not go through the trash.
+func (r *Repository) PurgeExpiredItems(ctx context.Context, now time.Time) (int64, error) {
+	var purged int64
+	err := r.write(ctx, func(ctx context.Context, tx bun.Tx) error {
+		res, err := tx.NewDelete().
+			Model((*ClipboardItem)(nil)).
+			Where("expires_at IS NOT NULL AND julianday(expires_at) <= julianday(?)", now).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		purged, err = res.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge expired items: %w", err)
+	}
+	if purged == 0 {
+		return 0, nil
+	}
+
+	return purged, r.deleteOrphanedRevisions(ctx)
+}
@@ -0,0 +1,153 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+// editTestItem gives an item a revision by editing its content.
+func editTestItem(t *testing.T, repository *Repository, id int64) {
+	t.Helper()
+	if err := repository.UpdateContent(context.Background(), id, fmt.Sprintf("edited %d", id), false); err != nil {
+		t.Fatalf("UpdateContent: %v", err)
+	}
+}
+
+// revisionItems returns the IDs of the items that have revisions.
+func revisionItems(t *testing.T, repository *Repository) []int64 {
+	t.Helper()
+	var ids []int64
+	if err := repository.db.NewSelect().
+		Model((*ItemRevision)(nil)).
+		ColumnExpr("DISTINCT item_id").
+		Order("item_id").
+		Scan(context.Background(), &ids); err != nil {
+		t.Fatalf("listing revisions: %v", err)
+	}
+	return ids
+}
+
+func TestPurgeExpiredItems(t *testing.T) {
+	ctx := context.Background()
+	repository := newTestRepository(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// Item 1 expired, 2 expired in the trash, 3 expires exactly now, 4 expires
+	// later and 5 never expires
+	ids := importTestItems(t, repository, 5, nil)
+	expiries := map[int64]time.Time{
+		ids[0]: now.Add(-time.Hour),
+		ids[1]: now.Add(-time.Minute),
+		ids[2]: now,
+		ids[3]: now.Add(time.Hour),
+	}
+	for id, expiresAt := range expiries {
+		if err := repository.SetExpiry(ctx, id, expiresAt); err != nil {
+			t.Fatalf("SetExpiry: %v", err)
+		}
+	}
+	if err := repository.TrashItems(ctx, []int64{ids[1]}, "test"); err != nil {
+		t.Fatalf("TrashItems: %v", err)
+	}
+	editTestItem(t, repository, ids[0])
+	editTestItem(t, repository, ids[3])
+
+	next, err := repository.NextExpiry(ctx)
+	if err != nil {
+		t.Fatalf("NextExpiry: %v", err)
+	}
+	if !next.Equal(expiries[ids[0]]) {
+		t.Errorf("NextExpiry = %v, want %v", next, expiries[ids[0]])
+	}
+
+	purged, err := repository.PurgeExpiredItems(ctx, now)
+	if err != nil {
+		t.Fatalf("PurgeExpiredItems: %v", err)
+	}
+	if purged != 3 {
+		t.Errorf("purged %d items, want 3", purged)
+	}
+
+	if got := listAll(t, repository, ListQuery{Limit: 10}); !slices.Equal(got, []int64{ids[4], ids[3]}) {
+		t.Errorf("items left %v, want %v", got, []int64{ids[4], ids[3]})
+	}
+	if trashed, err := repository.GetTrashedItems(ctx, 10); err != nil || len(trashed) != 0 {
+		t.Errorf("GetTrashedItems = %d items, %v; want the expired item gone from the trash", len(trashed), err)
+	}
+	if got := revisionItems(t, repository); !slices.Equal(got, []int64{ids[3]}) {
+		t.Errorf("items with revisions %v, want only %v", got, []int64{ids[3]})
+	}
+
+	next, err = repository.NextExpiry(ctx)
+	if err != nil {
+		t.Fatalf("NextExpiry: %v", err)
+	}
+	if !next.Equal(expiries[ids[3]]) {
+		t.Errorf("NextExpiry = %v, want %v", next, expiries[ids[3]])
+	}
+
+	if err := repository.SetExpiry(ctx, ids[3], time.Time{}); err != nil {
+		t.Fatalf("SetExpiry: %v", err)
+	}
+	if next, err := repository.NextExpiry(ctx); err != nil || !next.IsZero() {
+		t.Errorf("NextExpiry = %v, %v; want the zero time once nothing expires", next, err)
+	}
+	if purged, err := repository.PurgeExpiredItems(ctx, now.Add(24*time.Hour)); err != nil || purged != 0 {
+		t.Errorf("PurgeExpiredItems = %d, %v; want nothing purged", purged, err)
+	}
+}
+
+func TestNextExpiryAcrossTimeZones(t *testing.T) {
+	ctx := context.Background()
+	repository := newTestRepository(t)
+	ids := importTestItems(t, repository, 2, nil)
+
+	// Later on the clock, but earlier in absolute time
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	earlier := now.Add(time.Hour).In(time.FixedZone("UTC+5", 5*60*60))
+	if err := repository.SetExpiry(ctx, ids[0], now.Add(2*time.Hour)); err != nil {
+		t.Fatalf("SetExpiry: %v", err)
+	}
+	if err := repository.SetExpiry(ctx, ids[1], earlier); err != nil {
+		t.Fatalf("SetExpiry: %v", err)
+	}
+
+	next, err := repository.NextExpiry(ctx)
+	if err != nil {
+		t.Fatalf("NextExpiry: %v", err)
+	}
+	if !next.Equal(earlier) {
+		t.Errorf("NextExpiry = %v, want %v", next, earlier)
+	}
+}
+
+func TestBurnItem(t *testing.T) {
+	ctx := context.Background()
+	repository := newTestRepository(t)
+	ids := importTestItems(t, repository, 3, func(i int, item *ClipboardItem) {
+		item.BurnAfterCopy = i <= 2
+	})
+	editTestItem(t, repository, ids[0])
+	editTestItem(t, repository, ids[1])
+
+	if err := repository.BurnItem(ctx, ids[0]); err != nil {
+		t.Fatalf("BurnItem: %v", err)
+	}
+
+	if got := listAll(t, repository, ListQuery{Limit: 10}); !slices.Equal(got, []int64{ids[2], ids[1]}) {
+		t.Errorf("items left %v, want %v", got, []int64{ids[2], ids[1]})
+	}
+	if got := revisionItems(t, repository); !slices.Equal(got, []int64{ids[1]}) {
+		t.Errorf("items with revisions %v, want only %v", got, []int64{ids[1]})
+	}
+	item, err := repository.GetItemByID(ctx, ids[1])
+	if err != nil {
+		t.Fatalf("GetItemByID: %v", err)
+	}
+	if !item.BurnAfterCopy {
+		t.Error("the other burn after copy item lost its setting")
+	}
+}
@@ -0,0 +1,158 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// GetItemsByHash returns the items, including trashed ones, that have one of
+// the given hashes, keyed by hash. Items are loaded as list rows.
+func (r *Repository) GetItemsByHash(ctx context.Context, hashes []string) (map[string]*ClipboardItem, error) {
+	key, err := r.cipherKey()
+	if err != nil {
+		return nil, err
+	}
+
+	// Encrypted databases store keyed hashes; map them back to the plain ones
+	plain := make(map[string]string, len(hashes))
+	stored := make([]string, len(hashes))
+	for i, hash := range hashes {
+		stored[i] = storedHash(key, hash)
+		plain[stored[i]] = hash
+	}
+
+	const batchSize = 500
+	found := make(map[string]*ClipboardItem, len(hashes))
+
+	for start := 0; start < len(stored); start += batchSize {
+		batch := stored[start:min(start+batchSize, len(stored))]
+
+		var items []*ClipboardItem
+		if err := selectListColumns(r.db.NewSelect().Model(&items), key != nil).
+			Column("notes").
+			Where("hash IN (?)", bun.In(batch)).
+			Scan(ctx); err != nil {
+			return nil, fmt.Errorf("failed to get items by hash: %w", err)
+		}
+		if err := openItems(key, items); err != nil {
+			return nil, err
+		}
+
+		for _, item := range items {
+			item.Hash = plain[item.Hash]
+			found[item.Hash] = item
+		}
+	}
+
+	return found, nil
+}
+
+// ImportItems inserts new items and updates existing ones in a single
+// transaction. Timestamps, titles, notes, tags, pins and usage are kept as
+// given.
+// Updated items are taken out of the trash.
+//
+// Pinned items with Position 0 are placed below the existing pinned items,
+// updates first, each in the order given.
+func (r *Repository) ImportItems(ctx context.Context, inserts, updates []*ClipboardItem) error {
+	key, err := r.cipherKey()
+	if err != nil {
+		return err
+	}
+
+	err = r.write(ctx, func(ctx context.Context, tx bun.Tx) error {
+		var maxPosition int
+		if err := tx.NewSelect().
+			Model((*ClipboardItem)(nil)).
+			ColumnExpr("COALESCE(MAX(position), 0)").
+			Where("pinned = TRUE").
+			Scan(ctx, &maxPosition); err != nil {
+			return err
+		}
+
+		place := func(item *ClipboardItem) {
+			if !item.Pinned {
+				item.Position = 0
+			} else if item.Position == 0 {
+				maxPosition++
+				item.Position = maxPosition
+			}
+		}
+
+		for _, item := range updates {
+			place(item)
+		}
+		for _, item := range inserts {
+			place(item)
+		}
+
+		now := time.Now()
+		for _, item := range updates {
+			item.DeletedAt = time.Time{}
+			item.DeleteReason = ""
+			item.UpdatedAt = now
+			item.UsageWeight = usageWeight(item.CopyCount)
+
+			item.Tags = NormalizeTags(item.Tags)
+			sealed := *item
+			if sealed.Title, err = encryptString(key, item.Title); err != nil {
+				return err
+			}
+			if sealed.Notes, err = encryptString(key, item.Notes); err != nil {
+				return err
+			}
+			if sealed.Tags, err = encryptString(key, item.Tags); err != nil {
+				return err
+			}
+
+			if _, err := tx.NewUpdate().
+				Model(&sealed).
+				Column("timestamp", "title", "notes", "tags", "pinned", "position", "copy_count",
+					"usage_weight", "last_used_at", "deleted_at", "delete_reason", "updated_at").
+				WherePK().
+				Exec(ctx); err != nil {
+				return err
+			}
+		}
+
+		// Insert oldest first so IDs follow the original order
+		inserts := slices.Clone(inserts)
+		sort.SliceStable(inserts, func(i, j int) bool {
+			return inserts[i].Timestamp.Before(inserts[j].Timestamp)
+		})
+		for _, item := range inserts {
+			item.ID = 0
+			if item.Timestamp.IsZero() {
+				item.Timestamp = now
+			}
+			if item.CreatedAt.IsZero() {
+				item.CreatedAt = item.Timestamp
+			}
+			item.UpdatedAt = now
+			item.UsageWeight = usageWeight(item.CopyCount)
+			item.Tags = NormalizeTags(item.Tags)
+			classifyItem(item)
+
+			sealed, err := sealItem(key, item)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.NewInsert().Model(sealed).Exec(ctx); err != nil {
+				return err
+			}
+			item.ID = sealed.ID
+		}
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to import items: %w", err)
+	}
+
+	return nil
+}
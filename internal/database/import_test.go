@@ -0,0 +1,69 @@
+package database
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestImportItemsPlacesPins(t *testing.T) {
+	ctx := context.Background()
+	repository := newTestRepository(t)
+	ids := importTestItems(t, repository, 4, func(i int, item *ClipboardItem) {
+		if i <= 2 {
+			item.Pinned, item.Position = true, i
+		}
+	})
+
+	update := func(id int64, pinned bool) *ClipboardItem {
+		item, err := repository.GetItemByID(ctx, id)
+		if err != nil {
+			t.Fatalf("GetItemByID: %v", err)
+		}
+		item.Pinned, item.Position = pinned, 0
+		return item
+	}
+	insert := func(content string, minutes int, pinned bool, position int) *ClipboardItem {
+		return &ClipboardItem{
+			Type:      "text",
+			Content:   content,
+			Hash:      content,
+			Pinned:    pinned,
+			Position:  position,
+			Timestamp: time.Date(2026, 2, 1, 0, minutes, 0, 0, time.UTC),
+		}
+	}
+
+	repinned := update(ids[2], true)
+	unpinned := update(ids[3], false)
+	unpinned.Position = 3 // Ignored for an unpinned item
+	newer := insert("newer", 10, true, 0)
+	older := insert("older", 0, true, 0)
+	placed := insert("placed", 5, true, 50)
+	plain := insert("plain", 5, false, 7)
+
+	if err := repository.ImportItems(ctx, []*ClipboardItem{newer, older, placed, plain}, []*ClipboardItem{repinned, unpinned}); err != nil {
+		t.Fatalf("ImportItems: %v", err)
+	}
+
+	// Pins without a position go below the existing ones, updates first,
+	// each in the order given rather than by age
+	want := []int64{ids[0], ids[1], repinned.ID, newer.ID, older.ID, placed.ID}
+	if got := pinnedOrder(t, repository); !slices.Equal(got, want) {
+		t.Errorf("pinned order = %v, want %v", got, want)
+	}
+	if older.ID > newer.ID {
+		t.Errorf("older item got ID %d after newer item %d, want inserts oldest first", older.ID, newer.ID)
+	}
+
+	for _, id := range []int64{unpinned.ID, plain.ID} {
+		item, err := repository.GetItemByID(ctx, id)
+		if err != nil {
+			t.Fatalf("GetItemByID: %v", err)
+		}
+		if item.Pinned || item.Position != 0 {
+			t.Errorf("unpinned item %d has pinned %v, position %d", id, item.Pinned, item.Position)
+		}
+	}
+}
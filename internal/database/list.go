@@ -0,0 +1,389 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/uptrace/bun"
+
+	"clipboardpro/internal/classify"
+	"clipboardpro/internal/vault"
+)
+
+// SortMode selects how unpinned items are ordered. Pinned items always come
+// first, in their manual order.
+type SortMode string
+
+const (
+	SortRecent   SortMode = "recent"
+	SortMostUsed SortMode = "most_used"
+	SortFrecency SortMode = "frecency"
+)
+
+// frecencyDecayDays controls how quickly past usage loses weight in
+// SortFrecency. Ordering by last use plus decay*ln(copies+1) is equivalent to
+// ordering by (copies+1)*exp(-age/decay), without depending on the current time.
+const frecencyDecayDays = 7
+
+// usageWeight returns the frecency boost of an item copied copies times.
+// It is stored with each item rather than computed in SQL because ln() is
+// missing from SQLite builds without the math functions.
+func usageWeight(copies int) float64 {
+	return frecencyDecayDays * math.Log(float64(copies+1))
+}
+
+// weighUsage sets the usage weights of items copied before they were stored.
+func (r *Repository) weighUsage(ctx context.Context) error {
+	var items []*ClipboardItem
+	if err := r.db.NewSelect().
+		Model(&items).
+		Column("id", "copy_count").
+		Where("copy_count > 0").
+		Scan(ctx); err != nil {
+		return err
+	}
+
+	return r.write(ctx, func(ctx context.Context, tx bun.Tx) error {
+		for _, item := range items {
+			if _, err := tx.NewUpdate().
+				Model((*ClipboardItem)(nil)).
+				Set("usage_weight = ?", usageWeight(item.CopyCount)).
+				Where("id = ?", item.ID).
+				Exec(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// previewLength is the number of characters of content loaded for list rows.
+const previewLength = 200
+
+// listColumns are the columns loaded for list rows. Content is only loaded as
+// a short preview, and image data and notes are left out entirely.
+var listColumns = []string{
+	"id", "type", "subtype", "language", "timestamp", "size", "hash", "pinned", "position", "title",
+	"tags", "source_app", "copy_count", "last_used_at", "expires_at", "burn_after_copy", "deleted_at", "delete_reason",
+	"created_at", "updated_at",
+}
+
+// selectListColumns restricts q to the lightweight list row projection.
+// Encrypted content cannot be cut in SQL, so it is loaded whole and
+// shortened once decrypted.
+func selectListColumns(q *bun.SelectQuery, encrypted bool) *bun.SelectQuery {
+	q = q.Column(listColumns...)
+	if encrypted {
+		return q.ColumnExpr("?TableAlias.content AS preview")
+	}
+	return q.ColumnExpr("substr(?TableAlias.content, 1, ?) AS preview", previewLength)
+}
+
+// ListQuery describes one page of the history list.
+type ListQuery struct {
+	Search string
+	Sort   SortMode
+	Limit  int
+	After  *Cursor // Continue after this row; nil for the first page
+}
+
+// Cursor identifies the last row of a page for keyset pagination. Rows are
+// ordered by (pinned, -position, key, key2, id), all descending.
+type Cursor struct {
+	Pinned   bool
+	Position int
+	Key      float64
+	Key2     float64
+	ID       int64
+}
+
+// Page is one page of list results.
+type Page struct {
+	Items []*ClipboardItem
+	Next  *Cursor // Nil when there are no more items
+}
+
+// listRow is a list result together with the sort keys used for its cursor.
+type listRow struct {
+	ClipboardItem `bun:",extend"`
+
+	SortKey  float64 `bun:"sort_key,scanonly"`
+	SortKey2 float64 `bun:"sort_key2,scanonly"`
+}
+
+// sortKeys returns the SQL expressions of the two real-valued sort keys for a mode.
+func sortKeys(sort SortMode) (string, string) {
+	switch sort {
+	case SortMostUsed:
+		return "CAST(copy_count AS REAL)", "julianday(COALESCE(last_used_at, timestamp))"
+	case SortFrecency:
+		return "julianday(COALESCE(last_used_at, timestamp)) + usage_weight", "julianday(timestamp)"
+	default:
+		return "julianday(timestamp)", "0.0"
+	}
+}
+
+// ListItems returns a page of items that are not in the trash, optionally
+// filtered by a search term. Items are loaded as lightweight list rows.
+func (r *Repository) ListItems(ctx context.Context, query ListQuery) (*Page, error) {
+	key, err := r.cipherKey()
+	if err != nil {
+		return nil, err
+	}
+	filter := parseSearch(query.Search)
+	if key != nil && filter.decrypts() {
+		return r.searchEncrypted(ctx, key, query, filter)
+	}
+
+	var rows []*listRow
+	if err := r.listRowsQuery(&rows, query, filter, key != nil).Scan(ctx); err != nil {
+		return nil, fmt.Errorf("failed to list items: %w", err)
+	}
+
+	page := &Page{Items: make([]*ClipboardItem, len(rows))}
+	for i, row := range rows {
+		page.Items[i] = &row.ClipboardItem
+	}
+	if err := openItems(key, page.Items); err != nil {
+		return nil, err
+	}
+
+	if len(rows) == query.Limit && len(rows) > 0 {
+		page.Next = rows[len(rows)-1].cursor()
+	}
+
+	return page, nil
+}
+
+// listRowsQuery builds the query for one page of list rows in list order,
+// filtered by filter rather than query.Search.
+func (r *Repository) listRowsQuery(rows *[]*listRow, query ListQuery, filter listFilter, encrypted bool) *bun.SelectQuery {
+	key, key2 := sortKeys(query.Sort)
+
+	q := selectListColumns(r.db.NewSelect().Model(rows), encrypted).
+		ColumnExpr("? AS sort_key", bun.Safe(key)).
+		ColumnExpr("? AS sort_key2", bun.Safe(key2))
+	q = applyListFilter(q, filter)
+
+	if after := query.After; after != nil {
+		q = q.Where("(pinned, -position, ?, ?, id) < (?, ?, ?, ?, ?)",
+			bun.Safe(key), bun.Safe(key2),
+			after.Pinned, -after.Position, after.Key, after.Key2, after.ID)
+	}
+
+	return q.
+		OrderExpr("pinned DESC, position ASC, sort_key DESC, sort_key2 DESC, id DESC").
+		Limit(query.Limit)
+}
+
+// searchEncrypted pages through the encrypted history in list order and
+// keeps the rows whose decrypted content, title, notes and tags match the
+// search, until a page is full or the history ends. Types are still filtered
+// in SQL.
+func (r *Repository) searchEncrypted(ctx context.Context, key *vault.Key, query ListQuery, filter listFilter) (*Page, error) {
+	// Search is done here rather than with LIKE, on batches of rows
+	batch := query
+	batch.Limit = max(query.Limit, 100)
+	typesOnly := listFilter{types: filter.types}
+
+	page := &Page{}
+	for {
+		var rows []*listRow
+		if err := r.listRowsQuery(&rows, batch, typesOnly, true).Column("notes").Scan(ctx); err != nil {
+			return nil, fmt.Errorf("failed to search items: %w", err)
+		}
+
+		for _, row := range rows {
+			if err := openItem(key, &row.ClipboardItem); err != nil {
+				return nil, fmt.Errorf("failed to decrypt item %d: %w", row.ID, err)
+			}
+			if !filter.matches(&row.ClipboardItem, row.Preview) {
+				continue
+			}
+
+			row.Preview = truncateRunes(row.Preview, previewLength)
+			row.Notes = ""
+			page.Items = append(page.Items, &row.ClipboardItem)
+			if len(page.Items) == query.Limit {
+				page.Next = row.cursor()
+				return page, nil
+			}
+		}
+
+		if len(rows) < batch.Limit {
+			return page, nil
+		}
+		batch.After = rows[len(rows)-1].cursor()
+	}
+}
+
+func (row *listRow) cursor() *Cursor {
+	return &Cursor{
+		Pinned:   row.Pinned,
+		Position: row.Position,
+		Key:      row.SortKey,
+		Key2:     row.SortKey2,
+		ID:       row.ID,
+	}
+}
+
+// CountItems returns how many items outside the trash match a search term.
+func (r *Repository) CountItems(ctx context.Context, search string) (int, error) {
+	key, err := r.cipherKey()
+	if err != nil {
+		return 0, err
+	}
+	filter := parseSearch(search)
+	if key != nil && filter.decrypts() {
+		return r.countEncrypted(ctx, key, filter)
+	}
+
+	q := r.db.NewSelect().Model((*ClipboardItem)(nil))
+	count, err := applyListFilter(q, filter).Count(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count items: %w", err)
+	}
+
+	return count, nil
+}
+
+// countEncrypted counts the items whose decrypted content, title, notes and
+// tags match the search, among the types of filter.
+func (r *Repository) countEncrypted(ctx context.Context, key *vault.Key, filter listFilter) (int, error) {
+	const batchSize = 500
+	var lastID int64
+	count := 0
+
+	for {
+		var items []*ClipboardItem
+		q := r.db.NewSelect().
+			Model(&items).
+			Column("id", "content", "title", "notes", "tags")
+		if err := applyListFilter(q, listFilter{types: filter.types}).
+			Where("id > ?", lastID).
+			Order("id ASC").
+			Limit(batchSize).
+			Scan(ctx); err != nil {
+			return 0, fmt.Errorf("failed to count items: %w", err)
+		}
+
+		for _, item := range items {
+			if err := openItem(key, item); err != nil {
+				return 0, fmt.Errorf("failed to decrypt item %d: %w", item.ID, err)
+			}
+			if filter.matches(item, item.Content) {
+				count++
+			}
+		}
+
+		if len(items) < batchSize {
+			return count, nil
+		}
+		lastID = items[len(items)-1].ID
+	}
+}
+
+func applyListFilter(q *bun.SelectQuery, filter listFilter) *bun.SelectQuery {
+	q = filter.applyTypes(q.Where("deleted_at IS NULL"))
+	for _, tag := range filter.tags {
+		q = q.Where("instr(',' || tags || ',', ?) > 0", ","+tag+",")
+	}
+	if filter.term != "" {
+		pattern := "%" + filter.term + "%"
+		q = q.Where("content LIKE ? OR title LIKE ? OR notes LIKE ?", pattern, pattern, pattern)
+	}
+	return q
+}
+
+// listFilter is a parsed search: the text to look for, the types to keep
+// from type: tokens such as "type:url" or "type:image,json", and the tags
+// items must have from tag: tokens such as "tag:work".
+type listFilter struct {
+	term  string
+	types []string
+	tags  []string
+}
+
+// decrypts reports whether an encrypted history has to be decrypted to
+// apply the filter, as the search term and tags are encrypted.
+func (f listFilter) decrypts() bool {
+	return f.term != "" || len(f.tags) > 0
+}
+
+// matches reports whether a decrypted item with the given content matches
+// the search term and tags of the filter.
+func (f listFilter) matches(item *ClipboardItem, content string) bool {
+	return matchesSearch(item, content, f.term) && hasTags(item.Tags, f.tags)
+}
+
+// parseSearch splits the type: and tag: tokens out of a search. A type
+// matches an item's type, subtype or code language.
+func parseSearch(search string) listFilter {
+	var filter listFilter
+	var words []string
+	found := false
+	for _, word := range strings.Fields(search) {
+		if value, ok := cutPrefixFold(word, "tag:"); ok {
+			found = true
+			filter.tags = append(filter.tags, SplitTags(NormalizeTags(value))...)
+			continue
+		}
+		value, ok := cutPrefixFold(word, "type:")
+		if !ok {
+			words = append(words, word)
+			continue
+		}
+		found = true
+		for _, t := range strings.Split(strings.ToLower(value), ",") {
+			if aliases, ok := typeAliases[t]; ok {
+				filter.types = append(filter.types, aliases...)
+			} else if t != "" {
+				filter.types = append(filter.types, t)
+			}
+		}
+	}
+
+	// Keep the search term as typed unless tokens were taken out
+	filter.term = search
+	if found {
+		filter.term = strings.Join(words, " ")
+	}
+	return filter
+}
+
+// typeAliases maps alternative names used in type: filters to subtypes.
+var typeAliases = map[string][]string{
+	"colour":  {classify.Color},
+	"link":    {classify.URL},
+	"mail":    {classify.Email},
+	"tel":     {classify.Phone},
+	"file":    {classify.Path},
+	"markup":  {classify.XML, classify.HTML},
+	"js":      {"javascript"},
+	"ts":      {"typescript"},
+	"py":      {"python"},
+	"sh":      {"shell"},
+	"bash":    {"shell"},
+	"c++":     {"cpp"},
+	"c#":      {"csharp"},
+	"images":  {"image"},
+	"picture": {"image"},
+}
+
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) < len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
+
+// applyTypes restricts q to the types of a filter, if any.
+func (f listFilter) applyTypes(q *bun.SelectQuery) *bun.SelectQuery {
+	if len(f.types) == 0 {
+		return q
+	}
+	return q.Where("type IN (?) OR subtype IN (?) OR language IN (?)",
+		bun.In(f.types), bun.In(f.types), bun.In(f.types))
+}
@@ -0,0 +1,264 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+func newTestRepository(t *testing.T) *Repository {
+	t.Helper()
+	repository, err := NewMemoryRepository()
+	if err != nil {
+		t.Fatalf("NewMemoryRepository: %v", err)
+	}
+	t.Cleanup(func() { repository.Close() })
+	return repository
+}
+
+// importTestItems stores text items numbered from 1, oldest first, and
+// returns their IDs in the same order. Every third item shares its
+// timestamp with the previous one, to exercise the ID tie-break.
+func importTestItems(t *testing.T, repository *Repository, n int, edit func(i int, item *ClipboardItem)) []int64 {
+	t.Helper()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	items := make([]*ClipboardItem, n)
+	for i := range items {
+		content := fmt.Sprintf("item %d", i+1)
+		items[i] = &ClipboardItem{
+			Type:      "text",
+			Content:   content,
+			Size:      len(content),
+			Hash:      content,
+			Timestamp: base.Add(time.Duration(i-i/3) * time.Minute),
+		}
+		if edit != nil {
+			edit(i+1, items[i])
+		}
+	}
+	if err := repository.ImportItems(context.Background(), items, nil); err != nil {
+		t.Fatalf("ImportItems: %v", err)
+	}
+
+	ids := make([]int64, n)
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	return ids
+}
+
+// listAll walks every page of a query and returns the IDs in list order.
+func listAll(t *testing.T, repository *Repository, query ListQuery) []int64 {
+	t.Helper()
+	var ids []int64
+	for pages := 0; ; pages++ {
+		if pages > 1000 {
+			t.Fatal("pagination does not end")
+		}
+		page, err := repository.ListItems(context.Background(), query)
+		if err != nil {
+			t.Fatalf("ListItems: %v", err)
+		}
+		if len(page.Items) > query.Limit {
+			t.Fatalf("page of %d items, limit %d", len(page.Items), query.Limit)
+		}
+		for _, item := range page.Items {
+			ids = append(ids, item.ID)
+		}
+		if page.Next == nil {
+			return ids
+		}
+		query.After = page.Next
+	}
+}
+
+func TestListItemsPagination(t *testing.T) {
+	ctx := context.Background()
+	repository := newTestRepository(t)
+	ids := importTestItems(t, repository, 23, func(i int, item *ClipboardItem) {
+		item.CopyCount = i % 4
+		if item.CopyCount > 0 {
+			item.LastUsedAt = item.Timestamp.Add(time.Duration(i%5) * time.Hour)
+		}
+	})
+
+	// Pin two items, the older one first, and trash another
+	for _, id := range []int64{ids[2], ids[10]} {
+		if err := repository.PinItem(ctx, id, true); err != nil {
+			t.Fatalf("PinItem: %v", err)
+		}
+	}
+	if err := repository.SetPinnedOrder(ctx, []int64{ids[2], ids[10]}); err != nil {
+		t.Fatalf("SetPinnedOrder: %v", err)
+	}
+	if err := repository.DeleteItem(ctx, ids[5]); err != nil {
+		t.Fatalf("DeleteItem: %v", err)
+	}
+
+	// Newest first, ties broken by the higher ID, pinned items on top
+	wantRecent := []int64{ids[2], ids[10]}
+	for i := len(ids) - 1; i >= 0; i-- {
+		if i != 2 && i != 10 && i != 5 {
+			wantRecent = append(wantRecent, ids[i])
+		}
+	}
+
+	for _, sort := range []SortMode{SortRecent, SortMostUsed, SortFrecency} {
+		all := listAll(t, repository, ListQuery{Sort: sort, Limit: 1000})
+		if len(all) != len(ids)-1 {
+			t.Fatalf("%s: listed %d items, want %d", sort, len(all), len(ids)-1)
+		}
+		if sort == SortRecent && !slices.Equal(all, wantRecent) {
+			t.Errorf("%s: order = %v, want %v", sort, all, wantRecent)
+		}
+		if !slices.Equal(all[:2], []int64{ids[2], ids[10]}) {
+			t.Errorf("%s: pinned items are not first in their order: %v", sort, all[:2])
+		}
+
+		for _, limit := range []int{1, 2, 5, 7, 22} {
+			if got := listAll(t, repository, ListQuery{Sort: sort, Limit: limit}); !slices.Equal(got, all) {
+				t.Errorf("%s, %d per page: %v, want %v", sort, limit, got, all)
+			}
+		}
+	}
+}
+
+func TestListItemsPaginationEncrypted(t *testing.T) {
+	ctx := context.Background()
+	repository := newTestRepository(t)
+	ids := importTestItems(t, repository, 20, func(i int, item *ClipboardItem) {
+		if i%3 == 0 {
+			item.Title = "Match"
+		}
+	})
+	if err := repository.EnableEncryption(ctx, "passphrase"); err != nil {
+		t.Fatalf("EnableEncryption: %v", err)
+	}
+
+	var want []int64
+	for i := len(ids) - 1; i >= 0; i-- {
+		if (i+1)%3 == 0 {
+			want = append(want, ids[i])
+		}
+	}
+
+	for _, limit := range []int{1, 2, 4, 100} {
+		got := listAll(t, repository, ListQuery{Search: "match", Limit: limit})
+		if !slices.Equal(got, want) {
+			t.Errorf("%d per page: %v, want %v", limit, got, want)
+		}
+	}
+
+	count, err := repository.CountItems(ctx, "match")
+	if err != nil || count != len(want) {
+		t.Errorf("CountItems = %d, %v; want %d", count, err, len(want))
+	}
+}
+
+func TestSortModes(t *testing.T) {
+	repository := newTestRepository(t)
+	now := time.Now().UTC().Truncate(time.Second)
+	day := 24 * time.Hour
+
+	// Items 1 to 4, oldest first
+	uses := []struct {
+		age    time.Duration
+		copies int
+	}{
+		{30 * day, 1}, // Used once, long ago
+		{3 * day, 10}, // Used a lot, a few days ago
+		{2 * day, 2},
+		{1 * day, 0}, // New, never used
+	}
+	ids := importTestItems(t, repository, len(uses), func(i int, item *ClipboardItem) {
+		item.Timestamp = now.Add(-uses[i-1].age)
+		item.CopyCount = uses[i-1].copies
+		if item.CopyCount > 0 {
+			item.LastUsedAt = item.Timestamp
+		}
+	})
+
+	tests := []struct {
+		sort SortMode
+		want []int64
+	}{
+		{SortRecent, []int64{ids[3], ids[2], ids[1], ids[0]}},
+		{SortMostUsed, []int64{ids[1], ids[2], ids[0], ids[3]}},
+		// 7 ln(11) days of boost outweighs being 2 days older, while one
+		// copy is not worth 29 days
+		{SortFrecency, []int64{ids[1], ids[2], ids[3], ids[0]}},
+	}
+
+	for _, tt := range tests {
+		if got := listAll(t, repository, ListQuery{Sort: tt.sort, Limit: 10}); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: %v, want %v", tt.sort, got, tt.want)
+		}
+	}
+
+	// Using an item updates its weight for frecency
+	ctx := context.Background()
+	for range 20 {
+		if err := repository.RecordUsage(ctx, ids[0]); err != nil {
+			t.Fatalf("RecordUsage: %v", err)
+		}
+	}
+	if got := listAll(t, repository, ListQuery{Sort: SortFrecency, Limit: 10}); got[0] != ids[0] {
+		t.Errorf("frecency after use: %v, want item %d first", got, ids[0])
+	}
+}
+
+func TestListItemsSearch(t *testing.T) {
+	ctx := context.Background()
+	repository := newTestRepository(t)
+	ids := importTestItems(t, repository, 4, func(i int, item *ClipboardItem) {
+		switch i {
+		case 1:
+			item.Content = "https://example.com"
+			item.Tags = "work, links"
+		case 2:
+			item.Content = "package main\n\nfunc main() {\n\tx := 1\n}"
+			item.Tags = "work"
+		case 3:
+			item.Notes = "remember the milk"
+		}
+	})
+
+	tests := []struct {
+		search string
+		want   []int64
+	}{
+		{"", []int64{ids[3], ids[2], ids[1], ids[0]}},
+		{"item 4", []int64{ids[3]}},
+		{"MILK", []int64{ids[2]}},
+		{"type:url", []int64{ids[0]}},
+		{"type:link", []int64{ids[0]}},
+		{"type:go", []int64{ids[1]}},
+		{"type:url,go", []int64{ids[1], ids[0]}},
+		{"tag:work", []int64{ids[1], ids[0]}},
+		{"tag:#Work tag:links", []int64{ids[0]}},
+		{"tag:work main", []int64{ids[1]}},
+		{"tag:missing", nil},
+	}
+
+	for _, tt := range tests {
+		page, err := repository.ListItems(ctx, ListQuery{Search: tt.search, Limit: 10})
+		if err != nil {
+			t.Fatalf("ListItems(%q): %v", tt.search, err)
+		}
+		var got []int64
+		for _, item := range page.Items {
+			got = append(got, item.ID)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ListItems(%q) = %v, want %v", tt.search, got, tt.want)
+		}
+
+		count, err := repository.CountItems(ctx, tt.search)
+		if err != nil || count != len(tt.want) {
+			t.Errorf("CountItems(%q) = %d, %v; want %d", tt.search, count, err, len(tt.want))
+		}
+	}
+}
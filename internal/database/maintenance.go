@@ -0,0 +1,192 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// TypeStats counts the items of one type.
+type TypeStats struct {
+	Type         string
+	Items        int   // Items outside the trash
+	Bytes        int64 // Stored size of the items outside the trash
+	Trashed      int
+	TrashedBytes int64
+}
+
+// StorageStats describes the size and contents of the database.
+type StorageStats struct {
+	Path      string
+	FileSize  int64 // Including the write-ahead log, if any
+	PageSize  int64
+	PageCount int64
+	FreePages int64 // Unused pages that VACUUM would release
+
+	Types         []TypeStats // Ordered by stored size, largest first
+	Revisions     int
+	RevisionBytes int64
+	Largest       []*ClipboardItem // List rows, including trashed items
+}
+
+// FreeBytes returns the space held by free pages.
+func (s *StorageStats) FreeBytes() int64 {
+	return s.FreePages * s.PageSize
+}
+
+// storedSizeExpr is the number of bytes an item's data takes in the
+// database, which differs from its Size once encrypted.
+const storedSizeExpr = "LENGTH(COALESCE(content, '')) + LENGTH(COALESCE(image_data, X'')) + " +
+	"LENGTH(COALESCE(title, '')) + LENGTH(COALESCE(notes, ''))"
+
+// StorageStats measures the database. It returns the largest items, up to
+// largest of them.
+func (r *Repository) StorageStats(ctx context.Context, largest int) (*StorageStats, error) {
+	key, err := r.cipherKey()
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &StorageStats{}
+	for pragma, value := range map[string]*int64{
+		"page_size":      &stats.PageSize,
+		"page_count":     &stats.PageCount,
+		"freelist_count": &stats.FreePages,
+	} {
+		if err := r.db.NewRaw("PRAGMA "+pragma).Scan(ctx, value); err != nil {
+			return nil, fmt.Errorf("failed to read database size: %w", err)
+		}
+	}
+
+	stats.Path, err = r.databasePath(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read database size: %w", err)
+	}
+	stats.FileSize = stats.PageCount * stats.PageSize
+	if info, err := os.Stat(stats.Path); err == nil {
+		stats.FileSize = info.Size()
+	}
+	if info, err := os.Stat(stats.Path + "-wal"); err == nil {
+		stats.FileSize += info.Size()
+	}
+
+	err = r.db.NewSelect().
+		Model((*ClipboardItem)(nil)).
+		ColumnExpr("type").
+		ColumnExpr("COUNT(*) FILTER (WHERE deleted_at IS NULL) AS items").
+		ColumnExpr("COALESCE(SUM("+storedSizeExpr+") FILTER (WHERE deleted_at IS NULL), 0) AS bytes").
+		ColumnExpr("COUNT(*) FILTER (WHERE deleted_at IS NOT NULL) AS trashed").
+		ColumnExpr("COALESCE(SUM("+storedSizeExpr+") FILTER (WHERE deleted_at IS NOT NULL), 0) AS trashed_bytes").
+		Group("type").
+		OrderExpr("bytes + trashed_bytes DESC").
+		Scan(ctx, &stats.Types)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count items: %w", err)
+	}
+
+	err = r.db.NewSelect().
+		Model((*ItemRevision)(nil)).
+		ColumnExpr("COUNT(*)").
+		ColumnExpr("COALESCE(SUM(LENGTH(COALESCE(content, ''))), 0)").
+		Scan(ctx, &stats.Revisions, &stats.RevisionBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count revisions: %w", err)
+	}
+
+	err = selectListColumns(r.db.NewSelect().Model(&stats.Largest), key != nil).
+		OrderExpr(storedSizeExpr + " DESC").
+		Limit(largest).
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the largest items: %w", err)
+	}
+	if err := openItems(key, stats.Largest); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// databasePath returns the file the main database is stored in.
+func (r *Repository) databasePath(ctx context.Context) (string, error) {
+	var databases []struct {
+		Seq  int    `bun:"seq"`
+		Name string `bun:"name"`
+		File string `bun:"file"`
+	}
+	if err := r.db.NewRaw("PRAGMA database_list").Scan(ctx, &databases); err != nil {
+		return "", err
+	}
+	for _, db := range databases {
+		if db.Name == "main" {
+			return db.File, nil
+		}
+	}
+	return "", nil
+}
+
+// MaintenanceTask is a database maintenance operation.
+type MaintenanceTask string
+
+const (
+	TaskVacuum         MaintenanceTask = "vacuum"
+	TaskAnalyze        MaintenanceTask = "analyze"
+	TaskIntegrityCheck MaintenanceTask = "integrity_check"
+	TaskReindex        MaintenanceTask = "reindex"
+)
+
+// Description returns what a task does, for display.
+func (t MaintenanceTask) Description() string {
+	switch t {
+	case TaskVacuum:
+		return "Compacting database"
+	case TaskAnalyze:
+		return "Updating query statistics"
+	case TaskIntegrityCheck:
+		return "Checking database integrity"
+	case TaskReindex:
+		return "Rebuilding indexes"
+	default:
+		return string(t)
+	}
+}
+
+// integrityCheckLimit caps the number of problems integrity_check reports.
+const integrityCheckLimit = 100
+
+// RunMaintenance runs a maintenance task. Integrity checks return the
+// problems found, or nil if the database is intact.
+func (r *Repository) RunMaintenance(ctx context.Context, task MaintenanceTask) ([]string, error) {
+	var statement string
+	switch task {
+	case TaskVacuum:
+		statement = "VACUUM"
+	case TaskAnalyze:
+		statement = "ANALYZE"
+	case TaskReindex:
+		statement = "REINDEX"
+	case TaskIntegrityCheck:
+		var results []string
+		if err := r.db.NewRaw("PRAGMA integrity_check(?)", integrityCheckLimit).Scan(ctx, &results); err != nil {
+			return nil, fmt.Errorf("failed to check database integrity: %w", err)
+		}
+		if len(results) == 1 && strings.EqualFold(results[0], "ok") {
+			return nil, nil
+		}
+		return results, nil
+	default:
+		return nil, fmt.Errorf("unknown maintenance task %q", task)
+	}
+
+	// These cannot run inside a transaction, and hold the write lock
+	// throughout, so they take their turn in the write queue
+	err := r.queue(ctx, func(ctx context.Context) error {
+		_, err := r.db.ExecContext(ctx, statement)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s failed: %w", strings.ToLower(task.Description()), err)
+	}
+	return nil, nil
+}
@@ -4,6 +4,18 @@ import (
 	"time"
 
 	"github.com/uptrace/bun"
+
+	"clipboardpro/internal/classify"
+)
+
+// Reasons recorded when an item is moved to the trash.
+const (
+	DeleteReasonUser     = "Deleted"
+	DeleteReasonClearAll = "Cleared all history"
+	DeleteReasonAge      = "Older than history limit"
+	DeleteReasonCount    = "Exceeded maximum items"
+	DeleteReasonSize     = "Exceeded size limit"
+	DeleteReasonQuota    = "Exceeded storage quota"
 )
 
 type ClipboardItem struct {
@@ -11,14 +23,86 @@ type ClipboardItem struct {
 
 	ID        int64     `bun:"id,pk,autoincrement" json:"id"`
 	Type      string    `bun:"type,notnull" json:"type"`
+	Subtype   string    `bun:"subtype" json:"subtype,omitempty"`   // What a text item holds, see package classify
+	Language  string    `bun:"language" json:"language,omitempty"` // Language guessed for code
 	Content   string    `bun:"content" json:"content"`
 	ImageData []byte    `bun:"image_data" json:"-"`
 	Timestamp time.Time `bun:"timestamp,notnull,default:current_timestamp" json:"timestamp"`
 	Size      int       `bun:"size,notnull" json:"size"`
 	Hash      string    `bun:"hash,unique,notnull" json:"hash"`
 	Pinned    bool      `bun:"pinned,default:false" json:"pinned"`
+	Position  int       `bun:"position,notnull,default:0" json:"position,omitempty"` // Order among pinned items, lowest first
 	Title     string    `bun:"title" json:"title"`
+	Notes     string    `bun:"notes" json:"notes,omitempty"`
+	Tags      string    `bun:"tags" json:"tags,omitempty"`             // Comma-separated, see NormalizeTags
+	SourceApp string    `bun:"source_app" json:"source_app,omitempty"` // Application copied from, if known
+
+	CopyCount  int       `bun:"copy_count,notnull,default:0" json:"copy_count"`
+	LastUsedAt time.Time `bun:"last_used_at,nullzero" json:"last_used_at,omitempty"`
+	// UsageWeight is the frecency boost for CopyCount, see usageWeight.
+	UsageWeight float64 `bun:"usage_weight,notnull,default:0" json:"-"`
+
+	// ExpiresAt is when the item is deleted for good, if set. BurnAfterCopy
+	// items are deleted for good once they have been copied.
+	ExpiresAt     time.Time `bun:"expires_at,nullzero" json:"expires_at,omitempty"`
+	BurnAfterCopy bool      `bun:"burn_after_copy,notnull,default:false" json:"burn_after_copy,omitempty"`
+
+	DeletedAt    time.Time `bun:"deleted_at,nullzero" json:"deleted_at,omitempty"`
+	DeleteReason string    `bun:"delete_reason" json:"delete_reason,omitempty"`
 
 	CreatedAt time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"created_at"`
 	UpdatedAt time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp" json:"updated_at"`
+
+	// Preview holds the start of Content for items loaded as list rows, which
+	// leave Content, ImageData and Notes empty. Use GetItemByID for the full item.
+	Preview string `bun:"preview,scanonly" json:"-"`
+	// StoredBytes is the space the item and its revisions take in the
+	// database, for items loaded by GetRetentionItems.
+	StoredBytes int64 `bun:"stored_bytes,scanonly" json:"-"`
+}
+
+// SensitiveTag marks an item as sensitive by hand.
+const SensitiveTag = "sensitive"
+
+// IsSensitive reports whether an item holds a secret that should not leave
+// the app by default: a JWT, an item that expires or is deleted after its
+// first copy, or an item tagged SensitiveTag.
+func (i *ClipboardItem) IsSensitive() bool {
+	return i.Subtype == classify.JWT || i.BurnAfterCopy || !i.ExpiresAt.IsZero() ||
+		hasTags(i.Tags, []string{SensitiveTag})
+}
+
+// PreviewText returns the beginning of the item's text content, whether the
+// item was loaded in full or as a list row.
+func (i *ClipboardItem) PreviewText() string {
+	if i.Content != "" {
+		return i.Content
+	}
+	return i.Preview
+}
+
+// IsTrashed reports whether the item has been moved to the trash.
+func (i *ClipboardItem) IsTrashed() bool {
+	return !i.DeletedAt.IsZero()
+}
+
+// ItemRevision keeps a previous version of a text item's content.
+type ItemRevision struct {
+	bun.BaseModel `bun:"table:item_revisions"`
+
+	ID      int64  `bun:"id,pk,autoincrement" json:"id"`
+	ItemID  int64  `bun:"item_id,notnull" json:"item_id"`
+	Content string `bun:"content" json:"content"`
+	Size    int    `bun:"size,notnull" json:"size"`
+	Hash    string `bun:"hash,notnull" json:"hash"`
+
+	CreatedAt time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"created_at"`
+}
+
+// Meta stores database-wide settings, such as the encryption parameters.
+type Meta struct {
+	bun.BaseModel `bun:"table:meta"`
+
+	Key   string `bun:"key,pk"`
+	Value string `bun:"value,notnull"`
 }
@@ -3,33 +3,109 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect/sqlitedialect"
 	"github.com/uptrace/bun/driver/sqliteshim"
 
+	"clipboardpro/internal/classify"
 	"clipboardpro/internal/util"
+	"clipboardpro/internal/vault"
 )
 
+// ErrDuplicateContent is returned when an edit would give an item the same
+// content as another item.
+var ErrDuplicateContent = errors.New("another item already has this content")
+
+// ErrDuplicateInTrash is returned when an edit would give an item the same
+// content as an item in the trash, unless that item may be replaced.
+var ErrDuplicateInTrash = errors.New("an item in the trash already has this content")
+
 type Repository struct {
 	db *bun.DB
+
+	writes    chan writeRequest // Queue of writes, performed one at a time
+	closed    chan struct{}
+	closeOnce sync.Once
+
+	changes atomic.Uint64 // Writes queued by this process, for Version
+	watch   *sql.Conn     // Connection reading data_version; nil in memory
+
+	mu         sync.RWMutex    // Guards the encryption state
+	encryption *encryptionMeta // Nil unless the history is encrypted
+	key        *vault.Key      // Nil while locked or not encrypted
 }
 
+// NewRepository opens the history database stored at dbPath.
 func NewRepository(dbPath string) (*Repository, error) {
-	sqldb, err := sql.Open(sqliteshim.ShimName, dbPath)
+	sqldb, err := sql.Open(sqliteshim.ShimName, dataSourceName(dbPath))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
+	repo, err := newRepository(sqldb)
+	if err != nil {
+		return nil, err
+	}
+
+	// data_version only moves for commits made on other connections, so it
+	// is read on a connection kept for nothing else
+	repo.watch, err = sqldb.Conn(context.Background())
+	if err != nil {
+		repo.Close()
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	return repo, nil
+}
+
+// NewMemoryRepository returns a repository that keeps the history in memory
+// only, for ephemeral sessions. Nothing is written to disk and the history
+// is gone once the repository is closed.
+func NewMemoryRepository() (*Repository, error) {
+	sqldb, err := sql.Open(sqliteshim.ShimName, dataSourceName(":memory:"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// Every connection to :memory: is a database of its own, so the pool
+	// keeps exactly one, which must never be closed
+	sqldb.SetMaxOpenConns(1)
+	sqldb.SetMaxIdleConns(1)
+	sqldb.SetConnMaxLifetime(0)
+	sqldb.SetConnMaxIdleTime(0)
+
+	// Keep temporary tables and indexes used by large sorts off disk too
+	if _, err := sqldb.Exec("PRAGMA temp_store = MEMORY"); err != nil {
+		sqldb.Close()
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	return newRepository(sqldb)
+}
+
+func newRepository(sqldb *sql.DB) (*Repository, error) {
 	db := bun.NewDB(sqldb, sqlitedialect.New())
 
-	repo := &Repository{db: db}
+	repo := &Repository{
+		db:     db,
+		writes: make(chan writeRequest),
+		closed: make(chan struct{}),
+	}
+	go repo.runWriter()
 
 	if err := repo.migrate(); err != nil {
+		repo.Close()
 		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
+	if err := repo.loadEncryption(context.Background()); err != nil {
+		repo.Close()
+		return nil, err
+	}
 
 	return repo, nil
 }
@@ -40,6 +116,8 @@ func (r *Repository) migrate() error {
 	// Create tables
 	models := []interface{}{
 		(*ClipboardItem)(nil),
+		(*ItemRevision)(nil),
+		(*Meta)(nil),
 	}
 
 	for _, model := range models {
@@ -48,12 +126,30 @@ func (r *Repository) migrate() error {
 		}
 	}
 
+	// Add columns introduced after the initial schema
+	for _, col := range r.addedColumns() {
+		added, err := r.addColumnIfMissing(ctx, col.table, col.name, col.definition)
+		if err != nil {
+			return fmt.Errorf("failed to add column %s.%s: %w", col.table, col.name, err)
+		}
+		if added && col.backfill != nil {
+			if err := col.backfill(ctx); err != nil {
+				return fmt.Errorf("failed to backfill column %s.%s: %w", col.table, col.name, err)
+			}
+		}
+	}
+
 	// Create indexes
 	indexes := []string{
 		"CREATE INDEX IF NOT EXISTS idx_clipboard_timestamp ON clipboard_items(timestamp DESC)",
 		"CREATE INDEX IF NOT EXISTS idx_clipboard_hash ON clipboard_items(hash)",
 		"CREATE INDEX IF NOT EXISTS idx_clipboard_pinned ON clipboard_items(pinned)",
 		"CREATE INDEX IF NOT EXISTS idx_clipboard_type ON clipboard_items(type)",
+		"CREATE INDEX IF NOT EXISTS idx_clipboard_subtype ON clipboard_items(subtype)",
+		"CREATE INDEX IF NOT EXISTS idx_clipboard_copy_count ON clipboard_items(copy_count DESC)",
+		"CREATE INDEX IF NOT EXISTS idx_clipboard_deleted_at ON clipboard_items(deleted_at)",
+		"CREATE INDEX IF NOT EXISTS idx_clipboard_expires_at ON clipboard_items(expires_at)",
+		"CREATE INDEX IF NOT EXISTS idx_revisions_item ON item_revisions(item_id, created_at DESC)",
 	}
 
 	for _, idx := range indexes {
@@ -65,83 +161,156 @@ func (r *Repository) migrate() error {
 	return nil
 }
 
-func (r *Repository) SaveClipboardItem(ctx context.Context, item *ClipboardItem) error {
-	// Generate hash if not provided
-	if item.Hash == "" {
-		item.Hash = util.GenerateHash(item.Content, item.ImageData)
+// addedColumn is a column introduced after the initial schema.
+type addedColumn struct {
+	table, name, definition string
+	backfill                func(context.Context) error // Runs once, after the column is added
+}
+
+func (r *Repository) addedColumns() []addedColumn {
+	return []addedColumn{
+		{"clipboard_items", "copy_count", "INTEGER NOT NULL DEFAULT 0", nil},
+		{"clipboard_items", "last_used_at", "TIMESTAMP", nil},
+		{"clipboard_items", "deleted_at", "TIMESTAMP", nil},
+		{"clipboard_items", "delete_reason", "VARCHAR", nil},
+		{"clipboard_items", "notes", "VARCHAR", nil},
+		{"clipboard_items", "position", "INTEGER NOT NULL DEFAULT 0", r.orderPinnedItems},
+		{"clipboard_items", "expires_at", "TIMESTAMP", nil},
+		{"clipboard_items", "burn_after_copy", "BOOLEAN NOT NULL DEFAULT FALSE", nil},
+		{"clipboard_items", "subtype", "VARCHAR", nil},
+		{"clipboard_items", "language", "VARCHAR", r.classifyItems},
+		{"clipboard_items", "usage_weight", "REAL NOT NULL DEFAULT 0", r.weighUsage},
+		{"clipboard_items", "tags", "VARCHAR", nil},
+		{"clipboard_items", "source_app", "VARCHAR", nil},
 	}
+}
 
-	// Check if item with same hash already exists
-	exists, err := r.db.NewSelect().
+// orderPinnedItems gives items pinned before manual ordering existed
+// positions matching their previous newest-first order.
+func (r *Repository) orderPinnedItems(ctx context.Context) error {
+	var ids []int64
+	if err := r.db.NewSelect().
 		Model((*ClipboardItem)(nil)).
-		Where("hash = ?", item.Hash).
-		Exists(ctx)
+		Column("id").
+		Where("pinned = TRUE").
+		Order("timestamp DESC").
+		Scan(ctx, &ids); err != nil {
+		return err
+	}
+
+	return r.SetPinnedOrder(ctx, ids)
+}
+
+// addColumnIfMissing adds a column to an existing table and reports whether
+// it had to be added.
+func (r *Repository) addColumnIfMissing(ctx context.Context, table, column, definition string) (bool, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&count)
 	if err != nil {
-		return fmt.Errorf("failed to check existing item: %w", err)
+		return false, err
+	}
+	if count > 0 {
+		return false, nil
 	}
 
-	if exists {
-		// Update timestamp to move to top
-		_, err = r.db.NewUpdate().
-			Model((*ClipboardItem)(nil)).
-			Set("timestamp = ?", time.Now()).
-			Set("updated_at = ?", time.Now()).
-			Where("hash = ?", item.Hash).
-			Exec(ctx)
+	_, err = r.db.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	return err == nil, err
+}
+
+func (r *Repository) SaveClipboardItem(ctx context.Context, item *ClipboardItem) error {
+	key, err := r.cipherKey()
+	if err != nil {
 		return err
 	}
 
+	// Generate hash if not provided
+	if item.Hash == "" {
+		item.Hash = util.GenerateHash(item.Content, item.ImageData)
+	}
+	hash := storedHash(key, item.Hash)
+
 	// Set timestamps
 	now := time.Now()
 	item.Timestamp = now
 	item.CreatedAt = now
 	item.UpdatedAt = now
+	classifyItem(item)
 
-	// Insert new item
-	_, err = r.db.NewInsert().Model(item).Exec(ctx)
+	sealed, err := sealItem(key, item)
 	if err != nil {
-		return fmt.Errorf("failed to insert clipboard item: %w", err)
+		return fmt.Errorf("failed to encrypt clipboard item: %w", err)
 	}
 
-	return nil
-}
+	// Checking and inserting in one write keeps a second process from
+	// inserting the same content in between
+	return r.write(ctx, func(ctx context.Context, tx bun.Tx) error {
+		// Check if item with same hash already exists
+		exists, err := tx.NewSelect().
+			Model((*ClipboardItem)(nil)).
+			Where("hash = ?", hash).
+			Exists(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to check existing item: %w", err)
+		}
 
-func (r *Repository) GetRecentItems(ctx context.Context, limit int) ([]*ClipboardItem, error) {
-	var items []*ClipboardItem
+		if exists {
+			// Update timestamp to move to top, restoring the item if it was trashed
+			query := tx.NewUpdate().
+				Model((*ClipboardItem)(nil)).
+				Set("timestamp = ?", now).
+				Set("updated_at = ?", now).
+				Set("deleted_at = NULL").
+				Set("delete_reason = ''").
+				Where("hash = ?", hash)
+			if !item.ExpiresAt.IsZero() {
+				query = query.Set("expires_at = ?", item.ExpiresAt)
+			}
+			if item.BurnAfterCopy {
+				query = query.Set("burn_after_copy = TRUE")
+			}
+			_, err = query.Exec(ctx)
+			return err
+		}
 
-	err := r.db.NewSelect().
-		Model(&items).
-		Order("pinned DESC", "timestamp DESC").
-		Limit(limit).
-		Scan(ctx)
+		// Insert new item
+		if _, err := tx.NewInsert().Model(sealed).Exec(ctx); err != nil {
+			return fmt.Errorf("failed to insert clipboard item: %w", err)
+		}
+		item.ID = sealed.ID
+
+		return nil
+	})
+}
 
+// GetRecentItems returns the first page of the history.
+func (r *Repository) GetRecentItems(ctx context.Context, limit int, sort SortMode) ([]*ClipboardItem, error) {
+	page, err := r.ListItems(ctx, ListQuery{Sort: sort, Limit: limit})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get recent items: %w", err)
 	}
 
-	return items, nil
+	return page.Items, nil
 }
 
-func (r *Repository) SearchItems(ctx context.Context, query string, limit int) ([]*ClipboardItem, error) {
-	var items []*ClipboardItem
-
-	err := r.db.NewSelect().
-		Model(&items).
-		Where("content LIKE ? OR title LIKE ?", "%"+query+"%", "%"+query+"%").
-		Order("pinned DESC", "timestamp DESC").
-		Limit(limit).
-		Scan(ctx)
-
+// SearchItems returns the first page of items matching query.
+func (r *Repository) SearchItems(ctx context.Context, query string, limit int, sort SortMode) ([]*ClipboardItem, error) {
+	page, err := r.ListItems(ctx, ListQuery{Search: query, Sort: sort, Limit: limit})
 	if err != nil {
 		return nil, fmt.Errorf("failed to search items: %w", err)
 	}
 
-	return items, nil
+	return page.Items, nil
 }
 
 func (r *Repository) GetItemByID(ctx context.Context, id int64) (*ClipboardItem, error) {
+	key, err := r.cipherKey()
+	if err != nil {
+		return nil, err
+	}
+
 	var item ClipboardItem
-	err := r.db.NewSelect().
+	err = r.db.NewSelect().
 		Model(&item).
 		Where("id = ?", id).
 		Scan(ctx)
@@ -149,30 +318,384 @@ func (r *Repository) GetItemByID(ctx context.Context, id int64) (*ClipboardItem,
 	if err != nil {
 		return nil, fmt.Errorf("failed to get item by ID: %w", err)
 	}
+	if err := openFullItem(key, &item); err != nil {
+		return nil, fmt.Errorf("failed to decrypt item: %w", err)
+	}
 
 	return &item, nil
 }
 
+// TogglePin pins or unpins an item, as PinItem does with the opposite of
+// its current state.
 func (r *Repository) TogglePin(ctx context.Context, id int64) error {
-	_, err := r.db.NewUpdate().
+	err := r.write(ctx, func(ctx context.Context, tx bun.Tx) error {
+		var pinned bool
+		if err := tx.NewSelect().
+			Model((*ClipboardItem)(nil)).
+			Column("pinned").
+			Where("id = ?", id).
+			Scan(ctx, &pinned); err != nil {
+			return err
+		}
+		return pinItem(ctx, tx, id, !pinned)
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to toggle pin: %w", err)
+	}
+
+	return nil
+}
+
+// PinItem pins or unpins an item. A newly pinned item goes to the top of
+// the pinned items; an item that is already pinned keeps its position, and
+// unpinned items lose theirs.
+func (r *Repository) PinItem(ctx context.Context, id int64, pinned bool) error {
+	err := r.write(ctx, func(ctx context.Context, tx bun.Tx) error {
+		return pinItem(ctx, tx, id, pinned)
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to pin item: %w", err)
+	}
+
+	return nil
+}
+
+func pinItem(ctx context.Context, tx bun.Tx, id int64, pinned bool) error {
+	q := tx.NewUpdate().
 		Model((*ClipboardItem)(nil)).
-		Set("pinned = NOT pinned").
 		Set("updated_at = ?", time.Now()).
-		Where("id = ?", id).
-		Exec(ctx)
+		Where("id = ?", id)
+	if pinned {
+		q = q.Set("position = CASE WHEN pinned THEN position ELSE " +
+			"(SELECT COALESCE(MIN(position), 1) - 1 FROM clipboard_items WHERE pinned = TRUE) END").
+			Set("pinned = TRUE")
+	} else {
+		q = q.Set("pinned = FALSE").Set("position = 0")
+	}
+	_, err := q.Exec(ctx)
+	return err
+}
+
+// SetPinned sets the pinned state and position of an item directly, for
+// example to restore it after an undo.
+func (r *Repository) SetPinned(ctx context.Context, id int64, pinned bool, position int) error {
+	if !pinned {
+		position = 0
+	}
+
+	err := r.write(ctx, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewUpdate().
+			Model((*ClipboardItem)(nil)).
+			Set("pinned = ?", pinned).
+			Set("position = ?", position).
+			Set("updated_at = ?", time.Now()).
+			Where("id = ?", id).
+			Exec(ctx)
+		return err
+	})
 
 	if err != nil {
-		return fmt.Errorf("failed to toggle pin: %w", err)
+		return fmt.Errorf("failed to set pin: %w", err)
 	}
 
 	return nil
 }
 
+// MovePinnedItem swaps a pinned item with its neighbour above (offset < 0)
+// or below (offset > 0) and reports whether it moved. Items already at the
+// edge stay where they are.
+func (r *Repository) MovePinnedItem(ctx context.Context, id int64, offset int) (bool, error) {
+	moved := false
+	err := r.write(ctx, func(ctx context.Context, tx bun.Tx) error {
+		var item ClipboardItem
+		if err := tx.NewSelect().Model(&item).Where("id = ? AND pinned = TRUE", id).Scan(ctx); err != nil {
+			return err
+		}
+
+		neighbourQuery := tx.NewSelect().
+			Model((*ClipboardItem)(nil)).
+			Where("pinned = TRUE AND deleted_at IS NULL AND id != ?", id).
+			Limit(1)
+		if offset < 0 {
+			neighbourQuery = neighbourQuery.Where("position < ?", item.Position).OrderExpr("position DESC")
+		} else {
+			neighbourQuery = neighbourQuery.Where("position > ?", item.Position).OrderExpr("position ASC")
+		}
+
+		var neighbour ClipboardItem
+		if err := neighbourQuery.Scan(ctx, &neighbour); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil
+			}
+			return err
+		}
+
+		for _, update := range []struct {
+			id       int64
+			position int
+		}{{item.ID, neighbour.Position}, {neighbour.ID, item.Position}} {
+			if _, err := tx.NewUpdate().
+				Model((*ClipboardItem)(nil)).
+				Set("position = ?", update.position).
+				Where("id = ?", update.id).
+				Exec(ctx); err != nil {
+				return err
+			}
+		}
+		moved = true
+		return nil
+	})
+
+	if err != nil {
+		return false, fmt.Errorf("failed to move pinned item: %w", err)
+	}
+
+	return moved, nil
+}
+
+// SetPinnedOrder assigns consecutive positions to the given pinned items in order.
+func (r *Repository) SetPinnedOrder(ctx context.Context, ids []int64) error {
+	err := r.write(ctx, func(ctx context.Context, tx bun.Tx) error {
+		for i, id := range ids {
+			if _, err := tx.NewUpdate().
+				Model((*ClipboardItem)(nil)).
+				Set("position = ?", i+1).
+				Where("id = ? AND pinned = TRUE", id).
+				Exec(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to set pinned order: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateNotes sets the Markdown notes of an item.
+func (r *Repository) UpdateNotes(ctx context.Context, id int64, notes string) error {
+	key, err := r.cipherKey()
+	if err != nil {
+		return err
+	}
+	if notes, err = encryptString(key, notes); err != nil {
+		return fmt.Errorf("failed to update notes: %w", err)
+	}
+
+	err = r.write(ctx, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewUpdate().
+			Model((*ClipboardItem)(nil)).
+			Set("notes = ?", notes).
+			Set("updated_at = ?", time.Now()).
+			Where("id = ?", id).
+			Exec(ctx)
+		return err
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to update notes: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateContent replaces the content of a text item, keeping the previous
+// content as a revision. The item's hash is recomputed so deduplication keeps
+// working; ErrDuplicateContent is returned if another item already has the
+// new content. A trashed item with the new content is deleted for good if
+// replaceTrashed is set, and otherwise ErrDuplicateInTrash is returned.
+func (r *Repository) UpdateContent(ctx context.Context, id int64, content string, replaceTrashed bool) error {
+	key, err := r.cipherKey()
+	if err != nil {
+		return err
+	}
+
+	err = r.write(ctx, func(ctx context.Context, tx bun.Tx) error {
+		var item ClipboardItem
+		if err := tx.NewSelect().Model(&item).Where("id = ?", id).Scan(ctx); err != nil {
+			return err
+		}
+
+		// The revision keeps the content exactly as stored
+		storedContent := item.Content
+		if err := openItem(key, &item); err != nil {
+			return err
+		}
+
+		if item.Type != "text" {
+			return fmt.Errorf("cannot edit content of %s items", item.Type)
+		}
+		if item.Content == content {
+			return nil
+		}
+
+		hash := storedHash(key, util.GenerateHash(content, nil))
+		trashedCopy := "hash = ? AND id != ? AND deleted_at IS NOT NULL"
+		if !replaceTrashed {
+			inTrash, err := tx.NewSelect().
+				Model((*ClipboardItem)(nil)).
+				Where(trashedCopy, hash, id).
+				Exists(ctx)
+			if err != nil {
+				return err
+			}
+			if inTrash {
+				return ErrDuplicateInTrash
+			}
+		}
+		if _, err := tx.NewDelete().
+			Model((*ItemRevision)(nil)).
+			Where("item_id IN (SELECT id FROM clipboard_items WHERE "+trashedCopy+")", hash, id).
+			Exec(ctx); err != nil {
+			return err
+		}
+		if _, err := tx.NewDelete().
+			Model((*ClipboardItem)(nil)).
+			Where(trashedCopy, hash, id).
+			Exec(ctx); err != nil {
+			return err
+		}
+
+		exists, err := tx.NewSelect().
+			Model((*ClipboardItem)(nil)).
+			Where("hash = ? AND id != ?", hash, id).
+			Exists(ctx)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return ErrDuplicateContent
+		}
+
+		now := time.Now()
+		revision := &ItemRevision{
+			ItemID:    item.ID,
+			Content:   storedContent,
+			Size:      item.Size,
+			Hash:      item.Hash,
+			CreatedAt: now,
+		}
+		if _, err := tx.NewInsert().Model(revision).Exec(ctx); err != nil {
+			return err
+		}
+
+		sealedContent, err := encryptString(key, content)
+		if err != nil {
+			return err
+		}
+
+		subtype, language := classify.Text(content)
+		_, err = tx.NewUpdate().
+			Model((*ClipboardItem)(nil)).
+			Set("content = ?", sealedContent).
+			Set("subtype = ?", subtype).
+			Set("language = ?", language).
+			Set("size = ?", len(content)).
+			Set("hash = ?", hash).
+			Set("updated_at = ?", now).
+			Where("id = ?", id).
+			Exec(ctx)
+		return err
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to update content: %w", err)
+	}
+
+	return nil
+}
+
+// GetRevisions returns the previous versions of an item, newest first.
+func (r *Repository) GetRevisions(ctx context.Context, itemID int64) ([]*ItemRevision, error) {
+	key, err := r.cipherKey()
+	if err != nil {
+		return nil, err
+	}
+
+	var revisions []*ItemRevision
+
+	err = r.db.NewSelect().
+		Model(&revisions).
+		Where("item_id = ?", itemID).
+		Order("created_at DESC", "id DESC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revisions: %w", err)
+	}
+	for _, revision := range revisions {
+		if err := openRevision(key, revision); err != nil {
+			return nil, fmt.Errorf("failed to decrypt revision: %w", err)
+		}
+	}
+
+	return revisions, nil
+}
+
+// RevertToRevision restores the content of a revision. The content being
+// replaced is kept as a new revision. A trashed item with the same content
+// is handled as in UpdateContent.
+func (r *Repository) RevertToRevision(ctx context.Context, revisionID int64, replaceTrashed bool) error {
+	key, err := r.cipherKey()
+	if err != nil {
+		return err
+	}
+
+	var revision ItemRevision
+	err = r.db.NewSelect().
+		Model(&revision).
+		Where("id = ?", revisionID).
+		Scan(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to get revision: %w", err)
+	}
+	if err := openRevision(key, &revision); err != nil {
+		return fmt.Errorf("failed to decrypt revision: %w", err)
+	}
+
+	return r.UpdateContent(ctx, revision.ItemID, revision.Content, replaceTrashed)
+}
+
+// RecordUsage increments the copy count of an item and sets its last-used time.
+func (r *Repository) RecordUsage(ctx context.Context, id int64) error {
+	err := r.write(ctx, func(ctx context.Context, tx bun.Tx) error {
+		var copies int
+		if err := tx.NewSelect().
+			Model((*ClipboardItem)(nil)).
+			Column("copy_count").
+			Where("id = ?", id).
+			Scan(ctx, &copies); err != nil {
+			return err
+		}
+
+		_, err := tx.NewUpdate().
+			Model((*ClipboardItem)(nil)).
+			Set("copy_count = ?", copies+1).
+			Set("usage_weight = ?", usageWeight(copies+1)).
+			Set("last_used_at = ?", time.Now()).
+			Where("id = ?", id).
+			Exec(ctx)
+		return err
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to record usage: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteItem moves an item to the trash.
 func (r *Repository) DeleteItem(ctx context.Context, id int64) error {
-	_, err := r.db.NewDelete().
-		Model((*ClipboardItem)(nil)).
-		Where("id = ?", id).
-		Exec(ctx)
+	_, err := r.trashItems(ctx, DeleteReasonUser, func(q *bun.UpdateQuery) *bun.UpdateQuery {
+		return q.Where("id = ?", id)
+	})
 
 	if err != nil {
 		return fmt.Errorf("failed to delete item: %w", err)
@@ -181,13 +704,49 @@ func (r *Repository) DeleteItem(ctx context.Context, id int64) error {
 	return nil
 }
 
+// trashItems marks the items selected by filter as deleted with the given
+// reason and returns how many were moved to the trash.
+func (r *Repository) trashItems(ctx context.Context, reason string, filter func(*bun.UpdateQuery) *bun.UpdateQuery) (int64, error) {
+	var trashed int64
+	err := r.write(ctx, func(ctx context.Context, tx bun.Tx) error {
+		now := time.Now()
+		query := tx.NewUpdate().
+			Model((*ClipboardItem)(nil)).
+			Set("deleted_at = ?", now).
+			Set("delete_reason = ?", reason).
+			Set("updated_at = ?", now).
+			Where("deleted_at IS NULL")
+
+		res, err := filter(query).Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		trashed, err = res.RowsAffected()
+		return err
+	})
+
+	return trashed, err
+}
+
 func (r *Repository) UpdateTitle(ctx context.Context, id int64, title string) error {
-	_, err := r.db.NewUpdate().
-		Model((*ClipboardItem)(nil)).
-		Set("title = ?", title).
-		Set("updated_at = ?", time.Now()).
-		Where("id = ?", id).
-		Exec(ctx)
+	key, err := r.cipherKey()
+	if err != nil {
+		return err
+	}
+	if title, err = encryptString(key, title); err != nil {
+		return fmt.Errorf("failed to update title: %w", err)
+	}
+
+	err = r.write(ctx, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewUpdate().
+			Model((*ClipboardItem)(nil)).
+			Set("title = ?", title).
+			Set("updated_at = ?", time.Now()).
+			Where("id = ?", id).
+			Exec(ctx)
+		return err
+	})
 
 	if err != nil {
 		return fmt.Errorf("failed to update title: %w", err)
@@ -196,47 +755,290 @@ func (r *Repository) UpdateTitle(ctx context.Context, id int64, title string) er
 	return nil
 }
 
-func (r *Repository) CleanupOldItems(ctx context.Context, maxDays int, maxItems int) error {
-	cutoffDate := time.Now().AddDate(0, 0, -maxDays)
+// ClearAllItems moves every item, pinned or not, to the trash and returns the
+// IDs of the items it moved.
+func (r *Repository) ClearAllItems(ctx context.Context) ([]int64, error) {
+	var ids []int64
+	err := r.write(ctx, func(ctx context.Context, tx bun.Tx) error {
+		if err := tx.NewSelect().
+			Model((*ClipboardItem)(nil)).
+			Column("id").
+			Where("deleted_at IS NULL").
+			Scan(ctx, &ids); err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
 
-	// Delete old unpinned items
-	_, err := r.db.NewDelete().
-		Model((*ClipboardItem)(nil)).
-		Where("timestamp < ? AND pinned = FALSE", cutoffDate).
-		Exec(ctx)
+		now := time.Now()
+		_, err := tx.NewUpdate().
+			Model((*ClipboardItem)(nil)).
+			Set("deleted_at = ?", now).
+			Set("delete_reason = ?", DeleteReasonClearAll).
+			Set("updated_at = ?", now).
+			Where("id IN (?)", bun.In(ids)).
+			Exec(ctx)
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("failed to delete old items: %w", err)
+		return nil, fmt.Errorf("failed to clear all items: %w", err)
 	}
+	return ids, nil
+}
 
-	// Keep only the most recent items (excluding pinned)
-	subquery := r.db.NewSelect().
-		Model((*ClipboardItem)(nil)).
-		Column("id").
-		Where("pinned = FALSE").
-		Order("timestamp DESC").
-		Limit(maxItems)
+// TrashItems moves the given items to the trash with the given reason.
+func (r *Repository) TrashItems(ctx context.Context, ids []int64, reason string) error {
+	if len(ids) == 0 {
+		return nil
+	}
 
-	_, err = r.db.NewDelete().
-		Model((*ClipboardItem)(nil)).
-		Where("pinned = FALSE").
-		Where("id NOT IN (?)", subquery).
-		Exec(ctx)
+	_, err := r.trashItems(ctx, reason, func(q *bun.UpdateQuery) *bun.UpdateQuery {
+		return q.Where("id IN (?)", bun.In(ids))
+	})
 
 	if err != nil {
-		return fmt.Errorf("failed to cleanup excess items: %w", err)
+		return fmt.Errorf("failed to trash items: %w", err)
 	}
 
 	return nil
 }
 
-func (r *Repository) ClearAllItems(ctx context.Context) error {
-	_, err := r.db.NewDelete().Model((*ClipboardItem)(nil)).Where("1=1").Exec(ctx)
+// ExportFilter selects the items visited by ForEachItem.
+type ExportFilter struct {
+	From       time.Time // Zero for no lower bound
+	To         time.Time // Zero for no upper bound
+	Type       string    // Empty for all types
+	Tags       string    // Items must have all of these tags; empty for any
+	PinnedOnly bool
+
+	// IncludeSensitive includes items for which IsSensitive is true, which
+	// are left out by default.
+	IncludeSensitive bool
+}
+
+// ForEachItem calls fn with every item outside the trash that matches
+// filter, oldest first. Items are loaded in full, in batches.
+func (r *Repository) ForEachItem(ctx context.Context, filter ExportFilter, fn func(*ClipboardItem) error) error {
+	key, err := r.cipherKey()
 	if err != nil {
-		return fmt.Errorf("failed to clear all items: %w", err)
+		return err
 	}
+
+	const batchSize = 100
+	var lastID int64
+	tags := SplitTags(NormalizeTags(filter.Tags))
+
+	for {
+		var items []*ClipboardItem
+		q := r.db.NewSelect().
+			Model(&items).
+			Where("deleted_at IS NULL").
+			Where("id > ?", lastID).
+			Order("id ASC").
+			Limit(batchSize)
+
+		if !filter.From.IsZero() {
+			q = q.Where("julianday(timestamp) >= julianday(?)", filter.From)
+		}
+		if !filter.To.IsZero() {
+			q = q.Where("julianday(timestamp) < julianday(?)", filter.To)
+		}
+		if filter.Type != "" {
+			q = q.Where("type = ?", filter.Type)
+		}
+		if filter.PinnedOnly {
+			q = q.Where("pinned = TRUE")
+		}
+
+		if err := q.Scan(ctx); err != nil {
+			return fmt.Errorf("failed to load items: %w", err)
+		}
+		for _, item := range items {
+			if err := openFullItem(key, item); err != nil {
+				return fmt.Errorf("failed to decrypt item %d: %w", item.ID, err)
+			}
+			// Tags may be encrypted, so they are matched here rather than in SQL
+			if !hasTags(item.Tags, tags) || (item.IsSensitive() && !filter.IncludeSensitive) {
+				continue
+			}
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+
+		if len(items) < batchSize {
+			return nil
+		}
+		lastID = items[len(items)-1].ID
+	}
+}
+
+// GetTrashedItems returns items in the trash as list rows, most recently
+// deleted first.
+func (r *Repository) GetTrashedItems(ctx context.Context, limit int) ([]*ClipboardItem, error) {
+	key, err := r.cipherKey()
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*ClipboardItem
+
+	err = selectListColumns(r.db.NewSelect().Model(&items), key != nil).
+		Where("deleted_at IS NOT NULL").
+		Order("deleted_at DESC").
+		Limit(limit).
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trashed items: %w", err)
+	}
+	if err := openItems(key, items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// RestoreItem moves an item out of the trash.
+func (r *Repository) RestoreItem(ctx context.Context, id int64) error {
+	return r.RestoreItems(ctx, []int64{id})
+}
+
+// RestoreItems moves the given items out of the trash.
+func (r *Repository) RestoreItems(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	err := r.write(ctx, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewUpdate().
+			Model((*ClipboardItem)(nil)).
+			Set("deleted_at = NULL").
+			Set("delete_reason = ''").
+			Set("updated_at = ?", time.Now()).
+			Where("id IN (?)", bun.In(ids)).
+			Exec(ctx)
+		return err
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to restore items: %w", err)
+	}
+
+	return nil
+}
+
+// PurgeItem permanently deletes an item that is in the trash.
+func (r *Repository) PurgeItem(ctx context.Context, id int64) error {
+	err := r.write(ctx, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewDelete().
+			Model((*ClipboardItem)(nil)).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Exec(ctx)
+		return err
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to purge item: %w", err)
+	}
+
+	return r.deleteOrphanedRevisions(ctx)
+}
+
+// DeleteItems permanently deletes items and their revisions, whether they
+// are in the trash or not.
+func (r *Repository) DeleteItems(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	err := r.write(ctx, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewDelete().
+			Model((*ItemRevision)(nil)).
+			Where("item_id IN (?)", bun.In(ids)).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NewDelete().
+			Model((*ClipboardItem)(nil)).
+			Where("id IN (?)", bun.In(ids)).
+			Exec(ctx)
+		return err
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to delete items: %w", err)
+	}
+
+	return nil
+}
+
+// EmptyTrash permanently deletes every item in the trash.
+func (r *Repository) EmptyTrash(ctx context.Context) error {
+	err := r.write(ctx, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewDelete().
+			Model((*ClipboardItem)(nil)).
+			Where("deleted_at IS NOT NULL").
+			Exec(ctx)
+		return err
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to empty trash: %w", err)
+	}
+
+	return r.deleteOrphanedRevisions(ctx)
+}
+
+// PurgeTrash permanently deletes items that have been in the trash for more than maxDays.
+func (r *Repository) PurgeTrash(ctx context.Context, maxDays int) error {
+	cutoffDate := time.Now().AddDate(0, 0, -maxDays)
+
+	err := r.write(ctx, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewDelete().
+			Model((*ClipboardItem)(nil)).
+			Where("deleted_at IS NOT NULL AND julianday(deleted_at) < julianday(?)", cutoffDate).
+			Exec(ctx)
+		return err
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to purge trash: %w", err)
+	}
+
+	return r.deleteOrphanedRevisions(ctx)
+}
+
+// deleteOrphanedRevisions removes revisions whose item no longer exists.
+func (r *Repository) deleteOrphanedRevisions(ctx context.Context) error {
+	err := r.write(ctx, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewDelete().
+			Model((*ItemRevision)(nil)).
+			Where("item_id NOT IN (SELECT id FROM clipboard_items)").
+			Exec(ctx)
+		return err
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to delete orphaned revisions: %w", err)
+	}
+
 	return nil
 }
 
+// Close stops the write queue and closes the database. Writes queued
+// afterwards fail with ErrClosed.
 func (r *Repository) Close() error {
-	return r.db.Close()
+	var err error
+	r.closeOnce.Do(func() {
+		close(r.closed)
+		if r.watch != nil {
+			r.watch.Close()
+		}
+		err = r.db.Close()
+	})
+	return err
 }
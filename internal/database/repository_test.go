@@ -0,0 +1,244 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"clipboardpro/internal/util"
+)
+
+// pinnedOrder returns the IDs of the pinned items in list order.
+func pinnedOrder(t *testing.T, repository *Repository) []int64 {
+	t.Helper()
+	page, err := repository.ListItems(context.Background(), ListQuery{Limit: 100})
+	if err != nil {
+		t.Fatalf("ListItems: %v", err)
+	}
+	var ids []int64
+	for _, item := range page.Items {
+		if item.Pinned {
+			ids = append(ids, item.ID)
+		}
+	}
+	return ids
+}
+
+func TestPinning(t *testing.T) {
+	ctx := context.Background()
+	repository := newTestRepository(t)
+	ids := importTestItems(t, repository, 4, nil)
+
+	steps := []struct {
+		name string
+		do   func() error
+		want []int64
+	}{
+		{"toggle pins on top", func() error { return repository.TogglePin(ctx, ids[0]) }, []int64{ids[0]}},
+		{"pin goes on top", func() error { return repository.PinItem(ctx, ids[1], true) }, []int64{ids[1], ids[0]}},
+		{"toggle goes on top", func() error { return repository.TogglePin(ctx, ids[2]) }, []int64{ids[2], ids[1], ids[0]}},
+		{"pinning again keeps the position", func() error { return repository.PinItem(ctx, ids[0], true) }, []int64{ids[2], ids[1], ids[0]}},
+		{"toggle unpins", func() error { return repository.TogglePin(ctx, ids[1]) }, []int64{ids[2], ids[0]}},
+		{"toggle back goes on top", func() error { return repository.TogglePin(ctx, ids[1]) }, []int64{ids[1], ids[2], ids[0]}},
+		{"unpin", func() error { return repository.PinItem(ctx, ids[2], false) }, []int64{ids[1], ids[0]}},
+		{"restore a position", func() error { return repository.SetPinned(ctx, ids[2], true, 100) }, []int64{ids[1], ids[0], ids[2]}},
+	}
+
+	for _, step := range steps {
+		if err := step.do(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if got := pinnedOrder(t, repository); !slices.Equal(got, step.want) {
+			t.Errorf("%s: pinned %v, want %v", step.name, got, step.want)
+		}
+	}
+
+	for _, id := range []int64{ids[0], ids[1], ids[2]} {
+		if err := repository.PinItem(ctx, id, false); err != nil {
+			t.Fatal(err)
+		}
+		item, err := repository.GetItemByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if item.Pinned || item.Position != 0 {
+			t.Errorf("unpinned item %d kept pinned %v, position %d", id, item.Pinned, item.Position)
+		}
+	}
+}
+
+func TestMovePinnedItem(t *testing.T) {
+	ctx := context.Background()
+	repository := newTestRepository(t)
+	ids := importTestItems(t, repository, 3, func(i int, item *ClipboardItem) {
+		item.Pinned = true
+	})
+	a, b, c := ids[0], ids[1], ids[2] // Positions 1, 2 and 3
+
+	tests := []struct {
+		name      string
+		id        int64
+		offset    int
+		wantMoved bool
+		want      []int64
+	}{
+		{"top item up", a, -1, false, []int64{a, b, c}},
+		{"bottom item down", c, 1, false, []int64{a, b, c}},
+		{"down", a, 1, true, []int64{b, a, c}},
+		{"up", c, -1, true, []int64{b, c, a}},
+		{"new top item up", b, -1, false, []int64{b, c, a}},
+	}
+
+	for _, tt := range tests {
+		moved, err := repository.MovePinnedItem(ctx, tt.id, tt.offset)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if moved != tt.wantMoved {
+			t.Errorf("%s: moved = %v, want %v", tt.name, moved, tt.wantMoved)
+		}
+		if got := pinnedOrder(t, repository); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: pinned %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestVersion(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "history.db")
+	repository, err := NewRepository(path)
+	if err != nil {
+		t.Fatalf("NewRepository: %v", err)
+	}
+	defer repository.Close()
+
+	// A second repository on the same file stands in for another process
+	other, err := NewRepository(path)
+	if err != nil {
+		t.Fatalf("NewRepository: %v", err)
+	}
+	defer other.Close()
+
+	version := func() uint64 {
+		t.Helper()
+		v, err := repository.Version(ctx)
+		if err != nil {
+			t.Fatalf("Version: %v", err)
+		}
+		return v
+	}
+
+	before := version()
+	if _, err := repository.ListItems(ctx, ListQuery{Limit: 10}); err != nil {
+		t.Fatalf("ListItems: %v", err)
+	}
+	if got := version(); got != before {
+		t.Errorf("version changed from %d to %d after a read", before, got)
+	}
+
+	if err := repository.SaveClipboardItem(ctx, &ClipboardItem{Content: "mine", Type: "text", Hash: "mine"}); err != nil {
+		t.Fatalf("SaveClipboardItem: %v", err)
+	}
+	afterOwn := version()
+	if afterOwn == before {
+		t.Error("version did not change after a write by the same repository")
+	}
+
+	if err := other.SaveClipboardItem(ctx, &ClipboardItem{Content: "theirs", Type: "text", Hash: "theirs"}); err != nil {
+		t.Fatalf("SaveClipboardItem: %v", err)
+	}
+	if version() == afterOwn {
+		t.Error("version did not change after a write by another repository")
+	}
+}
+
+func TestUpdateContent(t *testing.T) {
+	for _, encrypted := range []bool{false, true} {
+		t.Run(fmt.Sprintf("encrypted=%v", encrypted), func(t *testing.T) {
+			ctx := context.Background()
+			repository := newTestRepository(t)
+			ids := importTestItems(t, repository, 1, func(i int, item *ClipboardItem) {
+				item.Hash = util.GenerateHash(item.Content, nil)
+			})
+			if encrypted {
+				if err := repository.EnableEncryption(ctx, "passphrase"); err != nil {
+					t.Fatalf("EnableEncryption: %v", err)
+				}
+			}
+
+			if err := repository.UpdateContent(ctx, ids[0], "edited", false); err != nil {
+				t.Fatalf("UpdateContent: %v", err)
+			}
+
+			oldHash, newHash := util.GenerateHash("item 1", nil), util.GenerateHash("edited", nil)
+			found, err := repository.GetItemsByHash(ctx, []string{oldHash, newHash})
+			if err != nil {
+				t.Fatalf("GetItemsByHash: %v", err)
+			}
+			if _, ok := found[oldHash]; ok {
+				t.Error("the item is still found by its old hash")
+			}
+			if item, ok := found[newHash]; !ok || item.ID != ids[0] {
+				t.Error("the item is not found by the hash of its new content")
+			}
+
+			revisions, err := repository.GetRevisions(ctx, ids[0])
+			if err != nil {
+				t.Fatalf("GetRevisions: %v", err)
+			}
+			if len(revisions) != 1 || revisions[0].Content != "item 1" {
+				t.Errorf("revisions = %+v, want the previous content", revisions)
+			}
+		})
+	}
+}
+
+func TestUpdateContentDuplicates(t *testing.T) {
+	ctx := context.Background()
+	repository := newTestRepository(t)
+	ids := importTestItems(t, repository, 3, func(i int, item *ClipboardItem) {
+		item.Hash = util.GenerateHash(item.Content, nil)
+	})
+	if err := repository.UpdateContent(ctx, ids[2], "item 3 edited", false); err != nil {
+		t.Fatalf("UpdateContent: %v", err)
+	}
+	if err := repository.TrashItems(ctx, []int64{ids[2]}, "test"); err != nil {
+		t.Fatalf("TrashItems: %v", err)
+	}
+
+	if err := repository.UpdateContent(ctx, ids[0], "item 2", false); !errors.Is(err, ErrDuplicateContent) {
+		t.Errorf("UpdateContent to the content of another item = %v, want ErrDuplicateContent", err)
+	}
+	// Replacing only applies to trashed items
+	if err := repository.UpdateContent(ctx, ids[0], "item 2", true); !errors.Is(err, ErrDuplicateContent) {
+		t.Errorf("UpdateContent replacing trashed items = %v, want ErrDuplicateContent", err)
+	}
+
+	if err := repository.UpdateContent(ctx, ids[0], "item 3 edited", false); !errors.Is(err, ErrDuplicateInTrash) {
+		t.Errorf("UpdateContent to the content of a trashed item = %v, want ErrDuplicateInTrash", err)
+	}
+	if _, err := repository.GetItemByID(ctx, ids[2]); err != nil {
+		t.Errorf("the trashed item was deleted without replaceTrashed: %v", err)
+	}
+
+	if err := repository.UpdateContent(ctx, ids[0], "item 3 edited", true); err != nil {
+		t.Fatalf("UpdateContent replacing the trashed item: %v", err)
+	}
+	if _, err := repository.GetItemByID(ctx, ids[2]); err == nil {
+		t.Error("the trashed item was not deleted")
+	}
+	if revisions, err := repository.GetRevisions(ctx, ids[2]); err != nil || len(revisions) != 0 {
+		t.Errorf("GetRevisions of the deleted item = %d revisions, %v; want none", len(revisions), err)
+	}
+
+	item, err := repository.GetItemByID(ctx, ids[0])
+	if err != nil {
+		t.Fatalf("GetItemByID: %v", err)
+	}
+	if item.Content != "item 3 edited" {
+		t.Errorf("content = %q, want %q", item.Content, "item 3 edited")
+	}
+}
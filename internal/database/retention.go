@@ -0,0 +1,32 @@
+package database
+
+import (
+	"context"
+	"fmt"
+)
+
+// GetRetentionItems returns every item, including those in the trash, as a
+// list row with StoredBytes set, newest first, for evaluating retention
+// rules and the storage quota.
+func (r *Repository) GetRetentionItems(ctx context.Context) ([]*ClipboardItem, error) {
+	key, err := r.cipherKey()
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*ClipboardItem
+	err = selectListColumns(r.db.NewSelect().Model(&items), key != nil).
+		ColumnExpr("("+storedSizeExpr+") + (SELECT COALESCE(SUM(LENGTH(COALESCE(revision.content, ''))), 0) "+
+			"FROM item_revisions AS revision WHERE revision.item_id = ?TableAlias.id) AS stored_bytes").
+		Order("timestamp DESC", "id DESC").
+		Scan(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get items: %w", err)
+	}
+	if err := openItems(key, items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
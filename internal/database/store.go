@@ -0,0 +1,74 @@
+package database
+
+import (
+	"context"
+	"time"
+)
+
+// Store is the clipboard history as used by the rest of the application.
+// Repository implements it on SQLite, either on disk or, for ephemeral
+// sessions, in memory.
+type Store interface {
+	// Items
+	SaveClipboardItem(ctx context.Context, item *ClipboardItem) error
+	GetItemByID(ctx context.Context, id int64) (*ClipboardItem, error)
+	GetItemsByHash(ctx context.Context, hashes []string) (map[string]*ClipboardItem, error)
+	ListItems(ctx context.Context, query ListQuery) (*Page, error)
+	CountItems(ctx context.Context, search string) (int, error)
+	GetRecentItems(ctx context.Context, limit int, sort SortMode) ([]*ClipboardItem, error)
+	SearchItems(ctx context.Context, query string, limit int, sort SortMode) ([]*ClipboardItem, error)
+	ForEachItem(ctx context.Context, filter ExportFilter, fn func(*ClipboardItem) error) error
+	ImportItems(ctx context.Context, inserts, updates []*ClipboardItem) error
+	Version(ctx context.Context) (uint64, error)
+
+	// Editing
+	TogglePin(ctx context.Context, id int64) error
+	PinItem(ctx context.Context, id int64, pinned bool) error
+	SetPinned(ctx context.Context, id int64, pinned bool, position int) error
+	MovePinnedItem(ctx context.Context, id int64, offset int) (bool, error)
+	SetPinnedOrder(ctx context.Context, ids []int64) error
+	UpdateTitle(ctx context.Context, id int64, title string) error
+	UpdateNotes(ctx context.Context, id int64, notes string) error
+	UpdateTags(ctx context.Context, id int64, tags string) error
+	UpdateContent(ctx context.Context, id int64, content string, replaceTrashed bool) error
+	GetRevisions(ctx context.Context, itemID int64) ([]*ItemRevision, error)
+	RevertToRevision(ctx context.Context, revisionID int64, replaceTrashed bool) error
+	RecordUsage(ctx context.Context, id int64) error
+
+	// Expiry
+	SetExpiry(ctx context.Context, id int64, expiresAt time.Time) error
+	SetBurnAfterCopy(ctx context.Context, id int64, burn bool) error
+	BurnItem(ctx context.Context, id int64) error
+	NextExpiry(ctx context.Context) (time.Time, error)
+	PurgeExpiredItems(ctx context.Context, now time.Time) (int64, error)
+
+	// Trash and retention
+	DeleteItem(ctx context.Context, id int64) error
+	TrashItems(ctx context.Context, ids []int64, reason string) error
+	ClearAllItems(ctx context.Context) ([]int64, error)
+	GetTrashedItems(ctx context.Context, limit int) ([]*ClipboardItem, error)
+	RestoreItem(ctx context.Context, id int64) error
+	RestoreItems(ctx context.Context, ids []int64) error
+	PurgeItem(ctx context.Context, id int64) error
+	DeleteItems(ctx context.Context, ids []int64) error
+	EmptyTrash(ctx context.Context) error
+	PurgeTrash(ctx context.Context, maxDays int) error
+	GetRetentionItems(ctx context.Context) ([]*ClipboardItem, error)
+
+	// Encryption
+	IsEncrypted() bool
+	IsLocked() bool
+	Unlock(passphrase string) error
+	EnableEncryption(ctx context.Context, passphrase string) error
+	ChangePassphrase(ctx context.Context, current, newPassphrase string) error
+	DisableEncryption(ctx context.Context, current string) error
+
+	// Maintenance
+	StorageStats(ctx context.Context, largest int) (*StorageStats, error)
+	RunMaintenance(ctx context.Context, task MaintenanceTask) ([]string, error)
+	BackupTo(ctx context.Context, path string) error
+	RestoreFrom(ctx context.Context, path string) error
+	Close() error
+}
+
+var _ Store = (*Repository)(nil)
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// NormalizeTags turns user input such as "Work, clients  work" into the
+// stored form of a tag list: lower case, without duplicates, sorted and
+// separated by commas. Tags are separated by commas or spaces.
+func NormalizeTags(input string) string {
+	var tags []string
+	for _, tag := range strings.FieldsFunc(strings.ToLower(input), func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	}) {
+		tag = strings.TrimPrefix(tag, "#")
+		if tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	slices.Sort(tags)
+	return strings.Join(tags, ",")
+}
+
+// SplitTags returns the tags of a stored tag list.
+func SplitTags(tags string) []string {
+	if tags == "" {
+		return nil
+	}
+	return strings.Split(tags, ",")
+}
+
+// hasTags reports whether a stored tag list contains all of tags.
+func hasTags(stored string, tags []string) bool {
+	list := SplitTags(stored)
+	for _, tag := range tags {
+		if !slices.Contains(list, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// UpdateTags sets the tags of an item, normalizing them first.
+func (r *Repository) UpdateTags(ctx context.Context, id int64, tags string) error {
+	key, err := r.cipherKey()
+	if err != nil {
+		return err
+	}
+	if tags, err = encryptString(key, NormalizeTags(tags)); err != nil {
+		return fmt.Errorf("failed to update tags: %w", err)
+	}
+
+	err = r.write(ctx, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewUpdate().
+			Model((*ClipboardItem)(nil)).
+			Set("tags = ?", tags).
+			Set("updated_at = ?", time.Now()).
+			Where("id = ?", id).
+			Exec(ctx)
+		return err
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to update tags: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package database
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tests := map[string]string{
+		"":                    "",
+		"work":                "work",
+		"Work, clients  work": "clients,work",
+		"#todo\t#Later\n,,":   "later,todo",
+		" b a  c ":            "a,b,c",
+		"#":                   "",
+		"naïve, Ünïcode":      "naïve,ünïcode",
+	}
+	for input, want := range tests {
+		if got := NormalizeTags(input); got != want {
+			t.Errorf("NormalizeTags(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestHasTags(t *testing.T) {
+	tests := []struct {
+		stored string
+		tags   []string
+		want   bool
+	}{
+		{"", nil, true},
+		{"", []string{"work"}, false},
+		{"a,work", []string{"work"}, true},
+		{"a,work", []string{"work", "a"}, true},
+		{"a,work", []string{"wor"}, false},
+		{"a,work", []string{"work", "b"}, false},
+	}
+	for _, tt := range tests {
+		if got := hasTags(tt.stored, tt.tags); got != tt.want {
+			t.Errorf("hasTags(%q, %q) = %v, want %v", tt.stored, tt.tags, got, tt.want)
+		}
+	}
+}
+
+func TestUpdateTags(t *testing.T) {
+	ctx := context.Background()
+	repository := newTestRepository(t)
+	ids := importTestItems(t, repository, 1, nil)
+
+	for _, encrypted := range []bool{false, true} {
+		if encrypted {
+			if err := repository.EnableEncryption(ctx, "passphrase"); err != nil {
+				t.Fatalf("EnableEncryption: %v", err)
+			}
+		}
+
+		if err := repository.UpdateTags(ctx, ids[0], "Work #Later work"); err != nil {
+			t.Fatalf("UpdateTags: %v", err)
+		}
+		item, err := repository.GetItemByID(ctx, ids[0])
+		if err != nil {
+			t.Fatalf("GetItemByID: %v", err)
+		}
+		if got := SplitTags(item.Tags); !slices.Equal(got, []string{"later", "work"}) {
+			t.Errorf("encrypted %v: tags = %q, want later and work", encrypted, got)
+		}
+	}
+}
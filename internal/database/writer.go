@@ -0,0 +1,110 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/driver/sqliteshim"
+)
+
+// ErrClosed is returned by writes to a closed repository.
+var ErrClosed = errors.New("database is closed")
+
+// busyTimeoutMillis is how long a connection waits for a lock held by
+// another connection or process before failing with "database is locked".
+const busyTimeoutMillis = 10000
+
+// dataSourceName adds the connection settings to a database path, in the
+// syntax of the SQLite driver in use. WAL lets readers proceed while a
+// write is in progress, in this process or another one. Transactions begin
+// immediately, taking SQLite's write lock up front and waiting for other
+// writers in any process, rather than failing when they first write.
+func dataSourceName(path string) string {
+	var params []string
+	if sqliteshim.DriverName() == "sqlite3" {
+		// github.com/mattn/go-sqlite3
+		params = []string{
+			fmt.Sprintf("_busy_timeout=%d", busyTimeoutMillis),
+			"_journal_mode=WAL",
+			"_synchronous=NORMAL",
+		}
+	} else {
+		params = []string{
+			fmt.Sprintf("_pragma=busy_timeout(%d)", busyTimeoutMillis),
+			"_pragma=journal_mode(WAL)",
+			"_pragma=synchronous(NORMAL)",
+		}
+	}
+	params = append(params, "_txlock=immediate")
+	return path + "?" + strings.Join(params, "&")
+}
+
+// writeRequest is a write waiting in the queue.
+type writeRequest struct {
+	ctx  context.Context
+	fn   func(context.Context) error
+	done chan error
+}
+
+// runWriter performs queued writes one at a time until the repository is
+// closed.
+func (r *Repository) runWriter() {
+	for {
+		select {
+		case <-r.closed:
+			return
+		case req := <-r.writes:
+			if err := req.ctx.Err(); err != nil {
+				req.done <- err
+				continue
+			}
+			req.done <- req.fn(req.ctx)
+		}
+	}
+}
+
+// queue runs fn on the writer after the writes queued before it and
+// returns its result. Writes from this process never wait on each other
+// inside SQLite, which leaves the busy timeout to other processes. fn must
+// not queue writes itself.
+func (r *Repository) queue(ctx context.Context, fn func(context.Context) error) error {
+	req := writeRequest{ctx: ctx, fn: fn, done: make(chan error, 1)}
+
+	select {
+	case r.writes <- req:
+	case <-r.closed:
+		return ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	err := <-req.done
+	r.changes.Add(1)
+	return err
+}
+
+// Version identifies the state of the history. It changes whenever the
+// history is written, by this process or another one, or unlocked, so a
+// caller polling for changes can skip reading items that have not changed.
+func (r *Repository) Version(ctx context.Context) (uint64, error) {
+	version := r.changes.Load()
+	if r.watch == nil {
+		return version, nil
+	}
+
+	var dataVersion uint64
+	if err := r.watch.QueryRowContext(ctx, "PRAGMA data_version").Scan(&dataVersion); err != nil {
+		return 0, fmt.Errorf("failed to read data version: %w", err)
+	}
+	return version + dataVersion, nil
+}
+
+// write runs fn in a transaction on the writer.
+func (r *Repository) write(ctx context.Context, fn func(ctx context.Context, tx bun.Tx) error) error {
+	return r.queue(ctx, func(ctx context.Context) error {
+		return r.db.RunInTx(ctx, nil, fn)
+	})
+}
@@ -0,0 +1,230 @@
+// Package diff computes line and word differences between two texts.
+package diff
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Kind describes how a piece of text differs between the old and new version.
+type Kind int
+
+const (
+	Equal Kind = iota
+	Insert
+	Delete
+)
+
+// Op is a single run of text that is equal, inserted or deleted.
+type Op struct {
+	Kind Kind
+	Text string
+}
+
+// maxCells bounds the size of the comparison table. Inputs that would need
+// more are reported as a full replacement instead.
+const maxCells = 4_000_000
+
+// Options change how texts are compared.
+type Options struct {
+	Words            bool // Compare word by word instead of line by line
+	IgnoreWhitespace bool // Treat any run of whitespace as equal, and ignore it around lines
+}
+
+// Lines compares old and new line by line.
+func Lines(old, new string) []Op {
+	return Compare(old, new, Options{})
+}
+
+// Compare compares old and new. Text that only differs in whitespace is
+// reported as equal, in its old form, when whitespace is ignored.
+func Compare(old, new string, opts Options) []Op {
+	split := splitLines
+	if opts.Words {
+		split = splitWords
+	}
+	a, b := split(old), split(new)
+	return compare(a, b, keys(a, opts), keys(b, opts))
+}
+
+// HasChanges reports whether ops contain any insertion or deletion.
+func HasChanges(ops []Op) bool {
+	for _, op := range ops {
+		if op.Kind != Equal {
+			return true
+		}
+	}
+	return false
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// splitWords splits text into words, runs of whitespace and single
+// punctuation characters.
+func splitWords(text string) []string {
+	var tokens []string
+	start := 0
+	class := -1
+	for i, r := range text {
+		c := charClass(r)
+		if i > start && (c != class || c == punctuation) {
+			tokens = append(tokens, text[start:i])
+			start = i
+		}
+		class = c
+	}
+	if start < len(text) {
+		tokens = append(tokens, text[start:])
+	}
+	return tokens
+}
+
+const (
+	word = iota
+	space
+	punctuation
+)
+
+func charClass(r rune) int {
+	switch {
+	case unicode.IsSpace(r):
+		return space
+	case unicode.IsLetter(r), unicode.IsDigit(r), r == '_':
+		return word
+	default:
+		return punctuation
+	}
+}
+
+// keys returns the tokens as compared: unchanged, or with whitespace
+// normalised when it is ignored.
+func keys(tokens []string, opts Options) []string {
+	if !opts.IgnoreWhitespace {
+		return tokens
+	}
+
+	normalised := make([]string, len(tokens))
+	for i, token := range tokens {
+		if opts.Words && strings.TrimSpace(token) == "" {
+			normalised[i] = " "
+		} else {
+			normalised[i] = strings.Join(strings.Fields(token), " ")
+		}
+	}
+	return normalised
+}
+
+// compare returns the shortest edit script turning a into b as ops,
+// comparing tokens by their keys.
+func compare(a, b, keyA, keyB []string) []Op {
+	var ops []Op
+	for _, e := range script(keyA, keyB) {
+		if e.kind == Insert {
+			ops = appendOp(ops, Insert, b[e.b])
+		} else {
+			ops = appendOp(ops, e.kind, a[e.a])
+		}
+	}
+	return ops
+}
+
+// edit is one step of an edit script. Equal steps refer to a token in both
+// inputs, deletions only to a and insertions only to b.
+type edit struct {
+	kind Kind
+	a, b int // Token indexes, -1 for the input a step does not refer to
+}
+
+// script returns the shortest edit script turning a into b, computed from
+// the longest common subsequence of the two token lists.
+func script(a, b []string) []edit {
+	// Trim the common prefix and suffix, which is usually most of the input
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var edits []edit
+	for i := 0; i < prefix; i++ {
+		edits = append(edits, edit{Equal, i, i})
+	}
+
+	n, m := len(a)-suffix-prefix, len(b)-suffix-prefix
+	if n*m > maxCells {
+		for i := 0; i < n; i++ {
+			edits = append(edits, edit{Delete, prefix + i, -1})
+		}
+		for j := 0; j < m; j++ {
+			edits = append(edits, edit{Insert, -1, prefix + j})
+		}
+	} else {
+		edits = appendLCS(edits, a[prefix:prefix+n], b[prefix:prefix+m], prefix)
+	}
+
+	for k := 0; k < suffix; k++ {
+		edits = append(edits, edit{Equal, prefix + n + k, prefix + m + k})
+	}
+	return edits
+}
+
+// appendLCS adds the edits turning a into b, which start at offset in the
+// full inputs.
+func appendLCS(edits []edit, a, b []string, offset int) []edit {
+	n, m := len(a), len(b)
+	width := m + 1
+	lengths := make([]int, (n+1)*width)
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i*width+j] = lengths[(i+1)*width+j+1] + 1
+			} else {
+				lengths[i*width+j] = max(lengths[(i+1)*width+j], lengths[i*width+j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			edits = append(edits, edit{Equal, offset + i, offset + j})
+			i++
+			j++
+		case lengths[(i+1)*width+j] >= lengths[i*width+j+1]:
+			edits = append(edits, edit{Delete, offset + i, -1})
+			i++
+		default:
+			edits = append(edits, edit{Insert, -1, offset + j})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		edits = append(edits, edit{Delete, offset + i, -1})
+	}
+	for ; j < m; j++ {
+		edits = append(edits, edit{Insert, -1, offset + j})
+	}
+	return edits
+}
+
+// appendOp adds text to ops, merging it into the last op when the kinds match.
+func appendOp(ops []Op, kind Kind, text string) []Op {
+	if len(ops) > 0 && ops[len(ops)-1].Kind == kind {
+		ops[len(ops)-1].Text += text
+		return ops
+	}
+	return append(ops, Op{Kind: kind, Text: text})
+}
@@ -0,0 +1,221 @@
+package diff
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		opts     Options
+		want     []Op
+	}{
+		{
+			name: "identical",
+			old:  "a\nb\n", new: "a\nb\n",
+			want: []Op{{Equal, "a\nb\n"}},
+		},
+		{
+			name: "both empty",
+		},
+		{
+			name: "from empty",
+			new:  "a\n",
+			want: []Op{{Insert, "a\n"}},
+		},
+		{
+			name: "changed line",
+			old:  "a\nb\nc\n", new: "a\nx\nc\n",
+			want: []Op{{Equal, "a\n"}, {Delete, "b\n"}, {Insert, "x\n"}, {Equal, "c\n"}},
+		},
+		{
+			name: "missing final newline",
+			old:  "a\nb", new: "a\nb\n",
+			want: []Op{{Equal, "a\n"}, {Delete, "b"}, {Insert, "b\n"}},
+		},
+		{
+			name: "words",
+			old:  "the quick fox", new: "the slow fox",
+			opts: Options{Words: true},
+			want: []Op{{Equal, "the "}, {Delete, "quick"}, {Insert, "slow"}, {Equal, " fox"}},
+		},
+		{
+			name: "punctuation is a word of its own",
+			old:  "f(a, b)", new: "f(a; b)",
+			opts: Options{Words: true},
+			want: []Op{{Equal, "f(a"}, {Delete, ","}, {Insert, ";"}, {Equal, " b)"}},
+		},
+		{
+			name: "whitespace ignored in lines",
+			old:  "a  b\n  c\n", new: "a b\nc  \n",
+			opts: Options{IgnoreWhitespace: true},
+			want: []Op{{Equal, "a  b\n  c\n"}},
+		},
+		{
+			name: "whitespace ignored in words",
+			old:  "a \t b", new: "a b",
+			opts: Options{Words: true, IgnoreWhitespace: true},
+			want: []Op{{Equal, "a \t b"}},
+		},
+		{
+			name: "whitespace not ignored",
+			old:  "a  b\n", new: "a b\n",
+			want: []Op{{Delete, "a  b\n"}, {Insert, "a b\n"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Compare(tt.old, tt.new, tt.opts)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Compare(%q, %q) = %v, want %v", tt.old, tt.new, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasChanges(t *testing.T) {
+	if HasChanges(Compare("a  b\n", "a b\n", Options{IgnoreWhitespace: true})) {
+		t.Error("HasChanges reported whitespace that is ignored")
+	}
+	if !HasChanges(Lines("a\n", "b\n")) {
+		t.Error("HasChanges missed a changed line")
+	}
+	if HasChanges(nil) {
+		t.Error("HasChanges reported changes between empty texts")
+	}
+}
+
+func TestCompareLargeInputs(t *testing.T) {
+	// Inputs past maxCells are reported as a full replacement, around the
+	// common prefix and suffix
+	var old, new strings.Builder
+	old.WriteString("start\n")
+	new.WriteString("start\n")
+	for i := 0; i < 2100; i++ {
+		old.WriteString("o\n")
+		new.WriteString("n\n")
+		old.WriteString(strings.Repeat("x", i%7) + "\n")
+		new.WriteString(strings.Repeat("y", i%5) + "\n")
+	}
+	old.WriteString("end\n")
+	new.WriteString("end\n")
+
+	ops := Compare(old.String(), new.String(), Options{})
+	if len(ops) != 4 || ops[0] != (Op{Equal, "start\n"}) || ops[1].Kind != Delete || ops[2].Kind != Insert || ops[3] != (Op{Equal, "end\n"}) {
+		t.Errorf("got %d ops, want equal, delete, insert, equal", len(ops))
+	}
+}
+
+func TestUnified(t *testing.T) {
+	tenLines := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	changed := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\n"
+
+	// Expected output is that of GNU diff -u with the same labels
+	tests := []struct {
+		name     string
+		old, new string
+		context  int
+		opts     Options
+		want     string
+	}{
+		{
+			name: "no changes",
+			old:  tenLines, new: tenLines, context: 3,
+		},
+		{
+			name: "separate hunks",
+			old:  tenLines, new: changed, context: 3,
+			want: `--- old
++++ new
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -8,3 +8,4 @@
+ h
+ i
+ j
++k
+`,
+		},
+		{
+			name: "less context",
+			old:  tenLines, new: changed, context: 1,
+			want: `--- old
++++ new
+@@ -1,3 +1,3 @@
+ a
+-b
++B
+ c
+@@ -10 +10,2 @@
+ j
++k
+`,
+		},
+		{
+			name: "no newline at end",
+			old:  "x\ny", new: "x\nz", context: 3,
+			want: `--- old
++++ new
+@@ -1,2 +1,2 @@
+ x
+-y
+\ No newline at end of file
++z
+\ No newline at end of file
+`,
+		},
+		{
+			name: "from empty",
+			new:  "one\n", context: 3,
+			want: `--- old
++++ new
+@@ -0,0 +1 @@
++one
+`,
+		},
+		{
+			name: "to empty",
+			old:  "one\ntwo\n", context: 3,
+			want: `--- old
++++ new
+@@ -1,2 +0,0 @@
+-one
+-two
+`,
+		},
+		{
+			name: "whitespace ignored",
+			old:  "a\n  b\n", new: "a\nb\n", context: 3,
+			opts: Options{IgnoreWhitespace: true},
+		},
+		{
+			name: "words option ignored",
+			old:  "a b\n", new: "a c\n", context: 3,
+			opts: Options{Words: true},
+			want: `--- old
++++ new
+@@ -1 +1 @@
+-a b
++a c
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Unified("old", "new", tt.old, tt.new, tt.context, tt.opts)
+			if got != tt.want {
+				t.Errorf("Unified() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Unified returns the line differences between old and new in unified diff
+// format, with context lines of unchanged text around each change. Words in
+// opts is ignored; unified diffs are always by line. It returns "" if the
+// texts do not differ.
+func Unified(oldName, newName, old, new string, context int, opts Options) string {
+	opts.Words = false
+	a, b := splitLines(old), splitLines(new)
+	edits := script(keys(a, opts), keys(b, opts))
+
+	var out strings.Builder
+	aLine, bLine, next := 0, 0, 0
+	for _, hunk := range hunks(edits, context) {
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", oldName, newName)
+		}
+
+		// Count the lines on either side before the hunk
+		for ; next < hunk[0]; next++ {
+			if edits[next].a >= 0 {
+				aLine++
+			}
+			if edits[next].b >= 0 {
+				bLine++
+			}
+		}
+		writeHunk(&out, edits[hunk[0]:hunk[1]], aLine, bLine, a, b)
+	}
+	return out.String()
+}
+
+// hunks returns the ranges of edits, as start and end indexes, that contain
+// changes together with up to context equal edits on either side. Changes
+// closer than twice the context share a hunk.
+func hunks(edits []edit, context int) [][2]int {
+	var ranges [][2]int
+	for i, e := range edits {
+		if e.kind == Equal {
+			continue
+		}
+
+		start, end := max(i-context, 0), min(i+1+context, len(edits))
+		if n := len(ranges); n > 0 && start <= ranges[n-1][1] {
+			ranges[n-1][1] = end
+		} else {
+			ranges = append(ranges, [2]int{start, end})
+		}
+	}
+	return ranges
+}
+
+// writeHunk writes a hunk that follows aLine lines of old and bLine lines
+// of new.
+func writeHunk(out *strings.Builder, edits []edit, aLine, bLine int, a, b []string) {
+	aCount, bCount := 0, 0
+	for _, e := range edits {
+		if e.a >= 0 {
+			aCount++
+		}
+		if e.b >= 0 {
+			bCount++
+		}
+	}
+	fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(aLine, aCount), hunkRange(bLine, bCount))
+
+	for _, e := range edits {
+		switch e.kind {
+		case Equal:
+			writeLine(out, ' ', a[e.a])
+		case Delete:
+			writeLine(out, '-', a[e.a])
+		case Insert:
+			writeLine(out, '+', b[e.b])
+		}
+	}
+}
+
+// hunkRange formats one side of a hunk header. Lines are numbered from 1,
+// and an empty side gives the line it follows.
+func hunkRange(before, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", before)
+	case 1:
+		return fmt.Sprintf("%d", before+1)
+	default:
+		return fmt.Sprintf("%d,%d", before+1, count)
+	}
+}
+
+func writeLine(out *strings.Builder, prefix byte, line string) {
+	out.WriteByte(prefix)
+	out.WriteString(line)
+	if !strings.HasSuffix(line, "\n") {
+		out.WriteString("\n\\ No newline at end of file\n")
+	}
+}
@@ -0,0 +1,245 @@
+// Package export writes clipboard history to JSON Lines, CSV, Markdown and
+// ZIP archives.
+package export
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"clipboardpro/internal/database"
+)
+
+type Format string
+
+const (
+	FormatJSONL    Format = "jsonl"
+	FormatCSV      Format = "csv"
+	FormatMarkdown Format = "markdown"
+	FormatZIP      Format = "zip"
+)
+
+// Formats lists the supported formats in the order they are offered.
+var Formats = []Format{FormatJSONL, FormatCSV, FormatMarkdown, FormatZIP}
+
+// ImageMode controls how image data is written by formats that support it.
+type ImageMode string
+
+const (
+	ImagesInline ImageMode = "base64" // Embedded as base64 (JSON Lines only)
+	ImagesFiles  ImageMode = "files"  // Written as sidecar PNG files
+	ImagesNone   ImageMode = "none"   // Left out
+)
+
+// ManifestVersion is the version of the record and manifest layout.
+const ManifestVersion = 1
+
+// Options configures an export.
+type Options struct {
+	Format Format
+	Images ImageMode
+	Filter database.ExportFilter
+}
+
+// Extension returns the usual file extension for a format.
+func (f Format) Extension() string {
+	switch f {
+	case FormatCSV:
+		return ".csv"
+	case FormatMarkdown:
+		return ".md"
+	case FormatZIP:
+		return ".zip"
+	default:
+		return ".jsonl"
+	}
+}
+
+// DefaultFileName suggests a file name for an export made at t.
+func DefaultFileName(f Format, t time.Time) string {
+	return "clipboard-history-" + t.Format("2006-01-02") + f.Extension()
+}
+
+// Record is the exported form of a clipboard item.
+type Record struct {
+	ID         int64      `json:"id"`
+	Type       string     `json:"type"`
+	Content    string     `json:"content,omitempty"`
+	Title      string     `json:"title,omitempty"`
+	Notes      string     `json:"notes,omitempty"`
+	Tags       []string   `json:"tags,omitempty"`
+	Pinned     bool       `json:"pinned"`
+	Position   int        `json:"position,omitempty"`
+	Timestamp  time.Time  `json:"timestamp"`
+	Size       int        `json:"size"`
+	Hash       string     `json:"hash"`
+	CopyCount  int        `json:"copy_count,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+
+	ImageData string `json:"image_data,omitempty"` // Base64-encoded PNG
+	ImageFile string `json:"image_file,omitempty"` // Path of a sidecar PNG, relative to the export
+}
+
+// Manifest describes a ZIP export.
+type Manifest struct {
+	Version    int       `json:"version"`
+	App        string    `json:"app"`
+	ExportedAt time.Time `json:"exported_at"`
+	Count      int       `json:"count"`
+	Items      []*Record `json:"items"`
+}
+
+// Result summarises a finished export.
+type Result struct {
+	Path   string
+	Items  int
+	Images int
+}
+
+// NewRecord converts an item into a record without image data.
+func NewRecord(item *database.ClipboardItem) *Record {
+	record := &Record{
+		ID:        item.ID,
+		Type:      item.Type,
+		Content:   item.Content,
+		Title:     item.Title,
+		Notes:     item.Notes,
+		Tags:      database.SplitTags(item.Tags),
+		Pinned:    item.Pinned,
+		Position:  item.Position,
+		Timestamp: item.Timestamp,
+		Size:      item.Size,
+		Hash:      item.Hash,
+		CopyCount: item.CopyCount,
+		CreatedAt: item.CreatedAt,
+	}
+	if !item.LastUsedAt.IsZero() {
+		lastUsed := item.LastUsedAt
+		record.LastUsedAt = &lastUsed
+	}
+	return record
+}
+
+// ImageFileName returns the name used for an item's image in sidecar
+// directories and ZIP archives.
+func ImageFileName(id int64) string {
+	return fmt.Sprintf("%d.png", id)
+}
+
+// Export writes the items selected by opts to path.
+func Export(ctx context.Context, repository database.Store, path string, opts Options) (*Result, error) {
+	switch opts.Images {
+	case ImagesInline, ImagesFiles, ImagesNone:
+	case "":
+		opts.Images = ImagesInline
+	default:
+		return nil, fmt.Errorf("unsupported image mode: %s", opts.Images)
+	}
+
+	var writer itemWriter
+	var err error
+
+	switch opts.Format {
+	case FormatJSONL:
+		writer, err = newJSONLWriter(path, opts.Images)
+	case FormatCSV:
+		writer, err = newCSVWriter(path)
+	case FormatMarkdown:
+		writer, err = newMarkdownWriter(path, opts.Images)
+	case FormatZIP:
+		writer, err = newZIPWriter(path)
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s", opts.Format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create export file: %w", err)
+	}
+
+	result := &Result{Path: path}
+	err = repository.ForEachItem(ctx, opts.Filter, func(item *database.ClipboardItem) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		wroteImage, err := writer.Write(item)
+		if err != nil {
+			return fmt.Errorf("failed to export item %d: %w", item.ID, err)
+		}
+		result.Items++
+		if wroteImage {
+			result.Images++
+		}
+		return nil
+	})
+
+	if closeErr := writer.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to finish export: %w", closeErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// itemWriter writes items in one export format. Write reports whether it
+// wrote the item's image.
+type itemWriter interface {
+	Write(item *database.ClipboardItem) (bool, error)
+	Close() error
+}
+
+// sidecarDir stores image files next to an export file, in a directory named
+// after it.
+type sidecarDir struct {
+	dir     string // Absolute directory
+	relBase string // Directory relative to the export file
+}
+
+func newSidecarDir(path string) *sidecarDir {
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)) + "_images"
+	return &sidecarDir{
+		dir:     filepath.Join(filepath.Dir(path), base),
+		relBase: base,
+	}
+}
+
+// Write stores an image and returns its path relative to the export file.
+func (s *sidecarDir) Write(id int64, data []byte) (string, error) {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return "", err
+	}
+
+	name := ImageFileName(id)
+	if err := os.WriteFile(filepath.Join(s.dir, name), data, 0644); err != nil {
+		return "", err
+	}
+
+	return filepath.ToSlash(filepath.Join(s.relBase, name)), nil
+}
+
+// attachImage adds an item's image to a record according to mode.
+func attachImage(record *Record, item *database.ClipboardItem, mode ImageMode, sidecar *sidecarDir) (bool, error) {
+	if item.Type != "image" || len(item.ImageData) == 0 {
+		return false, nil
+	}
+
+	switch mode {
+	case ImagesInline:
+		record.ImageData = base64.StdEncoding.EncodeToString(item.ImageData)
+		return true, nil
+	case ImagesFiles:
+		file, err := sidecar.Write(item.ID, item.ImageData)
+		if err != nil {
+			return false, err
+		}
+		record.ImageFile = file
+		return true, nil
+	default:
+		return false, nil
+	}
+}
@@ -0,0 +1,257 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"clipboardpro/internal/database"
+)
+
+type jsonlWriter struct {
+	file    *os.File
+	buf     *bufio.Writer
+	encoder *json.Encoder
+	images  ImageMode
+	sidecar *sidecarDir
+}
+
+func newJSONLWriter(path string, images ImageMode) (*jsonlWriter, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bufio.NewWriter(file)
+	return &jsonlWriter{
+		file:    file,
+		buf:     buf,
+		encoder: json.NewEncoder(buf),
+		images:  images,
+		sidecar: newSidecarDir(path),
+	}, nil
+}
+
+func (w *jsonlWriter) Write(item *database.ClipboardItem) (bool, error) {
+	record := NewRecord(item)
+	wroteImage, err := attachImage(record, item, w.images, w.sidecar)
+	if err != nil {
+		return false, err
+	}
+	return wroteImage, w.encoder.Encode(record)
+}
+
+func (w *jsonlWriter) Close() error {
+	if err := w.buf.Flush(); err != nil {
+		w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+// csvWriter writes one row per item. Image data is not included.
+type csvWriter struct {
+	file   *os.File
+	writer *csv.Writer
+}
+
+var csvHeader = []string{"id", "type", "timestamp", "pinned", "title", "content", "notes", "size", "copy_count", "hash"}
+
+func newCSVWriter(path string) (*csvWriter, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write(csvHeader); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &csvWriter{file: file, writer: writer}, nil
+}
+
+func (w *csvWriter) Write(item *database.ClipboardItem) (bool, error) {
+	return false, w.writer.Write([]string{
+		strconv.FormatInt(item.ID, 10),
+		item.Type,
+		item.Timestamp.Format(time.RFC3339),
+		strconv.FormatBool(item.Pinned),
+		item.Title,
+		item.Content,
+		item.Notes,
+		strconv.Itoa(item.Size),
+		strconv.Itoa(item.CopyCount),
+		item.Hash,
+	})
+}
+
+func (w *csvWriter) Close() error {
+	w.writer.Flush()
+	if err := w.writer.Error(); err != nil {
+		w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+// markdownWriter writes a readable document with one section per item.
+// Images can only be referenced as sidecar files.
+type markdownWriter struct {
+	file    *os.File
+	buf     *bufio.Writer
+	images  ImageMode
+	sidecar *sidecarDir
+}
+
+func newMarkdownWriter(path string, images ImageMode) (*markdownWriter, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bufio.NewWriter(file)
+	fmt.Fprintf(buf, "# Clipboard History\n\nExported %s\n", time.Now().Format("January 2, 2006 15:04"))
+
+	return &markdownWriter{file: file, buf: buf, images: images, sidecar: newSidecarDir(path)}, nil
+}
+
+func (w *markdownWriter) Write(item *database.ClipboardItem) (bool, error) {
+	title := item.Title
+	if title == "" {
+		title = fmt.Sprintf("Item %d", item.ID)
+	}
+	if item.Pinned {
+		title += " (pinned)"
+	}
+
+	fmt.Fprintf(w.buf, "\n## %s\n\n*%s • %s*\n\n", title, item.Type, item.Timestamp.Format("2006-01-02 15:04:05"))
+
+	wroteImage := false
+	switch item.Type {
+	case "image":
+		if w.images == ImagesFiles && len(item.ImageData) > 0 {
+			file, err := w.sidecar.Write(item.ID, item.ImageData)
+			if err != nil {
+				return false, err
+			}
+			fmt.Fprintf(w.buf, "![%s](%s)\n", title, file)
+			wroteImage = true
+		} else {
+			fmt.Fprintf(w.buf, "*Image, %d bytes*\n", item.Size)
+		}
+	default:
+		fence := codeFence(item.Content)
+		fmt.Fprintf(w.buf, "%s\n%s\n%s\n", fence, item.Content, fence)
+	}
+
+	if item.Notes != "" {
+		fmt.Fprintf(w.buf, "\n%s\n", item.Notes)
+	}
+
+	return wroteImage, nil
+}
+
+func (w *markdownWriter) Close() error {
+	if err := w.buf.Flush(); err != nil {
+		w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+// codeFence returns a backtick fence longer than any run of backticks in content.
+func codeFence(content string) string {
+	longest, run := 0, 0
+	for _, r := range content {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
+
+// zipWriter writes a manifest.json with every record plus an images
+// directory holding the image files referenced by the records.
+type zipWriter struct {
+	file     *os.File
+	archive  *zip.Writer
+	manifest Manifest
+}
+
+func newZIPWriter(path string) (*zipWriter, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &zipWriter{
+		file:    file,
+		archive: zip.NewWriter(file),
+		manifest: Manifest{
+			Version:    ManifestVersion,
+			App:        "ClipBoard Pro",
+			ExportedAt: time.Now(),
+		},
+	}, nil
+}
+
+func (w *zipWriter) Write(item *database.ClipboardItem) (bool, error) {
+	record := NewRecord(item)
+
+	wroteImage := false
+	if item.Type == "image" && len(item.ImageData) > 0 {
+		record.ImageFile = "images/" + ImageFileName(item.ID)
+		entry, err := w.archive.CreateHeader(&zip.FileHeader{
+			Name:     record.ImageFile,
+			Method:   zip.Store, // PNG data is already compressed
+			Modified: item.Timestamp,
+		})
+		if err != nil {
+			return false, err
+		}
+		if _, err := entry.Write(item.ImageData); err != nil {
+			return false, err
+		}
+		wroteImage = true
+	}
+
+	w.manifest.Items = append(w.manifest.Items, record)
+	return wroteImage, nil
+}
+
+func (w *zipWriter) Close() error {
+	w.manifest.Count = len(w.manifest.Items)
+
+	err := func() error {
+		entry, err := w.archive.CreateHeader(&zip.FileHeader{
+			Name:     "manifest.json",
+			Method:   zip.Deflate,
+			Modified: w.manifest.ExportedAt,
+		})
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(entry)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(&w.manifest); err != nil {
+			return err
+		}
+		return w.archive.Close()
+	}()
+
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,183 @@
+package importer
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"clipboardpro/internal/database"
+	"clipboardpro/internal/export"
+	"clipboardpro/internal/util"
+)
+
+// maxLineSize bounds a single JSON Lines record, which may hold a base64 image.
+const maxLineSize = 256 * 1024 * 1024
+
+func init() {
+	Register(exportSource{})
+}
+
+// exportSource reads the JSON Lines and ZIP files written by the export
+// package.
+type exportSource struct{}
+
+func (exportSource) ID() string          { return "clipboardpro" }
+func (exportSource) Name() string        { return "ClipBoard Pro export" }
+func (exportSource) DefaultPath() string { return "" }
+
+func (exportSource) Read(ctx context.Context, filePath string) ([]*database.ClipboardItem, int, error) {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".zip":
+		return readZIP(filePath)
+	case ".jsonl", ".json", ".ndjson":
+		return readJSONL(filePath)
+	default:
+		return nil, 0, fmt.Errorf("unsupported file type: %s", filepath.Ext(filePath))
+	}
+}
+
+func readJSONL(filePath string) ([]*database.ClipboardItem, int, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
+
+	// Images are read from next to the export, never from elsewhere on disk
+	baseDir := filepath.Dir(filePath)
+	readImage := func(name string) ([]byte, error) {
+		local := filepath.FromSlash(name)
+		if !filepath.IsLocal(local) {
+			return nil, fmt.Errorf("image file %q is outside the export", name)
+		}
+		return os.ReadFile(filepath.Join(baseDir, local))
+	}
+
+	var items []*database.ClipboardItem
+	skipped := 0 // Malformed lines and records that cannot be restored
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		var record export.Record
+		if err := json.Unmarshal(data, &record); err != nil {
+			skipped++
+			continue
+		}
+
+		item, err := recordToItem(&record, readImage)
+		if err != nil {
+			skipped++
+			continue
+		}
+		items = append(items, item)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return items, skipped, nil
+}
+
+func readZIP(filePath string) ([]*database.ClipboardItem, int, error) {
+	archive, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer archive.Close()
+
+	readImage := func(name string) ([]byte, error) {
+		file, err := archive.Open(path.Clean(name))
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return io.ReadAll(file)
+	}
+
+	file, err := archive.Open("manifest.json")
+	if err != nil {
+		return nil, 0, fmt.Errorf("not a ClipBoard Pro export: %w", err)
+	}
+	defer file.Close()
+
+	var manifest export.Manifest
+	if err := json.NewDecoder(file).Decode(&manifest); err != nil {
+		return nil, 0, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	if manifest.Version > export.ManifestVersion {
+		return nil, 0, fmt.Errorf("export version %d is newer than this version of the application supports", manifest.Version)
+	}
+
+	var items []*database.ClipboardItem
+	skipped := 0
+	for _, record := range manifest.Items {
+		item, err := recordToItem(record, readImage)
+		if err != nil {
+			skipped++
+			continue
+		}
+		items = append(items, item)
+	}
+
+	return items, skipped, nil
+}
+
+// recordToItem converts an exported record back into an item. Image records
+// without image data are rejected, as the image cannot be restored.
+func recordToItem(record *export.Record, readImage func(name string) ([]byte, error)) (*database.ClipboardItem, error) {
+	item := &database.ClipboardItem{
+		Type:      record.Type,
+		Content:   record.Content,
+		Title:     record.Title,
+		Notes:     record.Notes,
+		Tags:      database.NormalizeTags(strings.Join(record.Tags, ",")),
+		Pinned:    record.Pinned,
+		Position:  record.Position,
+		Timestamp: record.Timestamp,
+		CopyCount: record.CopyCount,
+		CreatedAt: record.CreatedAt,
+	}
+	if record.LastUsedAt != nil {
+		item.LastUsedAt = *record.LastUsedAt
+	}
+
+	switch record.Type {
+	case "text":
+		item.Size = len(item.Content)
+	case "image":
+		var err error
+		switch {
+		case record.ImageData != "":
+			item.ImageData, err = base64.StdEncoding.DecodeString(record.ImageData)
+		case record.ImageFile != "":
+			item.ImageData, err = readImage(record.ImageFile)
+		default:
+			return nil, fmt.Errorf("image item %d has no image data", record.ID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read image of item %d: %w", record.ID, err)
+		}
+		item.Size = len(item.ImageData)
+	default:
+		return nil, fmt.Errorf("unknown item type: %s", record.Type)
+	}
+
+	// Recompute the hash so it matches what the monitor would store
+	item.Hash = util.GenerateHash(item.Content, item.ImageData)
+	return item, nil
+}
@@ -0,0 +1,62 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportReadJSONL(t *testing.T) {
+	pngData := samplePNG(t)
+	root := t.TempDir()
+	dir := filepath.Join(root, "export")
+	if err := os.MkdirAll(filepath.Join(dir, "images"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "images", "2.png"), pngData, 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Readable only if image paths could leave the export directory
+	outside := filepath.Join(root, "outside.png")
+	if err := os.WriteFile(outside, pngData, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := []string{
+		`{"id":1,"type":"text","content":"first"}`,
+		`{"id":2,"type":"image","image_file":"images/2.png"}`,
+		`{"id":3,"type":"text","content":`, // Malformed
+		``,
+		`not json at all`,
+		`{"id":4,"type":"image","image_file":"../outside.png"}`,
+		`{"id":5,"type":"image","image_file":"` + filepath.ToSlash(outside) + `"}`,
+		`{"id":6,"type":"text","content":"last"}`,
+	}
+	path := filepath.Join(dir, "export.jsonl")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	items, skipped, err := exportSource{}.Read(context.Background(), path)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if skipped != 4 {
+		t.Errorf("skipped = %d, want 4", skipped)
+	}
+
+	var got []string
+	for _, item := range items {
+		got = append(got, item.Type+":"+item.Content)
+	}
+	want := []string{"text:first", "image:", "text:last"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("items = %v, want %v", got, want)
+	}
+	if !bytes.Equal(items[1].ImageData, pngData) {
+		t.Error("image data was not read from the export directory")
+	}
+}
@@ -0,0 +1,88 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"clipboardpro/internal/database"
+)
+
+func init() {
+	Register(cliphistSource{})
+}
+
+// cliphistSource reads the history of cliphist, a Wayland clipboard history
+// tool. Its database format is internal to cliphist, so entries are listed
+// and decoded with the cliphist command, which must be installed. cliphist
+// does not record when entries were copied; their order is kept with
+// timestamps one second apart ending at the database's modification time.
+type cliphistSource struct{}
+
+func (cliphistSource) ID() string   { return "cliphist" }
+func (cliphistSource) Name() string { return "cliphist" }
+
+func (cliphistSource) DefaultPath() string {
+	return firstExisting(xdgPath("XDG_CACHE_HOME", ".cache", "cliphist", "db"))
+}
+
+func (cliphistSource) Read(ctx context.Context, path string) ([]*database.ClipboardItem, int, error) {
+	if _, err := exec.LookPath("cliphist"); err != nil {
+		return nil, 0, fmt.Errorf("the cliphist command is needed to read its history: %w", err)
+	}
+
+	listing, err := exec.CommandContext(ctx, "cliphist", "-db-path", path, "list").Output()
+	if err != nil {
+		return nil, 0, fmt.Errorf("cliphist list failed: %w", err)
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(listing))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if line := scanner.Text(); strings.Contains(line, "\t") {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	// The listing is newest first
+	times := sequenceTimes(path, len(lines))
+
+	var items []*database.ClipboardItem
+	invalid := 0
+	for i, line := range lines {
+		decode := exec.CommandContext(ctx, "cliphist", "-db-path", path, "decode")
+		decode.Stdin = strings.NewReader(line + "\n")
+		data, err := decode.Output()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, 0, ctx.Err()
+			}
+			invalid++
+			continue
+		}
+
+		timestamp := times[len(times)-1-i]
+
+		var item *database.ClipboardItem
+		if isImage(data) {
+			item, _ = newImageItem(data, timestamp)
+		} else {
+			item = newTextItem(string(data), timestamp)
+		}
+
+		if item == nil {
+			invalid++
+			continue
+		}
+		items = append(items, item)
+	}
+
+	return items, invalid, nil
+}
@@ -0,0 +1,53 @@
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"clipboardpro/internal/database"
+)
+
+func init() {
+	Register(clipmanSource{})
+}
+
+// clipmanSource reads the history of Clipman, a Wayland clipboard manager,
+// which stores a JSON array of text entries, oldest first. Clipman does not
+// record when entries were copied, so their order is kept with timestamps
+// one second apart ending at the file's modification time.
+type clipmanSource struct{}
+
+func (clipmanSource) ID() string   { return "clipman" }
+func (clipmanSource) Name() string { return "Clipman" }
+
+func (clipmanSource) DefaultPath() string {
+	return firstExisting(xdgPath("XDG_DATA_HOME", ".local/share", "clipman.json"))
+}
+
+func (clipmanSource) Read(ctx context.Context, path string) ([]*database.ClipboardItem, int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var history []string
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, 0, err
+	}
+
+	times := sequenceTimes(path, len(history))
+
+	var items []*database.ClipboardItem
+	invalid := 0
+	for i, content := range history {
+		item := newTextItem(content, times[i])
+		if item == nil {
+			invalid++
+			continue
+		}
+		items = append(items, item)
+	}
+
+	return items, invalid, nil
+}
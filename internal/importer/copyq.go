@@ -0,0 +1,276 @@
+package importer
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+
+	"clipboardpro/internal/database"
+)
+
+func init() {
+	Register(copyqSource{})
+}
+
+// copyqSource reads a CopyQ tab file, by default the clipboard tab. Tabs are
+// Qt data streams holding a map of MIME type to data for each item, newest
+// first. Text, PNG images, notes and pins are imported. CopyQ does not record
+// when items were copied; their order is kept with timestamps one second
+// apart ending at the file's modification time. Tabs saved by plugins, such
+// as encrypted tabs, cannot be read.
+type copyqSource struct{}
+
+const (
+	copyqMimePrefix = "application/x-copyq-"
+	copyqMimeNotes  = copyqMimePrefix + "item-notes"
+	copyqMimePinned = copyqMimePrefix + "item-pinned"
+)
+
+func (copyqSource) ID() string   { return "copyq" }
+func (copyqSource) Name() string { return "CopyQ" }
+
+func (copyqSource) DefaultPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	// Tab files are named after the base64 encoded tab name, "&clipboard"
+	return firstExisting(
+		filepath.Join(configDir, "copyq", "copyq_tab_JmNsaXBib2FyZA==.dat"),
+		homePath(".config", "copyq", "copyq_tab_JmNsaXBib2FyZA==.dat"),
+	)
+}
+
+func (copyqSource) Read(ctx context.Context, path string) ([]*database.ClipboardItem, int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	stream := &qtStream{data: data}
+
+	// Plain tabs start with the item count; tabs saved by plugins start
+	// with a header naming the plugin.
+	if header, ok := stream.peekHeader(); ok {
+		return nil, 0, fmt.Errorf("tab saved by a CopyQ plugin (%s) is not supported", header)
+	}
+
+	count := stream.int32()
+	if stream.err != nil || count < 0 {
+		return nil, 0, errors.New("not a CopyQ tab file")
+	}
+
+	entries := make([]map[string][]byte, 0, count)
+	invalid := 0
+	for i := int32(0); i < count && stream.err == nil; i++ {
+		entry, ok := stream.item()
+		if !ok {
+			invalid++
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if stream.err != nil {
+		return nil, 0, fmt.Errorf("failed to read item %d: %w", len(entries)+invalid, stream.err)
+	}
+
+	times := sequenceTimes(path, len(entries))
+
+	var items []*database.ClipboardItem
+	for i, entry := range entries {
+		timestamp := times[len(times)-1-i]
+
+		var item *database.ClipboardItem
+		if png := entry["image/png"]; png != nil {
+			item, _ = newImageItem(png, timestamp)
+		} else {
+			item = newTextItem(string(copyqText(entry)), timestamp)
+		}
+
+		if item == nil {
+			invalid++
+			continue
+		}
+		item.Notes = string(entry[copyqMimeNotes])
+		_, item.Pinned = entry[copyqMimePinned]
+		items = append(items, item)
+	}
+
+	return items, invalid, nil
+}
+
+// copyqText returns the plain text of an item, if any.
+func copyqText(entry map[string][]byte) []byte {
+	if text, ok := entry["text/plain"]; ok {
+		return text
+	}
+	for mime, data := range entry {
+		if strings.HasPrefix(mime, "text/plain;") {
+			return data
+		}
+	}
+	return nil
+}
+
+// qtStream reads the subset of Qt's QDataStream format used by CopyQ tabs.
+// Values are big-endian; the first error stops further reads.
+type qtStream struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (s *qtStream) next(n int) []byte {
+	if s.err != nil {
+		return nil
+	}
+	if n < 0 || s.pos+n > len(s.data) {
+		s.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	b := s.data[s.pos : s.pos+n]
+	s.pos += n
+	return b
+}
+
+func (s *qtStream) int32() int32 {
+	b := s.next(4)
+	if b == nil {
+		return 0
+	}
+	return int32(binary.BigEndian.Uint32(b))
+}
+
+func (s *qtStream) bool() bool {
+	b := s.next(1)
+	return b != nil && b[0] != 0
+}
+
+// bytes reads a length-prefixed QByteArray or QString body. A length of
+// 0xFFFFFFFF marks a null value.
+func (s *qtStream) bytes() []byte {
+	n := uint32(s.int32())
+	if s.err != nil || n == 0xFFFFFFFF {
+		return nil
+	}
+	return s.next(int(n))
+}
+
+// peekHeader reports whether the stream starts with a "CopyQ..." string
+// header, as written by plugins, and returns it without consuming anything.
+func (s *qtStream) peekHeader() (string, bool) {
+	probe := &qtStream{data: s.data}
+	header := decodeQtString(probe.bytes())
+	if probe.err != nil || !strings.HasPrefix(header, "CopyQ") {
+		return "", false
+	}
+	return header, true
+}
+
+// item reads one item. Items stored in the oldest format, as a serialized
+// QVariantMap, are skipped.
+func (s *qtStream) item() (map[string][]byte, bool) {
+	version := 1
+	size := s.int32()
+	switch size {
+	case -1:
+		s.err = errors.New("items saved by very old CopyQ versions are not supported")
+		return nil, false
+	case -2:
+		version = 2
+		size = s.int32()
+	}
+
+	if size < 0 || size > 1024 {
+		s.err = fmt.Errorf("unexpected number of formats: %d", size)
+		return nil, false
+	}
+
+	entry := make(map[string][]byte, size)
+	valid := true
+	for i := int32(0); i < size && s.err == nil; i++ {
+		mime := decompressMime(decodeQtString(s.bytes()))
+
+		// Version 1 always compresses data; version 2 says whether it does
+		compressed := true
+		if version == 2 {
+			compressed = s.bool()
+		}
+		data := s.bytes()
+		if s.err != nil {
+			break
+		}
+
+		if compressed {
+			var err error
+			if data, err = qUncompress(data); err != nil {
+				valid = false
+				continue
+			}
+		}
+		entry[mime] = data
+	}
+
+	return entry, valid && s.err == nil
+}
+
+// decodeQtString decodes a string field, which CopyQ writes either as a
+// QString (UTF-16BE) or as UTF-8 bytes depending on the version.
+func decodeQtString(b []byte) string {
+	if len(b) >= 2 && len(b)%2 == 0 && b[0] == 0 {
+		units := make([]uint16, len(b)/2)
+		for i := range units {
+			units[i] = binary.BigEndian.Uint16(b[2*i:])
+		}
+		return string(utf16.Decode(units))
+	}
+	return string(b)
+}
+
+// decompressMime expands the single-character prefixes CopyQ uses to shorten
+// common MIME types.
+func decompressMime(mime string) string {
+	if mime == "" {
+		return mime
+	}
+
+	prefixes := map[byte]string{
+		'0': copyqMimePrefix,
+		'1': "text/",
+		'2': "application/",
+		'3': "image/",
+	}
+	if prefix, ok := prefixes[mime[0]]; ok {
+		return prefix + mime[1:]
+	}
+	return mime
+}
+
+// qUncompress reverses Qt's qCompress: a big-endian length followed by a
+// zlib stream.
+func qUncompress(data []byte) ([]byte, error) {
+	if len(data) < 4 {
+		return nil, errors.New("compressed data too short")
+	}
+
+	size := binary.BigEndian.Uint32(data)
+	reader, err := zlib.NewReader(bytes.NewReader(data[4:]))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	out := bytes.NewBuffer(make([]byte, 0, min(size, 64*1024*1024)))
+	if _, err := io.Copy(out, reader); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
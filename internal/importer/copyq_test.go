@@ -0,0 +1,203 @@
+package importer
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/binary"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"unicode/utf16"
+)
+
+// qtWriter writes the parts of Qt's QDataStream format read by qtStream.
+type qtWriter struct {
+	bytes.Buffer
+}
+
+func (w *qtWriter) int32(v int32) {
+	binary.Write(&w.Buffer, binary.BigEndian, v)
+}
+
+func (w *qtWriter) bytes(b []byte) {
+	w.int32(int32(len(b)))
+	w.Write(b)
+}
+
+// qString writes s as a QString, in UTF-16BE.
+func (w *qtWriter) qString(s string) {
+	units := utf16.Encode([]rune(s))
+	w.int32(int32(2 * len(units)))
+	for _, unit := range units {
+		binary.Write(&w.Buffer, binary.BigEndian, unit)
+	}
+}
+
+func (w *qtWriter) bool(b bool) {
+	if b {
+		w.WriteByte(1)
+	} else {
+		w.WriteByte(0)
+	}
+}
+
+// qCompress compresses data the way Qt's qCompress does.
+func qCompress(data []byte) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, uint32(len(data)))
+	zw := zlib.NewWriter(&buf)
+	zw.Write(data)
+	zw.Close()
+	return buf.Bytes()
+}
+
+// copyqFormat is one MIME type of a CopyQ item.
+type copyqFormat struct {
+	mime       string
+	data       []byte
+	compressed bool // Only honoured by version 2 items
+}
+
+// writeItemV2 writes an item in the current format, which says per format
+// whether the data is compressed.
+func (w *qtWriter) writeItemV2(formats ...copyqFormat) {
+	w.int32(-2)
+	w.int32(int32(len(formats)))
+	for _, f := range formats {
+		w.bytes([]byte(f.mime))
+		w.bool(f.compressed)
+		if f.compressed {
+			w.bytes(qCompress(f.data))
+		} else {
+			w.bytes(f.data)
+		}
+	}
+}
+
+// writeItemV1 writes an item in the older format, with MIME types as
+// QStrings and all data compressed.
+func (w *qtWriter) writeItemV1(formats ...copyqFormat) {
+	w.int32(int32(len(formats)))
+	for _, f := range formats {
+		w.qString(f.mime)
+		w.bytes(qCompress(f.data))
+	}
+}
+
+func samplePNG(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func writeFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCopyQRead(t *testing.T) {
+	pngData := samplePNG(t)
+
+	var w qtWriter
+	w.int32(5)
+	w.writeItemV2( // Newest
+		copyqFormat{mime: "text/plain", data: []byte("newest"), compressed: true},
+		copyqFormat{mime: "0item-notes", data: []byte("a note")},
+		copyqFormat{mime: "0item-pinned", data: nil},
+	)
+	w.writeItemV2(copyqFormat{mime: "3png", data: pngData})
+	w.writeItemV1(copyqFormat{mime: "text/plain;charset=utf-8", data: []byte("older")})
+	w.writeItemV2(copyqFormat{mime: "text/html", data: []byte("<b>no plain text</b>")})
+	w.writeItemV2(copyqFormat{mime: "1plain", data: []byte("oldest")})
+
+	items, invalid, err := copyqSource{}.Read(context.Background(), writeFile(t, "tab.dat", w.Bytes()))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if invalid != 1 {
+		t.Errorf("invalid = %d, want 1", invalid)
+	}
+
+	want := []struct {
+		itemType, content, notes string
+		pinned                   bool
+	}{
+		{"text", "newest", "a note", true},
+		{"image", "", "", false},
+		{"text", "older", "", false},
+		{"text", "oldest", "", false},
+	}
+	if len(items) != len(want) {
+		t.Fatalf("got %d items, want %d", len(items), len(want))
+	}
+	for i, w := range want {
+		item := items[i]
+		if item.Type != w.itemType || item.Content != w.content || item.Notes != w.notes || item.Pinned != w.pinned {
+			t.Errorf("item %d = {%s %q %q %v}, want %+v", i, item.Type, item.Content, item.Notes, item.Pinned, w)
+		}
+		if i > 0 && !item.Timestamp.Before(items[i-1].Timestamp) {
+			t.Errorf("item %d is not older than item %d", i, i-1)
+		}
+	}
+	if !bytes.Equal(items[1].ImageData, pngData) {
+		t.Error("image data was not kept as is")
+	}
+}
+
+func TestCopyQReadRejects(t *testing.T) {
+	var plugin qtWriter
+	plugin.qString("CopyQ_encrypted_tab v2")
+	plugin.int32(0)
+
+	var oldest qtWriter
+	oldest.int32(1)
+	oldest.int32(-1)
+
+	var truncated qtWriter
+	truncated.int32(2)
+	truncated.writeItemV2(copyqFormat{mime: "text/plain", data: []byte("only one")})
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty file", nil},
+		{"plugin tab", plugin.Bytes()},
+		{"very old items", oldest.Bytes()},
+		{"truncated", truncated.Bytes()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := (copyqSource{}).Read(context.Background(), writeFile(t, "tab.dat", tt.data)); err == nil {
+				t.Error("Read succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestDecompressMime(t *testing.T) {
+	tests := map[string]string{
+		"":              "",
+		"1plain":        "text/plain",
+		"2json":         "application/json",
+		"3png":          "image/png",
+		"0item-notes":   copyqMimeNotes,
+		"text/uri-list": "text/uri-list",
+	}
+	for in, want := range tests {
+		if got := decompressMime(in); got != want {
+			t.Errorf("decompressMime(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
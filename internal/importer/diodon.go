@@ -0,0 +1,78 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"clipboardpro/internal/database"
+)
+
+func init() {
+	Register(diodonSource{})
+}
+
+// diodonSource reads the history of Diodon, which keeps its clipboard items
+// as events in the Zeitgeist activity log. Text is stored as the event
+// subject's text and images as PNG data in the event payload.
+type diodonSource struct{}
+
+const diodonActor = "application://diodon.desktop"
+
+func (diodonSource) ID() string   { return "diodon" }
+func (diodonSource) Name() string { return "Diodon" }
+
+func (diodonSource) DefaultPath() string {
+	return firstExisting(xdgPath("XDG_DATA_HOME", ".local/share", "zeitgeist", "activity.sqlite"))
+}
+
+func (diodonSource) Read(ctx context.Context, path string) ([]*database.ClipboardItem, int, error) {
+	db, err := database.OpenReadOnly(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT e.timestamp, t.value, p.value
+		FROM event e
+		JOIN actor a ON a.id = e.actor
+		LEFT JOIN text t ON t.id = e.subj_text
+		LEFT JOIN payload p ON p.id = e.payload
+		WHERE a.value = ?
+		ORDER BY e.timestamp`, diodonActor)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var items []*database.ClipboardItem
+	invalid := 0
+	for rows.Next() {
+		var (
+			millis  int64
+			text    sql.NullString
+			payload []byte
+		)
+		if err := rows.Scan(&millis, &text, &payload); err != nil {
+			return nil, 0, err
+		}
+
+		timestamp := time.UnixMilli(millis)
+
+		var item *database.ClipboardItem
+		if len(payload) > 0 && isImage(payload) {
+			item, _ = newImageItem(payload, timestamp)
+		} else {
+			item = newTextItem(text.String, timestamp)
+		}
+
+		if item == nil {
+			invalid++
+			continue
+		}
+		items = append(items, item)
+	}
+
+	return items, invalid, rows.Err()
+}
@@ -0,0 +1,194 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"clipboardpro/internal/database"
+)
+
+func init() {
+	Register(dittoSource{})
+}
+
+// dittoSource reads the SQLite database of Ditto, the Windows clipboard
+// manager. Clips marked "never auto delete" are imported as pinned and their
+// quick paste text becomes the title. Groups are skipped.
+type dittoSource struct{}
+
+func (dittoSource) ID() string   { return "ditto" }
+func (dittoSource) Name() string { return "Ditto" }
+
+func (dittoSource) DefaultPath() string {
+	appData := os.Getenv("APPDATA")
+	if appData == "" {
+		appData = homePath("AppData", "Roaming")
+		if appData == "" {
+			return ""
+		}
+	}
+	return firstExisting(filepath.Join(appData, "Ditto", "Ditto.db"))
+}
+
+// Clipboard formats read from the Data table, most useful first.
+const (
+	dittoUnicodeText = "CF_UNICODETEXT"
+	dittoPNG         = "PNG"
+	dittoDIB         = "CF_DIB"
+)
+
+func (dittoSource) Read(ctx context.Context, path string) ([]*database.ClipboardItem, int, error) {
+	db, err := database.OpenReadOnly(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer db.Close()
+
+	data, err := dittoClipData(ctx, db)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Columns added in later Ditto versions are read when present
+	optional := func(column, fallback string) string {
+		var count int
+		if err := db.QueryRowContext(ctx,
+			"SELECT COUNT(*) FROM pragma_table_info('Main') WHERE name = ?", column).Scan(&count); err != nil || count == 0 {
+			return fallback
+		}
+		return column
+	}
+
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT lID, lDate, mText, %s, %s
+		FROM Main
+		WHERE %s = 0
+		ORDER BY lDate`,
+		optional("lDontAutoDelete", "0"),
+		optional("QuickPasteText", "''"),
+		optional("bIsGroup", "0"),
+	))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var items []*database.ClipboardItem
+	invalid := 0
+	for rows.Next() {
+		var (
+			id, date, dontDelete int64
+			text, quickPaste     sql.NullString
+		)
+		if err := rows.Scan(&id, &date, &text, &dontDelete, &quickPaste); err != nil {
+			return nil, 0, err
+		}
+
+		timestamp := time.Unix(date, 0)
+		formats := data[id]
+
+		var item *database.ClipboardItem
+		switch {
+		case formats[dittoUnicodeText] != nil:
+			item = newTextItem(decodeUTF16(formats[dittoUnicodeText]), timestamp)
+		case formats[dittoPNG] != nil:
+			item, _ = newImageItem(formats[dittoPNG], timestamp)
+		case formats[dittoDIB] != nil:
+			item, _ = newImageItem(dibToBMP(formats[dittoDIB]), timestamp)
+		default:
+			item = newTextItem(text.String, timestamp)
+		}
+
+		if item == nil {
+			invalid++
+			continue
+		}
+		item.Pinned = dontDelete > 0
+		item.Title = strings.TrimSpace(quickPaste.String)
+		items = append(items, item)
+	}
+
+	return items, invalid, rows.Err()
+}
+
+// dittoClipData loads the clipboard formats this importer understands, keyed
+// by clip ID and format name.
+func dittoClipData(ctx context.Context, db *sql.DB) (map[int64]map[string][]byte, error) {
+	rows, err := db.QueryContext(ctx,
+		"SELECT lParentID, strClipBoardFormat, ooData FROM Data WHERE strClipBoardFormat IN (?, ?, ?)",
+		dittoUnicodeText, dittoPNG, dittoDIB)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	data := make(map[int64]map[string][]byte)
+	for rows.Next() {
+		var (
+			parentID int64
+			format   string
+			value    []byte
+		)
+		if err := rows.Scan(&parentID, &format, &value); err != nil {
+			return nil, err
+		}
+		if data[parentID] == nil {
+			data[parentID] = make(map[string][]byte)
+		}
+		data[parentID][format] = value
+	}
+
+	return data, rows.Err()
+}
+
+// decodeUTF16 decodes NUL-terminated little-endian UTF-16, as used by
+// CF_UNICODETEXT.
+func decodeUTF16(data []byte) string {
+	units := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		unit := binary.LittleEndian.Uint16(data[i:])
+		if unit == 0 {
+			break
+		}
+		units = append(units, unit)
+	}
+	return string(utf16.Decode(units))
+}
+
+// dibToBMP turns a device-independent bitmap, as stored for CF_DIB, into a
+// BMP file by prepending the file header.
+func dibToBMP(dib []byte) []byte {
+	const fileHeaderSize = 14
+	if len(dib) < 40 {
+		return dib
+	}
+
+	headerSize := binary.LittleEndian.Uint32(dib[0:])
+	bitCount := binary.LittleEndian.Uint16(dib[14:])
+	compression := binary.LittleEndian.Uint32(dib[16:])
+	colorsUsed := binary.LittleEndian.Uint32(dib[32:])
+
+	paletteSize := uint32(0)
+	if bitCount <= 8 {
+		if colorsUsed == 0 {
+			colorsUsed = 1 << bitCount
+		}
+		paletteSize = colorsUsed * 4
+	}
+	if headerSize == 40 && compression == 3 { // BI_BITFIELDS masks follow the header
+		paletteSize += 12
+	}
+
+	file := make([]byte, fileHeaderSize, fileHeaderSize+len(dib))
+	copy(file, "BM")
+	binary.LittleEndian.PutUint32(file[2:], uint32(fileHeaderSize+len(dib)))
+	binary.LittleEndian.PutUint32(file[10:], fileHeaderSize+headerSize+paletteSize)
+	return append(file, dib...)
+}
@@ -0,0 +1,164 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"image"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/uptrace/bun/driver/sqliteshim"
+)
+
+// utf16LE encodes s as NUL-terminated CF_UNICODETEXT.
+func utf16LE(s string) []byte {
+	var buf bytes.Buffer
+	for _, unit := range utf16.Encode([]rune(s + "\x00")) {
+		binary.Write(&buf, binary.LittleEndian, unit)
+	}
+	return buf.Bytes()
+}
+
+// sampleDIB returns a 1×1, 24-bit device-independent bitmap.
+func sampleDIB() []byte {
+	dib := make([]byte, 40+4)
+	binary.LittleEndian.PutUint32(dib[0:], 40) // Header size
+	binary.LittleEndian.PutUint32(dib[4:], 1)  // Width
+	binary.LittleEndian.PutUint32(dib[8:], 1)  // Height
+	binary.LittleEndian.PutUint16(dib[12:], 1) // Planes
+	binary.LittleEndian.PutUint16(dib[14:], 24)
+	copy(dib[40:], []byte{0x00, 0x00, 0xff}) // One red pixel, in BGR order
+	return dib
+}
+
+// writeDittoDatabase creates a Ditto database with the given Main columns
+// and rows, and Data rows of (clip ID, format, data).
+func writeDittoDatabase(t *testing.T, mainColumns string, main [][]any, data [][]any) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "Ditto.db")
+	db, err := sql.Open(sqliteshim.ShimName, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	statements := []string{
+		"CREATE TABLE Main (" + mainColumns + ")",
+		"CREATE TABLE Data (lID INTEGER PRIMARY KEY, lParentID INTEGER, strClipBoardFormat TEXT, ooData BLOB)",
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, row := range main {
+		placeholders := "?" + strings.Repeat(", ?", len(row)-1)
+		if _, err := db.Exec("INSERT INTO Main VALUES ("+placeholders+")", row...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, row := range data {
+		if _, err := db.Exec("INSERT INTO Data (lParentID, strClipBoardFormat, ooData) VALUES (?, ?, ?)", row...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path
+}
+
+func TestDittoRead(t *testing.T) {
+	pngData := samplePNG(t)
+	path := writeDittoDatabase(t,
+		"lID INTEGER PRIMARY KEY, lDate INTEGER, mText TEXT, lDontAutoDelete INTEGER, QuickPasteText TEXT, bIsGroup INTEGER",
+		[][]any{
+			{1, 1000, "ignored", 0, "", 0},
+			{2, 2000, "", 1, " sig ", 0},
+			{3, 3000, "", 0, "", 0},
+			{4, 4000, "from mText", 0, "", 0},
+			{5, 5000, "a group", 0, "", 1},
+			{6, 6000, "", 0, "", 0},
+		},
+		[][]any{
+			{1, dittoUnicodeText, utf16LE("héllo 👋")},
+			{2, dittoPNG, pngData},
+			{3, dittoDIB, sampleDIB()},
+			{3, "CF_TEXT", []byte("skipped format")},
+		},
+	)
+
+	items, invalid, err := dittoSource{}.Read(context.Background(), path)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if invalid != 1 {
+		t.Errorf("invalid = %d, want 1 (the clip without data)", invalid)
+	}
+
+	want := []struct {
+		itemType, content, title string
+		pinned                   bool
+		unix                     int64
+	}{
+		{"text", "héllo 👋", "", false, 1000},
+		{"image", "", "sig", true, 2000},
+		{"image", "", "", false, 3000},
+		{"text", "from mText", "", false, 4000},
+	}
+	if len(items) != len(want) {
+		t.Fatalf("got %d items, want %d", len(items), len(want))
+	}
+	for i, w := range want {
+		item := items[i]
+		if item.Type != w.itemType || item.Content != w.content || item.Title != w.title ||
+			item.Pinned != w.pinned || item.Timestamp.Unix() != w.unix {
+			t.Errorf("item %d = {%s %q %q %v %d}, want %+v",
+				i, item.Type, item.Content, item.Title, item.Pinned, item.Timestamp.Unix(), w)
+		}
+	}
+
+	// The DIB is converted to PNG
+	img, format, err := image.Decode(bytes.NewReader(items[2].ImageData))
+	if err != nil || format != "png" {
+		t.Fatalf("DIB image decoded as %q: %v", format, err)
+	}
+	if r, g, b, _ := img.At(0, 0).RGBA(); r>>8 != 0xff || g != 0 || b != 0 {
+		t.Errorf("DIB pixel = %d, %d, %d, want red", r>>8, g>>8, b>>8)
+	}
+}
+
+func TestDittoReadOldSchema(t *testing.T) {
+	// Early versions have no pin, quick paste or group columns
+	path := writeDittoDatabase(t,
+		"lID INTEGER PRIMARY KEY, lDate INTEGER, mText TEXT",
+		[][]any{{1, 1000, "plain"}},
+		nil,
+	)
+
+	items, invalid, err := dittoSource{}.Read(context.Background(), path)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if invalid != 0 || len(items) != 1 || items[0].Content != "plain" || items[0].Pinned {
+		t.Errorf("Read = %d items (%d invalid), want one unpinned item", len(items), invalid)
+	}
+}
+
+func TestDecodeUTF16(t *testing.T) {
+	tests := []struct {
+		data []byte
+		want string
+	}{
+		{nil, ""},
+		{utf16LE("abc"), "abc"},
+		{append(utf16LE("stop"), utf16LE("after the terminator")...), "stop"},
+		{[]byte{'a', 0, 'b'}, "a"}, // Odd trailing byte
+	}
+	for _, tt := range tests {
+		if got := decodeUTF16(tt.data); got != tt.want {
+			t.Errorf("decodeUTF16(%v) = %q, want %q", tt.data, got, tt.want)
+		}
+	}
+}
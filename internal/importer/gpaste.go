@@ -0,0 +1,108 @@
+package importer
+
+import (
+	"context"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"clipboardpro/internal/database"
+)
+
+func init() {
+	Register(gpasteSource{})
+}
+
+// gpasteSource reads the XML history of GPaste, the GNOME clipboard manager.
+// Images are stored next to the history, in an images directory named by
+// item UUID. Password items are skipped.
+type gpasteSource struct{}
+
+func (gpasteSource) ID() string   { return "gpaste" }
+func (gpasteSource) Name() string { return "GPaste" }
+
+func (gpasteSource) DefaultPath() string {
+	return firstExisting(xdgPath("XDG_DATA_HOME", ".local/share", "gpaste", "history.xml"))
+}
+
+type gpasteHistory struct {
+	Items []gpasteItem `xml:"item"`
+}
+
+type gpasteItem struct {
+	Kind  string `xml:"kind,attr"`
+	UUID  string `xml:"uuid,attr"`
+	Date  string `xml:"date,attr"`
+	Image string `xml:"image,attr"` // Image path, in older history versions
+	Value string `xml:"value"`
+}
+
+func (gpasteSource) Read(ctx context.Context, path string) ([]*database.ClipboardItem, int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var history gpasteHistory
+	if err := xml.Unmarshal(data, &history); err != nil {
+		return nil, 0, err
+	}
+
+	// The history is newest first
+	fallback := sequenceTimes(path, len(history.Items))
+	imagesDir := filepath.Join(filepath.Dir(path), "images")
+
+	var items []*database.ClipboardItem
+	invalid := 0
+	for i, entry := range history.Items {
+		timestamp, ok := parseUnixTime(entry.Date)
+		if !ok {
+			timestamp = fallback[len(fallback)-1-i]
+		}
+
+		var item *database.ClipboardItem
+		switch entry.Kind {
+		case "Text", "Uris":
+			item = newTextItem(entry.Value, timestamp)
+		case "Image":
+			imagePath := entry.Image
+			if imagePath == "" {
+				imagePath = filepath.Join(imagesDir, entry.UUID+".png")
+			}
+			if imageData, err := os.ReadFile(imagePath); err == nil {
+				item, _ = newImageItem(imageData, timestamp)
+			}
+		case "Password":
+			continue
+		}
+
+		if item == nil {
+			invalid++
+			continue
+		}
+		items = append(items, item)
+	}
+
+	return items, invalid, nil
+}
+
+// parseUnixTime parses a Unix time in seconds, milliseconds or microseconds,
+// guessing the unit from its size.
+func parseUnixTime(value string) (time.Time, bool) {
+	n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || n <= 0 {
+		return time.Time{}, false
+	}
+
+	switch {
+	case n > 1e14:
+		return time.UnixMicro(n), true
+	case n > 1e11:
+		return time.UnixMilli(n), true
+	default:
+		return time.Unix(n, 0), true
+	}
+}
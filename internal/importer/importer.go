@@ -0,0 +1,251 @@
+// Package importer merges clipboard history from other sources into the
+// database, matching items by their content hash.
+package importer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"clipboardpro/internal/database"
+)
+
+// Policy decides what happens when an imported item already exists.
+type Policy string
+
+const (
+	KeepNewest   Policy = "keep-newest"   // Take the title, notes and pin of whichever copy was used last
+	KeepExisting Policy = "keep-existing" // Leave existing items untouched
+	MergeTitles  Policy = "merge-titles"  // Fill in missing titles and notes, keep pins from either copy
+)
+
+// Policies lists the conflict policies in the order they are offered.
+var Policies = []Policy{KeepNewest, KeepExisting, MergeTitles}
+
+// Description returns a short explanation of a policy for display.
+func (p Policy) Description() string {
+	switch p {
+	case KeepExisting:
+		return "Keep existing items unchanged"
+	case MergeTitles:
+		return "Merge titles, notes and pins"
+	default:
+		return "Keep the most recently used copy"
+	}
+}
+
+// Summary counts what an import does, or would do.
+type Summary struct {
+	Read      int // Items read from the source
+	Invalid   int // Records that could not be read as items
+	Duplicate int // Items repeated within the source
+	New       int // Items that will be added
+	Updated   int // Existing items that will change
+	Unchanged int // Existing items left as they are
+	Pinned    int // Pinned items among New and Updated
+}
+
+func (s Summary) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d items read\n", s.Read)
+	fmt.Fprintf(&b, "%d new items will be added\n", s.New)
+	fmt.Fprintf(&b, "%d existing items will be updated\n", s.Updated)
+	fmt.Fprintf(&b, "%d existing items will stay as they are\n", s.Unchanged)
+	if s.Pinned > 0 {
+		fmt.Fprintf(&b, "%d pinned items\n", s.Pinned)
+	}
+	if s.Duplicate > 0 {
+		fmt.Fprintf(&b, "%d duplicates in the file were ignored\n", s.Duplicate)
+	}
+	if s.Invalid > 0 {
+		fmt.Fprintf(&b, "%d records could not be read and will be skipped\n", s.Invalid)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Plan is the result of comparing imported items with the database. Nothing
+// is written until Apply is called, so a plan doubles as a dry run.
+type Plan struct {
+	Source  string // Name of the source the items were read from
+	Path    string
+	Summary Summary
+
+	inserts  []*database.ClipboardItem
+	updates  []*database.ClipboardItem
+	readTime time.Duration
+}
+
+// NewPlan works out how items merge into the database under policy. Items
+// must have their Hash set. invalid is the number of records the caller
+// could not read, reported in the summary.
+func NewPlan(ctx context.Context, repository database.Store, items []*database.ClipboardItem, invalid int, policy Policy) (*Plan, error) {
+	plan := &Plan{Summary: Summary{Read: len(items) + invalid, Invalid: invalid}}
+
+	// Within the source the most recently copied duplicate wins
+	unique := make(map[string]*database.ClipboardItem, len(items))
+	hashes := make([]string, 0, len(items))
+	for _, item := range items {
+		if previous, ok := unique[item.Hash]; ok {
+			plan.Summary.Duplicate++
+			if !item.Timestamp.After(previous.Timestamp) {
+				continue
+			}
+		} else {
+			hashes = append(hashes, item.Hash)
+		}
+		unique[item.Hash] = item
+	}
+
+	existing, err := repository.GetItemsByHash(ctx, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Visit pinned items in their original order so they keep it when placed
+	// below the existing pins
+	sort.SliceStable(hashes, func(i, j int) bool {
+		return unique[hashes[i]].Position < unique[hashes[j]].Position
+	})
+
+	for _, hash := range hashes {
+		item := unique[hash]
+		current, ok := existing[hash]
+		if !ok {
+			if item.Pinned {
+				plan.Summary.Pinned++
+			}
+			item.Position = 0
+			plan.inserts = append(plan.inserts, item)
+			continue
+		}
+
+		merged, changed := merge(current, item, policy)
+		if !changed {
+			plan.Summary.Unchanged++
+			continue
+		}
+		if merged.Pinned {
+			plan.Summary.Pinned++
+		}
+		plan.updates = append(plan.updates, merged)
+	}
+
+	plan.Summary.New = len(plan.inserts)
+	plan.Summary.Updated = len(plan.updates)
+
+	return plan, nil
+}
+
+// Report describes a finished import.
+type Report struct {
+	Source   string
+	Path     string
+	Summary  Summary
+	Duration time.Duration
+}
+
+func (r *Report) String() string {
+	s := r.Summary
+	var b strings.Builder
+	fmt.Fprintf(&b, "Imported from %s\n", r.Source)
+	fmt.Fprintf(&b, "%s\n", r.Path)
+	fmt.Fprintf(&b, "%d items read in %s\n", s.Read, r.Duration.Round(time.Millisecond))
+	fmt.Fprintf(&b, "%d added, %d updated, %d unchanged\n", s.New, s.Updated, s.Unchanged)
+	if s.Pinned > 0 {
+		fmt.Fprintf(&b, "%d pinned\n", s.Pinned)
+	}
+	if s.Duplicate > 0 {
+		fmt.Fprintf(&b, "%d duplicates ignored\n", s.Duplicate)
+	}
+	if s.Invalid > 0 {
+		fmt.Fprintf(&b, "%d entries skipped because they could not be read\n", s.Invalid)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Apply writes the plan to the database and reports what was imported.
+func (p *Plan) Apply(ctx context.Context, repository database.Store) (*Report, error) {
+	start := time.Now()
+	if len(p.inserts) > 0 || len(p.updates) > 0 {
+		if err := repository.ImportItems(ctx, p.inserts, p.updates); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Report{
+		Source:   p.Source,
+		Path:     p.Path,
+		Summary:  p.Summary,
+		Duration: p.readTime + time.Since(start),
+	}, nil
+}
+
+// merge combines an existing item with an imported copy of it. It returns the
+// updated existing item and whether anything changed. Trashed items always
+// change, as importing them brings them back.
+func merge(current, imported *database.ClipboardItem, policy Policy) (*database.ClipboardItem, bool) {
+	merged := *current
+	wasPinned := current.Pinned
+
+	switch policy {
+	case KeepExisting:
+		return current, current.IsTrashed()
+
+	case MergeTitles:
+		if merged.Title == "" {
+			merged.Title = imported.Title
+		}
+		if imported.Notes != "" && !strings.Contains(merged.Notes, imported.Notes) {
+			if merged.Notes != "" {
+				merged.Notes += "\n\n"
+			}
+			merged.Notes += imported.Notes
+		}
+		merged.Tags = database.NormalizeTags(current.Tags + "," + imported.Tags)
+		merged.Pinned = current.Pinned || imported.Pinned
+		merged.CopyCount = max(current.CopyCount, imported.CopyCount)
+		if imported.Timestamp.After(current.Timestamp) {
+			merged.Timestamp = imported.Timestamp
+		}
+		if imported.LastUsedAt.After(current.LastUsedAt) {
+			merged.LastUsedAt = imported.LastUsedAt
+		}
+
+	default: // KeepNewest
+		if !lastUsed(imported).After(lastUsed(current)) {
+			return current, current.IsTrashed()
+		}
+		merged.Title = imported.Title
+		merged.Notes = imported.Notes
+		merged.Tags = imported.Tags
+		merged.Pinned = imported.Pinned
+		merged.Timestamp = imported.Timestamp
+		merged.CopyCount = imported.CopyCount
+		merged.LastUsedAt = imported.LastUsedAt
+	}
+
+	if merged.Pinned && !wasPinned {
+		merged.Position = 0 // Placed with the other imported pins
+	}
+
+	changed := current.IsTrashed() ||
+		merged.Title != current.Title ||
+		merged.Notes != current.Notes ||
+		merged.Tags != current.Tags ||
+		merged.Pinned != current.Pinned ||
+		!merged.Timestamp.Equal(current.Timestamp) ||
+		merged.CopyCount != current.CopyCount ||
+		!merged.LastUsedAt.Equal(current.LastUsedAt)
+
+	return &merged, changed
+}
+
+// lastUsed returns when an item was last copied or used.
+func lastUsed(item *database.ClipboardItem) time.Time {
+	if item.LastUsedAt.After(item.Timestamp) {
+		return item.LastUsedAt
+	}
+	return item.Timestamp
+}
@@ -0,0 +1,277 @@
+package importer
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"clipboardpro/internal/database"
+	"clipboardpro/internal/util"
+)
+
+var importBase = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// textItem returns a text item copied minutes after importBase.
+func textItem(content string, minutes int) *database.ClipboardItem {
+	return &database.ClipboardItem{
+		Type:      "text",
+		Content:   content,
+		Size:      len(content),
+		Hash:      util.GenerateHash(content, nil),
+		Timestamp: importBase.Add(time.Duration(minutes) * time.Minute),
+	}
+}
+
+func newImportRepository(t *testing.T, existing ...*database.ClipboardItem) *database.Repository {
+	t.Helper()
+	repository, err := database.NewMemoryRepository()
+	if err != nil {
+		t.Fatalf("NewMemoryRepository: %v", err)
+	}
+	t.Cleanup(func() { repository.Close() })
+
+	if len(existing) > 0 {
+		if err := repository.ImportItems(context.Background(), existing, nil); err != nil {
+			t.Fatalf("ImportItems: %v", err)
+		}
+	}
+	return repository
+}
+
+// importItems plans and applies an import, returning its summary.
+func importItems(t *testing.T, repository database.Store, items []*database.ClipboardItem, policy Policy) Summary {
+	t.Helper()
+	ctx := context.Background()
+	plan, err := NewPlan(ctx, repository, items, 0, policy)
+	if err != nil {
+		t.Fatalf("NewPlan: %v", err)
+	}
+	if _, err := plan.Apply(ctx, repository); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	return plan.Summary
+}
+
+// itemByContent returns the stored item, trashed or not, with a text content.
+func itemByContent(t *testing.T, repository database.Store, content string) *database.ClipboardItem {
+	t.Helper()
+	hash := util.GenerateHash(content, nil)
+	found, err := repository.GetItemsByHash(context.Background(), []string{hash})
+	if err != nil {
+		t.Fatalf("GetItemsByHash: %v", err)
+	}
+	item, ok := found[hash]
+	if !ok {
+		t.Fatalf("no item with content %q", content)
+	}
+	return item
+}
+
+// pinnedContents returns the contents of the pinned items in list order.
+func pinnedContents(t *testing.T, repository database.Store) []string {
+	t.Helper()
+	page, err := repository.ListItems(context.Background(), database.ListQuery{Limit: 100})
+	if err != nil {
+		t.Fatalf("ListItems: %v", err)
+	}
+	var contents []string
+	for _, item := range page.Items {
+		if item.Pinned {
+			contents = append(contents, item.Preview)
+		}
+	}
+	return contents
+}
+
+func TestImportPolicies(t *testing.T) {
+	type fields struct {
+		title, notes, tags string
+		pinned             bool
+		copyCount          int
+	}
+	existingFields := fields{"old title", "old notes", "a", false, 2}
+
+	tests := []struct {
+		name        string
+		policy      Policy
+		existing    func(item *database.ClipboardItem)
+		imported    func(item *database.ClipboardItem)
+		want        fields
+		wantUpdated int
+	}{
+		{
+			name:        "keep newest takes a newer copy",
+			policy:      KeepNewest,
+			want:        fields{"new title", "new notes", "b", true, 5},
+			wantUpdated: 1,
+		},
+		{
+			name:   "keep newest ignores an older copy",
+			policy: KeepNewest,
+			imported: func(item *database.ClipboardItem) {
+				item.Timestamp = importBase.Add(-time.Hour)
+			},
+			want: existingFields,
+		},
+		{
+			name:   "keep newest counts use after the copy",
+			policy: KeepNewest,
+			existing: func(item *database.ClipboardItem) {
+				item.LastUsedAt = importBase.Add(2 * time.Hour)
+			},
+			want: existingFields,
+		},
+		{
+			name:   "keep existing",
+			policy: KeepExisting,
+			want:   existingFields,
+		},
+		{
+			name:        "merge titles keeps a title and appends notes",
+			policy:      MergeTitles,
+			want:        fields{"old title", "old notes\n\nnew notes", "a,b", true, 5},
+			wantUpdated: 1,
+		},
+		{
+			name:   "merge titles fills in a missing title",
+			policy: MergeTitles,
+			existing: func(item *database.ClipboardItem) {
+				item.Title = ""
+				item.Notes = ""
+				item.Pinned = true
+			},
+			imported: func(item *database.ClipboardItem) {
+				item.Notes = ""
+				item.Pinned = false
+				item.CopyCount = 1
+			},
+			want:        fields{"new title", "", "a,b", true, 2},
+			wantUpdated: 1,
+		},
+		{
+			name:   "merge titles does not repeat notes",
+			policy: MergeTitles,
+			imported: func(item *database.ClipboardItem) {
+				item.Title = ""
+				item.Notes = "old notes"
+				item.Tags = "a"
+				item.Pinned = false
+				item.CopyCount = 1
+				item.Timestamp = importBase
+			},
+			want: existingFields,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing := textItem("shared", 0)
+			existing.Title, existing.Notes, existing.Tags = "old title", "old notes", "a"
+			existing.CopyCount = 2
+			if tt.existing != nil {
+				tt.existing(existing)
+			}
+			repository := newImportRepository(t, existing)
+
+			imported := textItem("shared", 60)
+			imported.Title, imported.Notes, imported.Tags = "new title", "new notes", "b"
+			imported.Pinned = true
+			imported.CopyCount = 5
+			if tt.imported != nil {
+				tt.imported(imported)
+			}
+
+			summary := importItems(t, repository, []*database.ClipboardItem{imported}, tt.policy)
+			if summary.New != 0 || summary.Updated != tt.wantUpdated || summary.Unchanged != 1-tt.wantUpdated {
+				t.Errorf("summary = %+v, want %d updated", summary, tt.wantUpdated)
+			}
+
+			item := itemByContent(t, repository, "shared")
+			got := fields{item.Title, item.Notes, item.Tags, item.Pinned, item.CopyCount}
+			if got != tt.want {
+				t.Errorf("item = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImportDuplicatesInSource(t *testing.T) {
+	repository := newImportRepository(t)
+
+	older := textItem("repeated", 10)
+	older.Title = "older"
+	newest := textItem("repeated", 30)
+	newest.Title = "newest"
+	oldest := textItem("repeated", 0)
+	oldest.Title = "oldest"
+
+	summary := importItems(t, repository, []*database.ClipboardItem{older, newest, oldest, textItem("single", 5)}, KeepNewest)
+	if summary.Read != 4 || summary.Duplicate != 2 || summary.New != 2 {
+		t.Errorf("summary = %+v, want 4 read, 2 duplicates and 2 new", summary)
+	}
+
+	item := itemByContent(t, repository, "repeated")
+	if item.Title != "newest" || !item.Timestamp.Equal(newest.Timestamp) {
+		t.Errorf("kept %q copied at %v, want the newest copy", item.Title, item.Timestamp)
+	}
+}
+
+func TestImportRestoresTrashedItems(t *testing.T) {
+	for _, policy := range Policies {
+		t.Run(string(policy), func(t *testing.T) {
+			ctx := context.Background()
+			existing := textItem("trashed", 60)
+			existing.Title = "kept"
+			repository := newImportRepository(t, existing)
+			if err := repository.TrashItems(ctx, []int64{existing.ID}, "test"); err != nil {
+				t.Fatalf("TrashItems: %v", err)
+			}
+
+			// An older copy would change nothing about an item in the history
+			imported := textItem("trashed", 0)
+			summary := importItems(t, repository, []*database.ClipboardItem{imported}, policy)
+			if summary.Updated != 1 {
+				t.Errorf("summary = %+v, want 1 updated", summary)
+			}
+
+			item := itemByContent(t, repository, "trashed")
+			if item.IsTrashed() {
+				t.Error("item is still in the trash")
+			}
+			if item.Title != "kept" {
+				t.Errorf("title = %q, want the existing title", item.Title)
+			}
+		})
+	}
+}
+
+func TestImportPinPositions(t *testing.T) {
+	first := textItem("first pin", 0)
+	first.Pinned, first.Position = true, 1
+	second := textItem("second pin", 1)
+	second.Pinned, second.Position = true, 2
+	unpinned := textItem("becomes pinned", 2)
+	repository := newImportRepository(t, first, second, unpinned)
+
+	// Source positions order the new pins, whatever order they are read in
+	lower := textItem("new lower pin", 3)
+	lower.Pinned, lower.Position = true, 7
+	upper := textItem("new upper pin", 4)
+	upper.Pinned, upper.Position = true, 3
+	repinned := textItem("becomes pinned", 10)
+	repinned.Pinned, repinned.Position = true, 1
+	// An existing pin keeps its place
+	same := textItem("first pin", 20)
+	same.Pinned, same.Position = true, 9
+
+	summary := importItems(t, repository, []*database.ClipboardItem{lower, upper, repinned, same}, KeepNewest)
+	if summary.Pinned != 4 {
+		t.Errorf("summary = %+v, want 4 pinned", summary)
+	}
+
+	want := []string{"first pin", "second pin", "becomes pinned", "new upper pin", "new lower pin"}
+	if got := pinnedContents(t, repository); !slices.Equal(got, want) {
+		t.Errorf("pinned order = %q, want %q", got, want)
+	}
+}
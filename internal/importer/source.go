@@ -0,0 +1,202 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	_ "golang.org/x/image/bmp"
+
+	"clipboardpro/internal/database"
+	"clipboardpro/internal/util"
+)
+
+// Source reads the history of a clipboard manager. Sources register
+// themselves with Register, usually from an init function, and are then
+// offered by the import dialog and the import command.
+type Source interface {
+	// ID is a short name used on the command line.
+	ID() string
+	// Name is shown to the user.
+	Name() string
+	// DefaultPath returns where the history is usually stored on this
+	// system, or "" if there is no usual location or it does not exist.
+	DefaultPath() string
+	// Read loads the history at path. Entries that cannot be turned into
+	// items are counted in invalid rather than failing the whole read.
+	Read(ctx context.Context, path string) (items []*database.ClipboardItem, invalid int, err error)
+}
+
+var sources = map[string]Source{}
+
+// Register makes a source available. Registering the same ID twice panics.
+func Register(source Source) {
+	if _, ok := sources[source.ID()]; ok {
+		panic("importer: source registered twice: " + source.ID())
+	}
+	sources[source.ID()] = source
+}
+
+// Sources returns the registered sources, this application's own exports
+// first and the rest by name.
+func Sources() []Source {
+	list := make([]Source, 0, len(sources))
+	for _, source := range sources {
+		list = append(list, source)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if (list[i].ID() == exportSource{}.ID()) != (list[j].ID() == exportSource{}.ID()) {
+			return list[i].ID() == exportSource{}.ID()
+		}
+		return strings.ToLower(list[i].Name()) < strings.ToLower(list[j].Name())
+	})
+	return list
+}
+
+// Lookup returns the source registered under id.
+func Lookup(id string) (Source, bool) {
+	source, ok := sources[id]
+	return source, ok
+}
+
+// PlanSource reads the history at path with source and plans merging it
+// into the database.
+func PlanSource(ctx context.Context, repository database.Store, source Source, path string, policy Policy) (*Plan, error) {
+	start := time.Now()
+	items, invalid, err := source.Read(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+
+	plan, err := NewPlan(ctx, repository, items, invalid, policy)
+	if err != nil {
+		return nil, err
+	}
+	plan.Source = source.Name()
+	plan.Path = path
+	plan.readTime = time.Since(start)
+	return plan, nil
+}
+
+// newTextItem creates a text item, or returns nil if there is no text.
+func newTextItem(content string, timestamp time.Time) *database.ClipboardItem {
+	if strings.TrimSpace(content) == "" || !utf8.ValidString(content) {
+		return nil
+	}
+
+	return &database.ClipboardItem{
+		Type:      "text",
+		Content:   content,
+		Size:      len(content),
+		Hash:      util.GenerateHash(content, nil),
+		Timestamp: timestamp,
+		CreatedAt: timestamp,
+	}
+}
+
+// newImageItem creates an image item from encoded image data, converting it
+// to PNG if needed.
+func newImageItem(data []byte, timestamp time.Time) (*database.ClipboardItem, error) {
+	data, err := toPNG(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &database.ClipboardItem{
+		Type:      "image",
+		ImageData: data,
+		Size:      len(data),
+		Hash:      util.GenerateHash("", data),
+		Timestamp: timestamp,
+		CreatedAt: timestamp,
+	}, nil
+}
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// toPNG returns data encoded as PNG. PNG data is returned unchanged; JPEG,
+// GIF and BMP data is converted.
+func toPNG(data []byte) ([]byte, error) {
+	if bytes.HasPrefix(data, pngSignature) {
+		return data, nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// isImage reports whether data looks like an image the importers can convert.
+func isImage(data []byte) bool {
+	_, _, err := image.DecodeConfig(bytes.NewReader(data))
+	return err == nil
+}
+
+// sequenceTimes returns n timestamps one second apart, ending at the
+// modification time of path. It is used for histories that keep their order
+// but not when entries were copied; index 0 is the oldest.
+func sequenceTimes(path string, n int) []time.Time {
+	end := time.Now()
+	if info, err := os.Stat(path); err == nil {
+		end = info.ModTime()
+	}
+
+	times := make([]time.Time, n)
+	for i := range times {
+		times[i] = end.Add(-time.Duration(n-1-i) * time.Second)
+	}
+	return times
+}
+
+// firstExisting returns the first of paths that exists, or "".
+func firstExisting(paths ...string) string {
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// homePath joins elem to the user's home directory, or returns "" if it is
+// unknown.
+func homePath(elem ...string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(append([]string{home}, elem...)...)
+}
+
+// xdgPath joins elem to the XDG base directory named by env, falling back to
+// fallback under the home directory when the variable is not set.
+func xdgPath(env, fallback string, elem ...string) string {
+	base := os.Getenv(env)
+	if base == "" {
+		base = homePath(filepath.FromSlash(fallback))
+		if base == "" {
+			return ""
+		}
+	}
+	return filepath.Join(append([]string{base}, elem...)...)
+}
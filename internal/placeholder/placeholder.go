@@ -0,0 +1,98 @@
+// Package placeholder expands template placeholders such as {{date}} or
+// {{input:Ticket number}} in clipboard item content.
+package placeholder
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"clipboardpro/internal/util"
+)
+
+const (
+	DefaultDateLayout = "2006-01-02"
+	DefaultTimeLayout = "15:04:05"
+)
+
+// Env supplies the values placeholders are resolved against.
+type Env struct {
+	Now       time.Time
+	Clipboard func() string     // Returns the current clipboard text
+	Inputs    map[string]string // Values for {{input:Label}}, keyed by label
+}
+
+var pattern = regexp.MustCompile(`\{\{\s*(date|time|uuid|clipboard|input)\s*(?::([^}]*))?\}\}`)
+
+// Has reports whether content contains at least one placeholder.
+func Has(content string) bool {
+	return pattern.MatchString(content)
+}
+
+// Prompts returns the labels of all {{input:Label}} placeholders in content,
+// in order of first appearance and without duplicates.
+func Prompts(content string) []string {
+	var labels []string
+	seen := make(map[string]bool)
+	for _, match := range pattern.FindAllStringSubmatch(content, -1) {
+		if match[1] != "input" {
+			continue
+		}
+		label := inputLabel(match[2])
+		if !seen[label] {
+			seen[label] = true
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// Expand replaces every placeholder in content with its value from env.
+// Missing inputs expand to an empty string.
+func Expand(content string, env Env) string {
+	if env.Now.IsZero() {
+		env.Now = time.Now()
+	}
+
+	var clipboardText *string
+	return pattern.ReplaceAllStringFunc(content, func(token string) string {
+		match := pattern.FindStringSubmatch(token)
+		arg := strings.TrimSpace(match[2])
+
+		switch match[1] {
+		case "date":
+			if arg == "" {
+				arg = DefaultDateLayout
+			}
+			return env.Now.Format(arg)
+		case "time":
+			if arg == "" {
+				arg = DefaultTimeLayout
+			}
+			return env.Now.Format(arg)
+		case "uuid":
+			return util.NewUUID()
+		case "clipboard":
+			if clipboardText == nil {
+				text := ""
+				if env.Clipboard != nil {
+					text = env.Clipboard()
+				}
+				clipboardText = &text
+			}
+			return *clipboardText
+		case "input":
+			return env.Inputs[inputLabel(match[2])]
+		default:
+			return token
+		}
+	})
+}
+
+func inputLabel(arg string) string {
+	label := strings.TrimSpace(arg)
+	if label == "" {
+		return "Value"
+	}
+	return label
+}
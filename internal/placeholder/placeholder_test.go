@@ -0,0 +1,99 @@
+package placeholder
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestHas(t *testing.T) {
+	tests := map[string]bool{
+		"plain text":              false,
+		"{{date}}":                true,
+		"{{ time : 15:04 }}":      true,
+		"{{input:Name}}":          true,
+		"{{unknown}}":             false,
+		"{date}":                  false,
+		"func() { return {{}} }":  false,
+		"Dear {{input}}, thanks.": true,
+	}
+	for content, want := range tests {
+		if got := Has(content); got != want {
+			t.Errorf("Has(%q) = %v, want %v", content, got, want)
+		}
+	}
+}
+
+func TestPrompts(t *testing.T) {
+	tests := []struct {
+		content string
+		want    []string
+	}{
+		{"no placeholders", nil},
+		{"{{date}} {{clipboard}}", nil},
+		{"{{input:Name}} {{input: Ticket }} {{input:Name}}", []string{"Name", "Ticket"}},
+		{"{{input}} and {{input:}}", []string{"Value"}},
+	}
+	for _, tt := range tests {
+		if got := Prompts(tt.content); !slices.Equal(got, tt.want) {
+			t.Errorf("Prompts(%q) = %q, want %q", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestExpand(t *testing.T) {
+	now := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	reads := 0
+	env := Env{
+		Now: now,
+		Clipboard: func() string {
+			reads++
+			return "copied"
+		},
+		Inputs: map[string]string{"Name": "Ada", "Value": "default"},
+	}
+
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"plain", "plain"},
+		{"{{date}} {{time}}", "2026-03-04 05:06:07"},
+		{"{{date:02/01/2006}}", "04/03/2026"},
+		{"{{ time : 3:04PM }}", "5:06AM"},
+		{"Hi {{input:Name}}, {{input: Name }}!", "Hi Ada, Ada!"},
+		{"{{input}}", "default"},
+		{"[{{input:Missing}}]", "[]"},
+		{"{{clipboard}} and {{clipboard}}", "copied and copied"},
+		{"{{unknown}}", "{{unknown}}"},
+	}
+	for _, tt := range tests {
+		if got := Expand(tt.content, env); got != tt.want {
+			t.Errorf("Expand(%q) = %q, want %q", tt.content, got, tt.want)
+		}
+	}
+
+	reads = 0
+	Expand("{{clipboard}} {{clipboard}}", env)
+	if reads != 1 {
+		t.Errorf("the clipboard was read %d times, want once", reads)
+	}
+}
+
+func TestExpandUUID(t *testing.T) {
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	first, second := Expand("{{uuid}}", Env{}), Expand("{{uuid}}", Env{})
+	if !uuid.MatchString(first) {
+		t.Errorf("Expand({{uuid}}) = %q, not a version 4 UUID", first)
+	}
+	if first == second {
+		t.Error("Expand({{uuid}}) returned the same UUID twice")
+	}
+}
+
+func TestExpandWithoutClipboard(t *testing.T) {
+	if got := Expand("[{{clipboard}}]", Env{}); got != "[]" {
+		t.Errorf("Expand without a clipboard = %q, want %q", got, "[]")
+	}
+}
@@ -0,0 +1,233 @@
+// Package retention decides which history items the periodic cleanup
+// removes, based on retention rules and a storage quota.
+package retention
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"clipboardpro/internal/config"
+	"clipboardpro/internal/database"
+)
+
+// Removal is an item the cleanup would remove, and why.
+type Removal struct {
+	Item   *database.ClipboardItem
+	Reason string // One of the database.DeleteReason constants
+	Purge  bool   // Deleted for good rather than moved to the trash
+}
+
+// Plan is the outcome of evaluating the rules against the history.
+type Plan struct {
+	Removals     []Removal // Oldest first
+	RemovedBytes int64
+	Kept         int // Items outside the trash that stay, including pinned ones
+	KeptBytes    int64
+
+	// StoredBytes is the space the history, the trash and revisions
+	// included, would take in the database after the cleanup.
+	StoredBytes int64
+}
+
+// Purged returns how many of the removals are deleted for good.
+func (p *Plan) Purged() int {
+	purged := 0
+	for _, removal := range p.Removals {
+		if removal.Purge {
+			purged++
+		}
+	}
+	return purged
+}
+
+// Policy is the set of rules and the quota the cleanup enforces.
+type Policy struct {
+	Rules      []config.RetentionRule
+	QuotaBytes int64 // 0 for no quota
+}
+
+// PolicyFromConfig returns the policy configured in cfg.
+func PolicyFromConfig(cfg *config.Config) Policy {
+	return Policy{
+		Rules:      cfg.RetentionRules,
+		QuotaBytes: int64(cfg.StorageQuotaMB) * 1024 * 1024,
+	}
+}
+
+// NewPlan applies a policy to items, as loaded by GetRetentionItems and
+// ordered newest first. Rules apply to items outside the trash: pinned
+// items are never removed, and items matching no rule are only subject to
+// the quota, which is measured against StoredBytes of every item.
+//
+// Moving items to the trash frees no space, so while the history is over
+// the quota items are deleted for good instead: first those longest in the
+// trash, then those the rules remove, then the oldest unpinned items.
+func NewPlan(items []*database.ClipboardItem, policy Policy, now time.Time) *Plan {
+	plan := &Plan{}
+	counts := make([]int, len(policy.Rules))
+	sizes := make([]int64, len(policy.Rules))
+
+	var kept, trashed []*database.ClipboardItem
+	var broken []Removal // Newest first
+	for _, item := range items {
+		plan.StoredBytes += item.StoredBytes
+		if item.IsTrashed() {
+			trashed = append(trashed, item)
+			continue
+		}
+
+		size := int64(item.Size)
+		if item.Pinned {
+			kept = append(kept, item)
+			plan.KeptBytes += size
+			continue
+		}
+
+		rule := -1
+		for i, r := range policy.Rules {
+			if r.Matches(item.Type, database.SplitTags(item.Tags), item.SourceApp, item.IsSensitive()) {
+				rule = i
+				break
+			}
+		}
+
+		if rule >= 0 {
+			if reason := exceeds(policy.Rules[rule], item, counts[rule], sizes[rule], now); reason != "" {
+				broken = append(broken, Removal{Item: item, Reason: reason})
+				continue
+			}
+			counts[rule]++
+			sizes[rule] += size
+		}
+
+		kept = append(kept, item)
+		plan.KeptBytes += size
+	}
+
+	overQuota := func() bool {
+		return policy.QuotaBytes > 0 && plan.StoredBytes > policy.QuotaBytes
+	}
+
+	sort.SliceStable(trashed, func(i, j int) bool {
+		return trashed[i].DeletedAt.Before(trashed[j].DeletedAt)
+	})
+	for _, item := range trashed {
+		if !overQuota() {
+			break
+		}
+		plan.remove(Removal{Item: item, Reason: database.DeleteReasonQuota, Purge: true})
+	}
+
+	for i := len(broken) - 1; i >= 0; i-- {
+		if overQuota() {
+			broken[i].Purge = true
+		}
+		plan.remove(broken[i])
+	}
+
+	for i := len(kept) - 1; i >= 0 && overQuota(); i-- {
+		if kept[i].Pinned {
+			continue
+		}
+		plan.KeptBytes -= int64(kept[i].Size)
+		plan.remove(Removal{Item: kept[i], Reason: database.DeleteReasonQuota, Purge: true})
+		kept[i] = nil
+	}
+
+	for _, item := range kept {
+		if item != nil {
+			plan.Kept++
+		}
+	}
+
+	sort.SliceStable(plan.Removals, func(i, j int) bool {
+		return plan.Removals[i].Item.Timestamp.Before(plan.Removals[j].Item.Timestamp)
+	})
+	return plan
+}
+
+// exceeds returns the reason an item breaks a rule, given how many items
+// and bytes newer items already use, or "" if the item is kept.
+func exceeds(rule config.RetentionRule, item *database.ClipboardItem, count int, size int64, now time.Time) string {
+	switch {
+	case rule.MaxAgeDays > 0 && item.Timestamp.Before(now.AddDate(0, 0, -rule.MaxAgeDays)):
+		return database.DeleteReasonAge
+	case rule.MaxCount > 0 && count >= rule.MaxCount:
+		return database.DeleteReasonCount
+	case rule.MaxBytes > 0 && size+int64(item.Size) > rule.MaxBytes:
+		return database.DeleteReasonSize
+	}
+	return ""
+}
+
+func (p *Plan) remove(removal Removal) {
+	p.Removals = append(p.Removals, removal)
+	p.RemovedBytes += int64(removal.Item.Size)
+	if removal.Purge {
+		p.StoredBytes -= removal.Item.StoredBytes
+	}
+}
+
+// Preview returns what a cleanup with the given policy would remove now.
+func Preview(ctx context.Context, repository database.Store, policy Policy) (*Plan, error) {
+	items, err := repository.GetRetentionItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return NewPlan(items, policy, time.Now()), nil
+}
+
+// Apply moves the items of a plan to the trash, recording why, and deletes
+// those it purges for good, compacting the database afterwards.
+func Apply(ctx context.Context, repository database.Store, plan *Plan) error {
+	const batchSize = 500
+	byReason := make(map[string][]int64)
+	var reasons []string
+	var purged []int64
+	for _, removal := range plan.Removals {
+		if removal.Purge {
+			purged = append(purged, removal.Item.ID)
+			continue
+		}
+		if _, ok := byReason[removal.Reason]; !ok {
+			reasons = append(reasons, removal.Reason)
+		}
+		byReason[removal.Reason] = append(byReason[removal.Reason], removal.Item.ID)
+	}
+
+	for _, reason := range reasons {
+		ids := byReason[reason]
+		for start := 0; start < len(ids); start += batchSize {
+			if err := repository.TrashItems(ctx, ids[start:min(start+batchSize, len(ids))], reason); err != nil {
+				return fmt.Errorf("failed to clean up history: %w", err)
+			}
+		}
+	}
+
+	for start := 0; start < len(purged); start += batchSize {
+		if err := repository.DeleteItems(ctx, purged[start:min(start+batchSize, len(purged))]); err != nil {
+			return fmt.Errorf("failed to clean up history: %w", err)
+		}
+	}
+	if len(purged) > 0 {
+		if _, err := repository.RunMaintenance(ctx, database.TaskVacuum); err != nil {
+			return fmt.Errorf("failed to clean up history: %w", err)
+		}
+	}
+	return nil
+}
+
+// Cleanup removes the items that break the policy and returns how many it
+// removed.
+func Cleanup(ctx context.Context, repository database.Store, policy Policy) (int, error) {
+	plan, err := Preview(ctx, repository, policy)
+	if err != nil {
+		return 0, err
+	}
+	if err := Apply(ctx, repository, plan); err != nil {
+		return 0, err
+	}
+	return len(plan.Removals), nil
+}
@@ -0,0 +1,116 @@
+package retention
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"clipboardpro/internal/config"
+	"clipboardpro/internal/database"
+)
+
+func TestNewPlan(t *testing.T) {
+	now := time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	// item returns a live item copied days ago, storing size bytes
+	item := func(id int64, days int, size int) *database.ClipboardItem {
+		return &database.ClipboardItem{
+			ID:          id,
+			Type:        "text",
+			Size:        size,
+			StoredBytes: int64(size),
+			Timestamp:   now.AddDate(0, 0, -days),
+		}
+	}
+	pinned := func(i *database.ClipboardItem) *database.ClipboardItem {
+		i.Pinned = true
+		return i
+	}
+	tagged := func(i *database.ClipboardItem, tags string) *database.ClipboardItem {
+		i.Tags = tags
+		return i
+	}
+	trashed := func(i *database.ClipboardItem, days int) *database.ClipboardItem {
+		i.DeletedAt = now.AddDate(0, 0, -days)
+		return i
+	}
+
+	type removal struct {
+		id    int64
+		purge bool
+	}
+
+	tests := []struct {
+		name   string
+		items  []*database.ClipboardItem // Newest first
+		policy Policy
+		want   []removal // Oldest first
+	}{
+		{
+			name:   "no rules",
+			items:  []*database.ClipboardItem{item(1, 0, 10), item(2, 400, 10)},
+			policy: Policy{},
+		},
+		{
+			name:   "max age trashes older items",
+			items:  []*database.ClipboardItem{item(1, 1, 10), item(2, 10, 10), pinned(item(3, 20, 10))},
+			policy: Policy{Rules: []config.RetentionRule{{MaxAgeDays: 5}}},
+			want:   []removal{{2, false}},
+		},
+		{
+			name:   "max count keeps the newest",
+			items:  []*database.ClipboardItem{item(1, 1, 10), item(2, 2, 10), item(3, 3, 10)},
+			policy: Policy{Rules: []config.RetentionRule{{MaxCount: 1}}},
+			want:   []removal{{3, false}, {2, false}},
+		},
+		{
+			name: "first matching rule applies",
+			items: []*database.ClipboardItem{
+				tagged(item(1, 1, 10), "keep"), tagged(item(2, 10, 10), "keep"), item(3, 10, 10),
+			},
+			policy: Policy{Rules: []config.RetentionRule{{Tag: "keep"}, {MaxAgeDays: 5}}},
+			want:   []removal{{3, false}},
+		},
+		{
+			name: "sensitive rule",
+			items: []*database.ClipboardItem{
+				tagged(item(1, 2, 10), database.SensitiveTag), item(2, 2, 10),
+			},
+			policy: Policy{Rules: []config.RetentionRule{{Sensitive: true, MaxAgeDays: 1}}},
+			want:   []removal{{1, false}},
+		},
+		{
+			name: "quota purges the trash first",
+			items: []*database.ClipboardItem{
+				item(1, 1, 40), trashed(item(2, 5, 40), 1), trashed(item(3, 6, 40), 3),
+			},
+			policy: Policy{QuotaBytes: 80},
+			want:   []removal{{3, true}},
+		},
+		{
+			name: "quota purges rule removals, then the oldest items",
+			items: []*database.ClipboardItem{
+				item(1, 1, 40), pinned(item(2, 2, 40)), item(3, 3, 40), item(4, 4, 40), trashed(item(5, 5, 40), 1),
+			},
+			policy: Policy{Rules: []config.RetentionRule{{MaxCount: 2}}, QuotaBytes: 80},
+			want:   []removal{{5, true}, {4, true}, {3, true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := NewPlan(tt.items, tt.policy, now)
+
+			var got []removal
+			for _, r := range plan.Removals {
+				got = append(got, removal{r.Item.ID, r.Purge})
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("removals = %v, want %v", got, tt.want)
+			}
+			if tt.policy.QuotaBytes > 0 && plan.StoredBytes > tt.policy.QuotaBytes {
+				t.Errorf("stored bytes = %d, over the quota of %d", plan.StoredBytes, tt.policy.QuotaBytes)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package sourceapp
+
+import (
+	"context"
+	"strings"
+)
+
+// frontmost asks Launch Services for the name of the front application.
+func frontmost(ctx context.Context) (string, error) {
+	// ASN:0x0-0x1d01d:
+	asn, err := output(ctx, "lsappinfo", "front")
+	if err != nil {
+		return "", err
+	}
+
+	// "LSDisplayName"="Safari"
+	info, err := output(ctx, "lsappinfo", "info", "-only", "name", strings.TrimSpace(asn))
+	if err != nil {
+		return "", err
+	}
+	return lastQuoted(info), nil
+}
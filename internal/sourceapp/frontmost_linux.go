@@ -0,0 +1,32 @@
+package sourceapp
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// frontmost asks the X server for the class of the active window, such as
+// "firefox". Wayland compositors do not share the active window.
+func frontmost(ctx context.Context) (string, error) {
+	active, err := output(ctx, "xprop", "-root", "_NET_ACTIVE_WINDOW")
+	if err != nil {
+		return "", err
+	}
+	// _NET_ACTIVE_WINDOW(WINDOW): window id # 0x3a00007
+	fields := strings.Fields(active)
+	if len(fields) == 0 {
+		return "", errors.New("no active window")
+	}
+	id := fields[len(fields)-1]
+	if !strings.HasPrefix(id, "0x") || id == "0x0" {
+		return "", errors.New("no active window")
+	}
+
+	// WM_CLASS(STRING) = "Navigator", "firefox"
+	class, err := output(ctx, "xprop", "-id", id, "WM_CLASS")
+	if err != nil {
+		return "", err
+	}
+	return lastQuoted(class), nil
+}
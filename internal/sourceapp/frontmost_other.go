@@ -0,0 +1,12 @@
+//go:build !linux && !darwin && !windows
+
+package sourceapp
+
+import (
+	"context"
+	"errors"
+)
+
+func frontmost(context.Context) (string, error) {
+	return "", errors.New("not supported on this platform")
+}
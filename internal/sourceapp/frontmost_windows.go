@@ -0,0 +1,52 @@
+package sourceapp
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	user32                       = syscall.NewLazyDLL("user32.dll")
+	kernel32                     = syscall.NewLazyDLL("kernel32.dll")
+	procGetForegroundWindow      = user32.NewProc("GetForegroundWindow")
+	procGetWindowThreadProcessId = user32.NewProc("GetWindowThreadProcessId")
+	procQueryFullProcessImageW   = kernel32.NewProc("QueryFullProcessImageNameW")
+)
+
+const processQueryLimitedInformation = 0x1000
+
+// frontmost returns the executable name, without .exe, of the process that
+// owns the foreground window.
+func frontmost(context.Context) (string, error) {
+	window, _, _ := procGetForegroundWindow.Call()
+	if window == 0 {
+		return "", errors.New("no foreground window")
+	}
+
+	var pid uint32
+	procGetWindowThreadProcessId.Call(window, uintptr(unsafe.Pointer(&pid)))
+	if pid == 0 {
+		return "", errors.New("no foreground process")
+	}
+
+	process, err := syscall.OpenProcess(processQueryLimitedInformation, false, pid)
+	if err != nil {
+		return "", err
+	}
+	defer syscall.CloseHandle(process)
+
+	buf := make([]uint16, syscall.MAX_PATH)
+	size := uint32(len(buf))
+	ok, _, err := procQueryFullProcessImageW.Call(uintptr(process), 0,
+		uintptr(unsafe.Pointer(&buf[0])), uintptr(unsafe.Pointer(&size)))
+	if ok == 0 {
+		return "", err
+	}
+
+	name := filepath.Base(syscall.UTF16ToString(buf[:size]))
+	return strings.TrimSuffix(name, filepath.Ext(name)), nil
+}
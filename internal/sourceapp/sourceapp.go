@@ -0,0 +1,47 @@
+// Package sourceapp finds the application in the foreground, which is taken
+// to be the one new clipboard contents were copied from.
+package sourceapp
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// lookupTimeout bounds how long finding the foreground application may hold
+// up saving a clipboard item.
+const lookupTimeout = time.Second
+
+// Frontmost returns the name of the application whose window has focus, or
+// "" if it cannot be told, for example on Wayland.
+func Frontmost(ctx context.Context) string {
+	ctx, cancel := context.WithTimeout(ctx, lookupTimeout)
+	defer cancel()
+
+	name, err := frontmost(ctx)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(name)
+}
+
+// output runs a command and returns what it printed.
+func output(ctx context.Context, name string, args ...string) (string, error) {
+	out, err := exec.CommandContext(ctx, name, args...).Output()
+	return string(out), err
+}
+
+// lastQuoted returns the last double-quoted string in s, as printed by
+// xprop and lsappinfo.
+func lastQuoted(s string) string {
+	end := strings.LastIndexByte(s, '"')
+	if end <= 0 {
+		return ""
+	}
+	start := strings.LastIndexByte(s[:end], '"')
+	if start < 0 {
+		return ""
+	}
+	return s[start+1 : end]
+}
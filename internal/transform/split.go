@@ -0,0 +1,36 @@
+package transform
+
+import "strings"
+
+// Split cuts text at every separator, or at runs of whitespace if separator
+// is empty. Parts are trimmed of surrounding whitespace if trim is set, and
+// empty parts are left out if skipEmpty is set.
+func Split(text, separator string, trim, skipEmpty bool) []string {
+	var parts []string
+	switch separator {
+	case "":
+		parts = strings.Fields(text)
+	case "\n":
+		parts = strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	default:
+		parts = strings.Split(text, separator)
+	}
+
+	kept := parts[:0]
+	for _, part := range parts {
+		if trim {
+			part = strings.TrimSpace(part)
+		}
+		if skipEmpty && part == "" {
+			continue
+		}
+		kept = append(kept, part)
+	}
+	return kept
+}
+
+// UnescapeSeparator turns the escapes \n, \t and \\ in a separator typed by
+// the user into the characters they stand for.
+func UnescapeSeparator(separator string) string {
+	return strings.NewReplacer(`\\`, `\`, `\n`, "\n", `\t`, "\t").Replace(separator)
+}
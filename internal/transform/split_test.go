@@ -0,0 +1,42 @@
+package transform
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		text, separator string
+		trim, skipEmpty bool
+		want            []string
+	}{
+		{"a  b\tc\n", "", false, false, []string{"a", "b", "c"}},
+		{"a\r\nb\n\nc", "\n", false, false, []string{"a", "b", "", "c"}},
+		{"a\r\nb\n\nc", "\n", false, true, []string{"a", "b", "c"}},
+		{"a , b,, c", ",", true, false, []string{"a", "b", "", "c"}},
+		{"a , b,, c", ",", true, true, []string{"a", "b", "c"}},
+		{" , ", ",", false, true, []string{" ", " "}},
+	}
+
+	for _, tt := range tests {
+		if got := Split(tt.text, tt.separator, tt.trim, tt.skipEmpty); !slices.Equal(got, tt.want) {
+			t.Errorf("Split(%q, %q, %v, %v) = %q, want %q", tt.text, tt.separator, tt.trim, tt.skipEmpty, got, tt.want)
+		}
+	}
+}
+
+func TestUnescapeSeparator(t *testing.T) {
+	tests := map[string]string{
+		`,`:    ",",
+		`\n`:   "\n",
+		`\t|`:  "\t|",
+		`\\n`:  `\n`,
+		`a\\b`: `a\b`,
+	}
+	for in, want := range tests {
+		if got := UnescapeSeparator(in); got != want {
+			t.Errorf("UnescapeSeparator(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+// Package transform converts text, for example to base64, upper case or a
+// SHA-256 hash, for the transform menu of text items.
+package transform
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Transform is a conversion of text.
+type Transform struct {
+	Name  string
+	Apply func(text string) (string, error)
+}
+
+// Group is a set of related transforms, shown together in the menu.
+type Group struct {
+	Name       string
+	Transforms []Transform
+}
+
+// Groups lists all transforms, in menu order.
+var Groups = []Group{
+	{"Case", []Transform{
+		{"UPPER CASE", infallible(strings.ToUpper)},
+		{"lower case", infallible(strings.ToLower)},
+		{"Title Case", infallible(titleCase)},
+		{"Sentence case", infallible(sentenceCase)},
+		{"camelCase", infallible(camelCase)},
+		{"snake_case", infallible(func(s string) string { return joinWords(s, "_") })},
+		{"kebab-case", infallible(func(s string) string { return joinWords(s, "-") })},
+	}},
+	{"Encode", []Transform{
+		{"Base64 Encode", infallible(func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) })},
+		{"Base64 Decode", base64Decode},
+		{"URL Encode", infallible(url.QueryEscape)},
+		{"URL Decode", url.QueryUnescape},
+		{"Hex Dump", infallible(func(s string) string { return hex.Dump([]byte(s)) })},
+	}},
+	{"Hash", []Transform{
+		{"SHA-256", infallible(func(s string) string { return fmt.Sprintf("%x", sha256.Sum256([]byte(s))) })},
+		{"MD5", infallible(func(s string) string { return fmt.Sprintf("%x", md5.Sum([]byte(s))) })},
+	}},
+	{"Lines", []Transform{
+		{"Sort Lines", lines(func(l []string) []string { slices.Sort(l); return l })},
+		{"Remove Duplicate Lines", lines(dedupe)},
+		{"Reverse Lines", lines(func(l []string) []string { slices.Reverse(l); return l })},
+		{"Reverse Text", infallible(reverseText)},
+		{"Trim Whitespace", infallible(trim)},
+	}},
+	{"Escape", []Transform{
+		{"Escape for JSON", escapeJSON},
+		{"Escape for Shell", infallible(escapeShell)},
+		{"Escape for SQL", infallible(escapeSQL)},
+	}},
+	{"Wrap", []Transform{
+		{"Wrap in Double Quotes", infallible(func(s string) string { return `"` + s + `"` })},
+		{"Wrap in Single Quotes", infallible(func(s string) string { return "'" + s + "'" })},
+		{"Wrap in Code Fence", infallible(codeFence)},
+	}},
+}
+
+func infallible(fn func(string) string) func(string) (string, error) {
+	return func(s string) (string, error) {
+		return fn(s), nil
+	}
+}
+
+// lines applies fn to the lines of a text, keeping a trailing newline.
+func lines(fn func([]string) []string) func(string) (string, error) {
+	return func(s string) (string, error) {
+		body, newline := strings.CutSuffix(s, "\n")
+		result := strings.Join(fn(strings.Split(body, "\n")), "\n")
+		if newline {
+			result += "\n"
+		}
+		return result, nil
+	}
+}
+
+func dedupe(lines []string) []string {
+	seen := make(map[string]bool, len(lines))
+	kept := lines[:0]
+	for _, line := range lines {
+		if !seen[line] {
+			seen[line] = true
+			kept = append(kept, line)
+		}
+	}
+	return kept
+}
+
+func reverseText(s string) string {
+	runes := []rune(s)
+	slices.Reverse(runes)
+	return string(runes)
+}
+
+// trim removes surrounding blank lines and the trailing spaces of each line.
+func trim(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRightFunc(line, unicode.IsSpace)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func base64Decode(s string) (string, error) {
+	s = strings.Join(strings.Fields(s), "")
+	for _, encoding := range []*base64.Encoding{
+		base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding,
+	} {
+		if data, err := encoding.DecodeString(s); err == nil {
+			if !utf8.Valid(data) {
+				return "", errors.New("the decoded data is binary, not text")
+			}
+			return string(data), nil
+		}
+	}
+	return "", errors.New("not valid base64")
+}
+
+func escapeJSON(s string) (string, error) {
+	var out strings.Builder
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(s); err != nil {
+		return "", err
+	}
+	// Leave out the quotes and newline added by the encoder
+	encoded := strings.TrimSuffix(out.String(), "\n")
+	return encoded[1 : len(encoded)-1], nil
+}
+
+// escapeShell quotes s as a single POSIX shell word.
+func escapeShell(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// escapeSQL quotes s as an SQL string literal.
+func escapeSQL(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// codeFence wraps s in a Markdown code fence longer than any backtick run
+// inside it.
+func codeFence(s string) string {
+	fence := "```"
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+	return fence + "\n" + strings.TrimSuffix(s, "\n") + "\n" + fence
+}
+
+// words splits s into words at spaces, punctuation and case changes, so
+// "parseHTTPResponse" gives parse, HTTP and Response.
+func words(s string) []string {
+	var result []string
+	var current []rune
+	runes := []rune(s)
+	flush := func() {
+		if len(current) > 0 {
+			result = append(result, string(current))
+			current = nil
+		}
+	}
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && len(current) > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		current = append(current, r)
+	}
+	flush()
+	return result
+}
+
+func joinWords(s, separator string) string {
+	parts := words(s)
+	for i, part := range parts {
+		parts[i] = strings.ToLower(part)
+	}
+	return strings.Join(parts, separator)
+}
+
+func camelCase(s string) string {
+	parts := words(s)
+	for i, part := range parts {
+		part = strings.ToLower(part)
+		if i > 0 {
+			part = capitalize(part)
+		}
+		parts[i] = part
+	}
+	return strings.Join(parts, "")
+}
+
+func capitalize(s string) string {
+	runes := []rune(s)
+	if len(runes) > 0 {
+		runes[0] = unicode.ToUpper(runes[0])
+	}
+	return string(runes)
+}
+
+// titleCase capitalises the first letter of every word, keeping spacing.
+func titleCase(s string) string {
+	runes := []rune(strings.ToLower(s))
+	start := true
+	for i, r := range runes {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '\'' {
+			if start {
+				runes[i] = unicode.ToUpper(r)
+			}
+			start = false
+		} else {
+			start = true
+		}
+	}
+	return string(runes)
+}
+
+// sentenceCase lowers everything but the first letter of each sentence.
+func sentenceCase(s string) string {
+	runes := []rune(strings.ToLower(s))
+	start := true
+	for i, r := range runes {
+		switch {
+		case unicode.IsLetter(r):
+			if start {
+				runes[i] = unicode.ToUpper(r)
+			}
+			start = false
+		case r == '.' || r == '!' || r == '?' || r == '\n':
+			start = true
+		}
+	}
+	return string(runes)
+}
@@ -0,0 +1,78 @@
+package transform
+
+import "testing"
+
+// byName returns the transform with the given menu name.
+func byName(t *testing.T, name string) Transform {
+	t.Helper()
+	for _, group := range Groups {
+		for _, transform := range group.Transforms {
+			if transform.Name == name {
+				return transform
+			}
+		}
+	}
+	t.Fatalf("no transform named %q", name)
+	return Transform{}
+}
+
+func TestTransforms(t *testing.T) {
+	tests := []struct {
+		transform string
+		input     string
+		want      string
+		wantErr   bool
+	}{
+		{transform: "UPPER CASE", input: "Mixed ünï", want: "MIXED ÜNÏ"},
+		{transform: "Title Case", input: "the CAT's  hat", want: "The Cat's  Hat"},
+		{transform: "Sentence case", input: "HELLO. how ARE you? fine", want: "Hello. How are you? Fine"},
+		{transform: "camelCase", input: "parse HTTP response", want: "parseHttpResponse"},
+		{transform: "snake_case", input: "parseHTTPResponse", want: "parse_http_response"},
+		{transform: "kebab-case", input: "Version2Update now", want: "version2-update-now"},
+		{transform: "Base64 Encode", input: "hi?", want: "aGk/"},
+		{transform: "Base64 Decode", input: "aGk/", want: "hi?"},
+		{transform: "Base64 Decode", input: "aGk_", want: "hi?"},
+		{transform: "Base64 Decode", input: "aGV s\nbG8", want: "hello"},
+		{transform: "Base64 Decode", input: "not base64!", wantErr: true},
+		{transform: "Base64 Decode", input: "/w==", wantErr: true},
+		{transform: "URL Encode", input: "a b&c", want: "a+b%26c"},
+		{transform: "URL Decode", input: "a+b%26c", want: "a b&c"},
+		{transform: "URL Decode", input: "%zz", wantErr: true},
+		{transform: "SHA-256", input: "abc", want: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{transform: "MD5", input: "abc", want: "900150983cd24fb0d6963f7d28e17f72"},
+		{transform: "Sort Lines", input: "b\na\nc\n", want: "a\nb\nc\n"},
+		{transform: "Remove Duplicate Lines", input: "a\nb\na\nb", want: "a\nb"},
+		{transform: "Reverse Lines", input: "1\n2\n3", want: "3\n2\n1"},
+		{transform: "Reverse Text", input: "añb", want: "bña"},
+		{transform: "Trim Whitespace", input: "\n  a  \nb\t\n\n", want: "a\nb"},
+		{transform: "Escape for JSON", input: "say \"<hi>\"\n", want: `say \"<hi>\"\n`},
+		{transform: "Escape for Shell", input: "it's", want: `'it'\''s'`},
+		{transform: "Escape for SQL", input: "it's", want: "'it''s'"},
+		{transform: "Wrap in Code Fence", input: "code\n", want: "```\ncode\n```"},
+		{transform: "Wrap in Code Fence", input: "has ``` inside", want: "````\nhas ``` inside\n````"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.transform, func(t *testing.T) {
+			got, err := byName(t, tt.transform).Apply(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("%s(%q) error = %v, want error %v", tt.transform, tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("%s(%q) = %q, want %q", tt.transform, tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransformNamesAreUnique(t *testing.T) {
+	seen := map[string]bool{}
+	for _, group := range Groups {
+		for _, transform := range group.Transforms {
+			if seen[transform.Name] {
+				t.Errorf("transform %q listed twice", transform.Name)
+			}
+			seen[transform.Name] = true
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package components
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ActivityArea wraps the content of a window to notice pointer movement
+// anywhere over it, for the idle lock.
+//
+// Pointer events go to the innermost widget that handles them, so a wrapper
+// cannot receive them over buttons or list rows. Fyne does ask every object
+// under the pointer for its cursor on each move, though, which is how
+// movement is seen here.
+type ActivityArea struct {
+	widget.BaseWidget
+
+	content    fyne.CanvasObject
+	onActivity func()
+}
+
+var _ desktop.Cursorable = (*ActivityArea)(nil)
+
+func NewActivityArea(content fyne.CanvasObject, onActivity func()) *ActivityArea {
+	a := &ActivityArea{content: content, onActivity: onActivity}
+	a.ExtendBaseWidget(a)
+	return a
+}
+
+func (a *ActivityArea) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(a.content)
+}
+
+// Cursor records activity and keeps the default cursor; widgets under the
+// pointer with a cursor of their own still set it.
+func (a *ActivityArea) Cursor() desktop.Cursor {
+	a.onActivity()
+	return desktop.DefaultCursor
+}
@@ -0,0 +1,148 @@
+package components
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"clipboardpro/internal/backup"
+)
+
+// BackupsDialog lists database backups and lets the user take, restore or
+// delete them.
+type BackupsDialog struct {
+	controller  *BackupsController
+	itemList    *ItemList
+	parent      fyne.Window
+	list        *widget.List
+	statusLabel *widget.Label
+}
+
+func NewBackupsDialog(manager *backup.Manager, itemList *ItemList, onRestored, pauseMonitor, resumeMonitor func(), parent fyne.Window) *BackupsDialog {
+	bd := &BackupsDialog{
+		itemList:    itemList,
+		parent:      parent,
+		statusLabel: widget.NewLabel("Loading..."),
+	}
+
+	bd.controller = NewBackupsController(
+		manager,
+		bd.statusLabel,
+		func() { bd.list.Refresh() },
+		onRestored,
+		pauseMonitor,
+		resumeMonitor,
+		func() fyne.Window { return bd.parent },
+	)
+
+	bd.createList()
+	return bd
+}
+
+func (bd *BackupsDialog) Show() {
+	backupButton := widget.NewButtonWithIcon("Back Up Now", theme.DocumentSaveIcon(), bd.controller.BackUpNow)
+	backupButton.Importance = widget.HighImportance
+
+	info := widget.NewLabel(fmt.Sprintf("Backups are stored in %s.", bd.controller.manager.Dir()))
+	info.Wrapping = fyne.TextWrapWord
+
+	content := container.NewBorder(
+		container.NewVBox(
+			container.NewBorder(nil, nil, bd.statusLabel, backupButton),
+			info,
+			widget.NewSeparator(),
+		),
+		nil, nil, nil,
+		bd.list,
+	)
+
+	d := dialog.NewCustom("Backups", "Close", content, bd.parent)
+	d.Resize(fyne.NewSize(700, 500))
+	d.Show()
+
+	bd.controller.LoadBackups()
+}
+
+func (bd *BackupsDialog) createList() {
+	bd.list = widget.NewList(
+		func() int {
+			return len(bd.controller.GetBackups())
+		},
+		func() fyne.CanvasObject {
+			return bd.createItemTemplate()
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			bd.updateItem(id, item)
+		},
+	)
+}
+
+func (bd *BackupsDialog) createItemTemplate() fyne.CanvasObject {
+	icon := widget.NewIcon(theme.StorageIcon())
+
+	title := widget.NewLabel("")
+	title.TextStyle = fyne.TextStyle{Bold: true}
+
+	details := widget.NewLabel("")
+	details.TextStyle = fyne.TextStyle{Italic: true}
+
+	restoreButton := widget.NewButtonWithIcon("Restore", theme.HistoryIcon(), nil)
+	restoreButton.Importance = widget.LowImportance
+
+	deleteButton := widget.NewButtonWithIcon("", theme.DeleteIcon(), nil)
+	deleteButton.Importance = widget.LowImportance
+
+	return container.NewBorder(
+		nil, nil,
+		icon,
+		container.NewHBox(restoreButton, deleteButton),
+		container.NewVBox(title, container.NewHBox(details, layout.NewSpacer())),
+	)
+}
+
+func (bd *BackupsDialog) updateItem(id widget.ListItemID, obj fyne.CanvasObject) {
+	backups := bd.controller.GetBackups()
+	if id >= len(backups) {
+		return
+	}
+
+	b := backups[id]
+	row := obj.(*fyne.Container)
+	textContainer := row.Objects[0].(*fyne.Container)
+	actionContainer := row.Objects[2].(*fyne.Container)
+
+	title := textContainer.Objects[0].(*widget.Label)
+	details := textContainer.Objects[1].(*fyne.Container).Objects[0].(*widget.Label)
+	restoreButton := actionContainer.Objects[0].(*widget.Button)
+	deleteButton := actionContainer.Objects[1].(*widget.Button)
+
+	heading := b.CreatedAt.Format("January 2, 2006 15:04")
+	if b.BeforeRestore {
+		heading += " (before restore)"
+	}
+	title.SetText(heading)
+
+	contents := "Unreadable"
+	if b.Stats != nil {
+		contents = fmt.Sprintf("%d items, %d pinned", b.Stats.Items, b.Stats.Pinned)
+	}
+	details.SetText(fmt.Sprintf("%s • %s • %s", contents, bd.itemList.formatTimeAgo(b.CreatedAt), bd.itemList.formatBytes(int(b.Size))))
+
+	restoreButton.OnTapped = func() {
+		bd.controller.Restore(b)
+	}
+	if b.Stats == nil {
+		restoreButton.Disable()
+	} else {
+		restoreButton.Enable()
+	}
+
+	deleteButton.OnTapped = func() {
+		bd.controller.Delete(b)
+	}
+}
@@ -0,0 +1,162 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"clipboardpro/internal/backup"
+)
+
+type BackupsController struct {
+	manager       *backup.Manager
+	statusLabel   *widget.Label
+	backups       []*backup.Backup
+	busy          bool
+	listRefresh   func()             // Callback to refresh the backup list
+	onRestored    func()             // Callback after a backup has been restored
+	pauseMonitor  func()             // Callback to stop capturing the clipboard during a restore
+	resumeMonitor func()             // Callback to start capturing again
+	getWindow     func() fyne.Window // Callback to get the parent window
+}
+
+func NewBackupsController(manager *backup.Manager, statusLabel *widget.Label, listRefresh, onRestored, pauseMonitor, resumeMonitor func(), getWindow func() fyne.Window) *BackupsController {
+	return &BackupsController{
+		manager:       manager,
+		statusLabel:   statusLabel,
+		listRefresh:   listRefresh,
+		onRestored:    onRestored,
+		pauseMonitor:  pauseMonitor,
+		resumeMonitor: resumeMonitor,
+		getWindow:     getWindow,
+	}
+}
+
+func (bc *BackupsController) GetBackups() []*backup.Backup {
+	return bc.backups
+}
+
+// LoadBackups lists the backups with their item counts.
+func (bc *BackupsController) LoadBackups() {
+	go func() {
+		backups, err := bc.manager.List(context.Background())
+
+		fyne.Do(func() {
+			if err != nil {
+				bc.statusLabel.SetText("Error loading backups")
+				bc.showError(err)
+				return
+			}
+
+			bc.backups = backups
+			bc.listRefresh()
+
+			switch len(backups) {
+			case 0:
+				bc.statusLabel.SetText("No backups yet")
+			case 1:
+				bc.statusLabel.SetText("1 backup")
+			default:
+				bc.statusLabel.SetText(fmt.Sprintf("%d backups", len(backups)))
+			}
+		})
+	}()
+}
+
+// BackUpNow takes a backup immediately.
+func (bc *BackupsController) BackUpNow() {
+	if bc.busy {
+		return
+	}
+	bc.busy = true
+	bc.statusLabel.SetText("Backing up...")
+
+	go func() {
+		_, err := bc.manager.Create(context.Background())
+
+		fyne.Do(func() {
+			bc.busy = false
+			if err != nil {
+				bc.showError(fmt.Errorf("failed to back up history: %w", err))
+			}
+			bc.LoadBackups()
+		})
+	}()
+}
+
+// Restore replaces the history with a backup after user confirmation.
+// Clipboard monitoring is paused until the restore has finished.
+func (bc *BackupsController) Restore(b *backup.Backup) {
+	window := bc.getWindow()
+	if window == nil || bc.busy {
+		return
+	}
+
+	message := fmt.Sprintf("Replace the current history with the backup from %s?\n\n"+
+		"The current history is backed up first, so this can be undone by restoring that backup.",
+		b.CreatedAt.Format("January 2, 2006 15:04"))
+
+	dialog.ShowConfirm("Restore Backup", message, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		bc.busy = true
+		bc.statusLabel.SetText("Restoring...")
+		bc.pauseMonitor()
+
+		go func() {
+			safety, err := bc.manager.Restore(context.Background(), b)
+
+			fyne.Do(func() {
+				bc.resumeMonitor()
+				bc.busy = false
+				bc.LoadBackups()
+
+				if err != nil {
+					if errors.Is(err, backup.ErrBusy) {
+						bc.showError(err)
+					} else {
+						bc.showError(fmt.Errorf("failed to restore backup: %w", err))
+					}
+					return
+				}
+
+				bc.onRestored()
+				dialog.ShowInformation("Backup Restored",
+					fmt.Sprintf("The history was restored. The previous history was saved as %s.", safety.Name()), window)
+			})
+		}()
+	}, window)
+}
+
+// Delete removes a backup after user confirmation.
+func (bc *BackupsController) Delete(b *backup.Backup) {
+	window := bc.getWindow()
+	if window == nil {
+		return
+	}
+
+	dialog.ShowConfirm("Delete Backup",
+		fmt.Sprintf("Are you sure you want to delete the backup %s? This action cannot be undone.", b.Name()),
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+
+			if err := bc.manager.Delete(b); err != nil {
+				bc.showError(err)
+			}
+			bc.LoadBackups()
+		}, window)
+}
+
+func (bc *BackupsController) showError(err error) {
+	if window := bc.getWindow(); window != nil {
+		dialog.ShowError(err, window)
+	}
+}
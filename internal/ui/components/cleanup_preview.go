@@ -0,0 +1,90 @@
+package components
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"clipboardpro/internal/retention"
+)
+
+// CleanupPreviewDialog lists the items the next cleanup would remove under
+// the retention rules being edited.
+type CleanupPreviewDialog struct {
+	plan     *retention.Plan
+	itemList *ItemList
+	parent   fyne.Window
+}
+
+func NewCleanupPreviewDialog(plan *retention.Plan, itemList *ItemList, parent fyne.Window) *CleanupPreviewDialog {
+	return &CleanupPreviewDialog{
+		plan:     plan,
+		itemList: itemList,
+		parent:   parent,
+	}
+}
+
+func (cd *CleanupPreviewDialog) Show() {
+	plan := cd.plan
+
+	var summary string
+	if len(plan.Removals) == 0 {
+		summary = "The next cleanup would not remove anything."
+	} else {
+		purged := plan.Purged()
+		summary = fmt.Sprintf("The next cleanup would remove %d items (%s): %d moved to the trash, %d deleted for good to stay within the storage quota.",
+			len(plan.Removals), cd.itemList.formatBytes(int(plan.RemovedBytes)), len(plan.Removals)-purged, purged)
+	}
+	summaryLabel := widget.NewLabelWithStyle(summary, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	summaryLabel.Wrapping = fyne.TextWrapWord
+
+	keptLabel := widget.NewLabel(fmt.Sprintf("%d items (%s) would be kept, including pinned items. "+
+		"The history would take %s of storage, including the trash and revisions.",
+		plan.Kept, cd.itemList.formatBytes(int(plan.KeptBytes)), cd.itemList.formatBytes(int(plan.StoredBytes))))
+	keptLabel.Wrapping = fyne.TextWrapWord
+
+	list := widget.NewList(
+		func() int {
+			return len(plan.Removals)
+		},
+		func() fyne.CanvasObject {
+			title := widget.NewLabel("")
+			title.TextStyle = fyne.TextStyle{Bold: true}
+			title.Truncation = fyne.TextTruncateEllipsis
+
+			details := widget.NewLabel("")
+			details.TextStyle = fyne.TextStyle{Italic: true}
+
+			return container.NewBorder(nil, nil, widget.NewIcon(theme.DocumentIcon()), nil,
+				container.NewVBox(title, details))
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			removal := plan.Removals[id]
+			reason := removal.Reason
+			if removal.Purge {
+				reason += " (deleted for good)"
+			}
+			row := obj.(*fyne.Container)
+			text := row.Objects[0].(*fyne.Container)
+
+			row.Objects[1].(*widget.Icon).SetResource(cd.itemList.getItemIcon(removal.Item))
+			text.Objects[0].(*widget.Label).SetText(cd.itemList.getItemTitle(removal.Item))
+			text.Objects[1].(*widget.Label).SetText(fmt.Sprintf("%s • %s • %s", reason,
+				cd.itemList.formatTimeAgo(removal.Item.Timestamp), cd.itemList.formatBytes(removal.Item.Size)))
+		},
+	)
+
+	content := container.NewBorder(
+		container.NewVBox(summaryLabel, keptLabel, widget.NewSeparator()),
+		nil, nil, nil,
+		list,
+	)
+
+	d := dialog.NewCustom("Cleanup Preview", "Close", content, cd.parent)
+	d.Resize(fyne.NewSize(650, 450))
+	d.Show()
+}
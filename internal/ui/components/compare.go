@@ -0,0 +1,186 @@
+package components
+
+import (
+	"context"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"clipboardpro/internal/database"
+	"clipboardpro/internal/diff"
+)
+
+// unifiedContext is the number of unchanged lines around each change in a
+// unified diff.
+const unifiedContext = 3
+
+// CompareDialog shows the differences between a text item and another item
+// chosen from the list, and can save them as a unified diff.
+type CompareDialog struct {
+	itemList   *ItemList
+	item       *database.ClipboardItem // List row of the item compared
+	candidates []*database.ClipboardItem
+	parent     fyne.Window
+
+	old, new *database.ClipboardItem // Loaded in full, the older one first
+
+	otherSelect      *widget.Select
+	granularity      *widget.RadioGroup
+	ignoreWhitespace *widget.Check
+	diffText         *widget.RichText
+	statusLabel      *widget.Label
+	saveButton       *widget.Button
+}
+
+func NewCompareDialog(itemList *ItemList, item *database.ClipboardItem, parent fyne.Window) *CompareDialog {
+	cd := &CompareDialog{
+		itemList:    itemList,
+		item:        item,
+		parent:      parent,
+		diffText:    newDiffText(nil),
+		statusLabel: widget.NewLabel("Choose an item to compare with"),
+	}
+
+	var labels []string
+	for _, candidate := range itemList.controller.GetItems() {
+		if candidate.Type == "text" && candidate.ID != item.ID {
+			cd.candidates = append(cd.candidates, candidate)
+			labels = append(labels, cd.candidateLabel(candidate))
+		}
+	}
+
+	cd.otherSelect = widget.NewSelect(labels, func(string) {
+		if index := cd.otherSelect.SelectedIndex(); index >= 0 {
+			cd.load(cd.candidates[index])
+		}
+	})
+	cd.otherSelect.PlaceHolder = "Select an item…"
+
+	cd.granularity = widget.NewRadioGroup([]string{"Lines", "Words"}, func(string) { cd.render() })
+	cd.granularity.Horizontal = true
+	cd.granularity.Required = true
+	cd.granularity.SetSelected("Lines")
+
+	cd.ignoreWhitespace = widget.NewCheck("Ignore whitespace", func(bool) { cd.render() })
+
+	cd.saveButton = widget.NewButtonWithIcon("Save Unified Diff as New Item", theme.DocumentSaveIcon(), cd.saveUnified)
+	cd.saveButton.Disable()
+
+	return cd
+}
+
+func (cd *CompareDialog) candidateLabel(item *database.ClipboardItem) string {
+	return fmt.Sprintf("%s (%s)", cd.itemList.getItemTitle(item), cd.itemList.formatTimeAgo(item.Timestamp))
+}
+
+func (cd *CompareDialog) Show() {
+	if len(cd.candidates) == 0 {
+		dialog.ShowInformation("Compare", "There are no other text items in the list to compare with.", cd.parent)
+		return
+	}
+
+	swapButton := widget.NewButtonWithIcon("Swap", theme.ViewRefreshIcon(), func() {
+		if cd.old != nil {
+			cd.old, cd.new = cd.new, cd.old
+			cd.render()
+		}
+	})
+
+	content := container.NewBorder(
+		container.NewVBox(
+			container.NewBorder(nil, nil, widget.NewLabel("Compare with:"), nil, cd.otherSelect),
+			container.NewHBox(cd.granularity, cd.ignoreWhitespace, swapButton),
+			cd.statusLabel,
+			widget.NewSeparator(),
+		),
+		container.NewHBox(cd.saveButton),
+		nil, nil,
+		container.NewScroll(cd.diffText),
+	)
+
+	d := dialog.NewCustom("Compare: "+cd.itemList.getItemTitle(cd.item), "Close", content, cd.parent)
+	d.Resize(fyne.NewSize(800, 550))
+	d.Show()
+
+	// Items copied around the same time are the likeliest to be compared
+	cd.otherSelect.SetSelectedIndex(0)
+}
+
+// load reads both items in full and shows their differences, older first.
+func (cd *CompareDialog) load(other *database.ClipboardItem) {
+	cd.statusLabel.SetText("Loading...")
+	cd.saveButton.Disable()
+
+	go func() {
+		ctx := context.Background()
+		repository := cd.itemList.controller.repository
+		item, err := repository.GetItemByID(ctx, cd.item.ID)
+		var otherItem *database.ClipboardItem
+		if err == nil {
+			otherItem, err = repository.GetItemByID(ctx, other.ID)
+		}
+
+		fyne.Do(func() {
+			if err != nil {
+				cd.statusLabel.SetText("Error loading items")
+				dialog.ShowError(fmt.Errorf("failed to load items: %w", err), cd.parent)
+				return
+			}
+
+			cd.old, cd.new = otherItem, item
+			if item.Timestamp.Before(otherItem.Timestamp) {
+				cd.old, cd.new = item, otherItem
+			}
+			cd.render()
+		})
+	}()
+}
+
+func (cd *CompareDialog) options() diff.Options {
+	return diff.Options{
+		Words:            cd.granularity.Selected == "Words",
+		IgnoreWhitespace: cd.ignoreWhitespace.Checked,
+	}
+}
+
+func (cd *CompareDialog) render() {
+	if cd.old == nil {
+		return
+	}
+
+	opts := cd.options()
+	ops := diff.Compare(cd.old.Content, cd.new.Content, opts)
+	if opts.Words {
+		setWordDiffSegments(cd.diffText, ops)
+	} else {
+		setDiffSegments(cd.diffText, ops)
+	}
+
+	if diff.HasChanges(ops) {
+		cd.statusLabel.SetText(fmt.Sprintf("Changes from %s to %s", cd.candidateLabel(cd.old), cd.candidateLabel(cd.new)))
+		cd.saveButton.Enable()
+	} else {
+		cd.statusLabel.SetText("The items do not differ")
+		cd.saveButton.Disable()
+	}
+}
+
+// saveUnified saves the line differences in unified diff format as a new
+// item, for pasting into a review or a patch.
+func (cd *CompareDialog) saveUnified() {
+	if cd.old == nil {
+		return
+	}
+
+	unified := diff.Unified(cd.itemList.getItemTitle(cd.old), cd.itemList.getItemTitle(cd.new),
+		cd.old.Content, cd.new.Content, unifiedContext, cd.options())
+	if unified == "" {
+		dialog.ShowInformation("Compare", "The items do not differ line by line.", cd.parent)
+		return
+	}
+	cd.itemList.controller.SaveNewItem(unified)
+}
@@ -0,0 +1,152 @@
+package components
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"clipboardpro/internal/classify"
+	"clipboardpro/internal/database"
+)
+
+// ItemDetailDialog shows the full content of an item together with its
+// Markdown notes, which can be edited and previewed.
+type ItemDetailDialog struct {
+	controller *ItemListController
+	itemList   *ItemList
+	itemID     int64
+	parent     fyne.Window
+}
+
+func NewItemDetailDialog(itemList *ItemList, itemID int64, parent fyne.Window) *ItemDetailDialog {
+	return &ItemDetailDialog{
+		controller: itemList.controller,
+		itemList:   itemList,
+		itemID:     itemID,
+		parent:     parent,
+	}
+}
+
+func (dd *ItemDetailDialog) Show() {
+	go func() {
+		ctx := context.Background()
+		item, err := dd.controller.repository.GetItemByID(ctx, dd.itemID)
+
+		fyne.Do(func() {
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("failed to load item: %w", err), dd.parent)
+				return
+			}
+			dd.show(item)
+		})
+	}()
+}
+
+func (dd *ItemDetailDialog) show(item *database.ClipboardItem) {
+	title := widget.NewLabelWithStyle(dd.itemList.getItemTitle(item), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	title.Truncation = fyne.TextTruncateEllipsis
+
+	details := fmt.Sprintf("Copied %s • %s", dd.itemList.formatTimeAgo(item.Timestamp), dd.itemList.formatBytes(item.Size))
+	if item.SourceApp != "" {
+		details = fmt.Sprintf("Copied from %s %s • %s", item.SourceApp,
+			dd.itemList.formatTimeAgo(item.Timestamp), dd.itemList.formatBytes(item.Size))
+	}
+	if item.Subtype != "" {
+		details = classify.Label(item.Subtype, item.Language) + " • " + details
+	}
+	info := widget.NewLabel(details)
+	info.TextStyle = fyne.TextStyle{Italic: true}
+
+	header := container.NewVBox(title, info)
+	if actions := dd.itemList.createSmartActionButtons(item); actions != nil {
+		header.Add(actions)
+	}
+
+	tabs := container.NewAppTabs(
+		container.NewTabItemWithIcon("Content", theme.DocumentIcon(), dd.createContentView(item)),
+		container.NewTabItemWithIcon("Notes", theme.DocumentCreateIcon(), dd.createNotesView(item)),
+	)
+	if item.Notes != "" {
+		tabs.SelectIndex(1)
+	}
+
+	content := container.NewBorder(
+		header,
+		nil, nil, nil,
+		tabs,
+	)
+
+	d := dialog.NewCustom("Item Details", "Close", content, dd.parent)
+	d.Resize(fyne.NewSize(700, 550))
+	d.Show()
+}
+
+func (dd *ItemDetailDialog) createContentView(item *database.ClipboardItem) fyne.CanvasObject {
+	if item.Type == "image" && len(item.ImageData) > 0 {
+		image := canvas.NewImageFromReader(bytes.NewReader(item.ImageData), "clipboard.png")
+		image.FillMode = canvas.ImageFillContain
+		image.SetMinSize(fyne.NewSize(300, 300))
+		return image
+	}
+
+	content := widget.NewLabel(item.Content)
+	content.Wrapping = fyne.TextWrapWord
+	content.TextStyle = fyne.TextStyle{Monospace: true}
+	return container.NewScroll(content)
+}
+
+func (dd *ItemDetailDialog) createNotesView(item *database.ClipboardItem) fyne.CanvasObject {
+	savedNotes := item.Notes
+
+	editor := widget.NewMultiLineEntry()
+	editor.SetText(item.Notes)
+	editor.Wrapping = fyne.TextWrapWord
+	editor.SetPlaceHolder("Why did you keep this? Notes support Markdown.")
+
+	preview := widget.NewRichTextFromMarkdown(item.Notes)
+	preview.Wrapping = fyne.TextWrapWord
+
+	modes := container.NewAppTabs(
+		container.NewTabItem("Edit", editor),
+		container.NewTabItem("Preview", container.NewScroll(preview)),
+	)
+	modes.OnSelected = func(tab *container.TabItem) {
+		if tab.Text == "Preview" {
+			preview.ParseMarkdown(editor.Text)
+		}
+	}
+
+	saveButton := widget.NewButtonWithIcon("Save Notes", theme.DocumentSaveIcon(), nil)
+	saveButton.Importance = widget.HighImportance
+	saveButton.Disable()
+
+	editor.OnChanged = func(text string) {
+		if text == savedNotes {
+			saveButton.Disable()
+		} else {
+			saveButton.Enable()
+		}
+	}
+
+	saveButton.OnTapped = func() {
+		oldNotes, newNotes := savedNotes, editor.Text
+		saveButton.Disable()
+		dd.controller.SaveNotes(item.ID, oldNotes, newNotes, func() {
+			savedNotes = newNotes
+		})
+	}
+
+	return container.NewBorder(
+		nil,
+		container.NewHBox(saveButton),
+		nil, nil,
+		modes,
+	)
+}
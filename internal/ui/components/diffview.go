@@ -0,0 +1,94 @@
+package components
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"clipboardpro/internal/diff"
+)
+
+// newDiffText renders a line diff as monospaced rich text, colouring
+// insertions and deletions.
+func newDiffText(ops []diff.Op) *widget.RichText {
+	text := widget.NewRichText()
+	text.Wrapping = fyne.TextWrapWord
+	setDiffSegments(text, ops)
+	return text
+}
+
+func setDiffSegments(text *widget.RichText, ops []diff.Op) {
+	var segments []widget.RichTextSegment
+	for _, op := range ops {
+		prefix, color := "  ", theme.ColorNameForeground
+		switch op.Kind {
+		case diff.Insert:
+			prefix, color = "+ ", theme.ColorNameSuccess
+		case diff.Delete:
+			prefix, color = "- ", theme.ColorNameError
+		}
+
+		for _, line := range strings.SplitAfter(strings.TrimSuffix(op.Text, "\n"), "\n") {
+			segments = append(segments, &widget.TextSegment{
+				Text: prefix + strings.TrimSuffix(line, "\n"),
+				Style: widget.RichTextStyle{
+					ColorName: color,
+					TextStyle: fyne.TextStyle{Monospace: true},
+				},
+			})
+		}
+	}
+
+	if len(segments) == 0 {
+		segments = append(segments, &widget.TextSegment{
+			Text:  "No differences",
+			Style: widget.RichTextStyle{TextStyle: fyne.TextStyle{Italic: true}},
+		})
+	}
+
+	text.Segments = segments
+	text.Refresh()
+}
+
+// setWordDiffSegments renders a word diff as running text, colouring
+// insertions and deletions in place.
+func setWordDiffSegments(text *widget.RichText, ops []diff.Op) {
+	var segments []widget.RichTextSegment
+	for _, op := range ops {
+		style := widget.RichTextStyle{
+			Inline:    true,
+			ColorName: theme.ColorNameForeground,
+			TextStyle: fyne.TextStyle{Monospace: true},
+		}
+		switch op.Kind {
+		case diff.Insert:
+			style.ColorName = theme.ColorNameSuccess
+			style.TextStyle.Bold = true
+		case diff.Delete:
+			style.ColorName = theme.ColorNameError
+			style.TextStyle.Underline = true
+		}
+
+		// Each line is its own segment, ending the paragraph at line breaks
+		for _, line := range strings.SplitAfter(op.Text, "\n") {
+			if line == "" {
+				continue
+			}
+			lineStyle := style
+			lineStyle.Inline = !strings.HasSuffix(line, "\n")
+			segments = append(segments, &widget.TextSegment{Text: strings.TrimSuffix(line, "\n"), Style: lineStyle})
+		}
+	}
+
+	if !diff.HasChanges(ops) {
+		segments = []widget.RichTextSegment{&widget.TextSegment{
+			Text:  "No differences",
+			Style: widget.RichTextStyle{TextStyle: fyne.TextStyle{Italic: true}},
+		}}
+	}
+
+	text.Segments = segments
+	text.Refresh()
+}
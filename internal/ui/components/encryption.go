@@ -0,0 +1,126 @@
+package components
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"clipboardpro/internal/backup"
+	"clipboardpro/internal/database"
+)
+
+// EncryptionDialog turns encryption of the history on or off and changes
+// its passphrase.
+type EncryptionDialog struct {
+	controller *EncryptionController
+	parent     fyne.Window
+	dialog     dialog.Dialog
+}
+
+func NewEncryptionDialog(repository database.Store, backups *backup.Manager, onChanged, pauseMonitor, resumeMonitor func(), parent fyne.Window) *EncryptionDialog {
+	ed := &EncryptionDialog{parent: parent}
+	ed.controller = NewEncryptionController(
+		repository,
+		backups,
+		onChanged,
+		pauseMonitor,
+		resumeMonitor,
+		func() fyne.Window { return ed.parent },
+	)
+	return ed
+}
+
+func (ed *EncryptionDialog) Show() {
+	var status *widget.Label
+	var buttons *fyne.Container
+
+	if ed.controller.IsEncrypted() {
+		status = widget.NewLabel("Your clipboard history is encrypted with a passphrase.")
+		changeButton := widget.NewButtonWithIcon("Change Passphrase...", theme.AccountIcon(), ed.showChangePassphrase)
+		disableButton := widget.NewButtonWithIcon("Turn Off Encryption...", theme.CancelIcon(), ed.showDisable)
+		buttons = container.NewHBox(changeButton, disableButton)
+	} else {
+		status = widget.NewLabel("Your clipboard history is not encrypted.")
+		enableButton := widget.NewButtonWithIcon("Encrypt History...", theme.AccountIcon(), ed.showEnable)
+		enableButton.Importance = widget.HighImportance
+		buttons = container.NewHBox(enableButton)
+	}
+	status.TextStyle = fyne.TextStyle{Bold: true}
+
+	info := widget.NewLabel("Encryption protects item contents, titles, notes, tags and images with a key derived from your passphrase. " +
+		"You will be asked for the passphrase each time ClipBoard Pro starts. " +
+		"Backups taken before encryption was turned on stay readable without the passphrase, so you are offered to delete them.")
+	info.Wrapping = fyne.TextWrapWord
+
+	content := container.NewVBox(status, info, buttons)
+
+	ed.dialog = dialog.NewCustom("Encryption", "Close", content, ed.parent)
+	ed.dialog.Resize(fyne.NewSize(500, content.MinSize().Height+100))
+	ed.dialog.Show()
+}
+
+func (ed *EncryptionDialog) showEnable() {
+	passphraseEntry := widget.NewPasswordEntry()
+	confirmEntry := widget.NewPasswordEntry()
+
+	warning := widget.NewLabel("If you forget the passphrase, your history cannot be recovered.")
+	warning.Wrapping = fyne.TextWrapWord
+	warning.Importance = widget.WarningImportance
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Passphrase", passphraseEntry),
+		widget.NewFormItem("Confirm passphrase", confirmEntry),
+		widget.NewFormItem("", warning),
+	}
+
+	ed.showForm("Encrypt History", "Encrypt", items, func() {
+		ed.controller.Enable(passphraseEntry.Text, confirmEntry.Text)
+	})
+}
+
+func (ed *EncryptionDialog) showChangePassphrase() {
+	currentEntry := widget.NewPasswordEntry()
+	passphraseEntry := widget.NewPasswordEntry()
+	confirmEntry := widget.NewPasswordEntry()
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Current passphrase", currentEntry),
+		widget.NewFormItem("New passphrase", passphraseEntry),
+		widget.NewFormItem("Confirm new passphrase", confirmEntry),
+	}
+
+	ed.showForm("Change Passphrase", "Change", items, func() {
+		ed.controller.ChangePassphrase(currentEntry.Text, passphraseEntry.Text, confirmEntry.Text)
+	})
+}
+
+func (ed *EncryptionDialog) showDisable() {
+	currentEntry := widget.NewPasswordEntry()
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Current passphrase", currentEntry),
+		widget.NewFormItem("", widget.NewLabel("Your history will be stored unencrypted.")),
+	}
+
+	ed.showForm("Turn Off Encryption", "Turn Off", items, func() {
+		ed.controller.Disable(currentEntry.Text)
+	})
+}
+
+// showForm closes the encryption dialog, whose contents are about to
+// change, and shows a passphrase form in its place.
+func (ed *EncryptionDialog) showForm(title, confirm string, items []*widget.FormItem, onConfirm func()) {
+	if ed.dialog != nil {
+		ed.dialog.Hide()
+	}
+
+	form := dialog.NewForm(title, confirm, "Cancel", items, func(confirmed bool) {
+		if confirmed {
+			onConfirm()
+		}
+	}, ed.parent)
+	form.Resize(fyne.NewSize(450, form.MinSize().Height))
+	form.Show()
+}
@@ -0,0 +1,215 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"clipboardpro/internal/backup"
+	"clipboardpro/internal/database"
+	"clipboardpro/internal/vault"
+)
+
+type EncryptionController struct {
+	repository    database.Store
+	backups       *backup.Manager // Nil when backups are off
+	busy          bool
+	onChanged     func()             // Callback after the history has been re-encrypted
+	pauseMonitor  func()             // Callback to stop capturing the clipboard while items are rewritten
+	resumeMonitor func()             // Callback to start capturing again
+	getWindow     func() fyne.Window // Callback to get the parent window
+}
+
+func NewEncryptionController(repository database.Store, backups *backup.Manager, onChanged, pauseMonitor, resumeMonitor func(), getWindow func() fyne.Window) *EncryptionController {
+	return &EncryptionController{
+		repository:    repository,
+		backups:       backups,
+		onChanged:     onChanged,
+		pauseMonitor:  pauseMonitor,
+		resumeMonitor: resumeMonitor,
+		getWindow:     getWindow,
+	}
+}
+
+func (ec *EncryptionController) IsEncrypted() bool {
+	return ec.repository.IsEncrypted()
+}
+
+// Enable encrypts the history with a new passphrase.
+func (ec *EncryptionController) Enable(passphrase, confirmation string) {
+	if err := checkNewPassphrase(passphrase, confirmation); err != nil {
+		ec.showError(err)
+		return
+	}
+
+	ec.run("Encrypting history...",
+		func(ctx context.Context) error {
+			return ec.repository.EnableEncryption(ctx, passphrase)
+		},
+		ec.offerBackupCleanup)
+}
+
+// encryptedText confirms that encryption was turned on.
+const encryptedText = "Your clipboard history is now encrypted. You will be asked for the passphrase when ClipBoard Pro starts."
+
+// offerBackupCleanup confirms that encryption is on and offers to delete the
+// backups taken before, which can still be read without the passphrase.
+func (ec *EncryptionController) offerBackupCleanup() {
+	if ec.backups == nil {
+		ec.showDone("History Encrypted", encryptedText)()
+		return
+	}
+
+	go func() {
+		backups, err := ec.backups.Unencrypted(context.Background())
+
+		fyne.Do(func() {
+			window := ec.getWindow()
+			if window == nil {
+				return
+			}
+
+			if err != nil {
+				log.Printf("Failed to check backups: %v", err)
+				dialog.ShowInformation("History Encrypted", encryptedText+
+					"\n\nYour backups could not be checked. Backups taken before encryption was turned on are not encrypted.", window)
+				return
+			}
+			if len(backups) == 0 {
+				dialog.ShowInformation("History Encrypted", encryptedText, window)
+				return
+			}
+
+			unencrypted := fmt.Sprintf("%d backups taken before encryption was turned on are", len(backups))
+			if len(backups) == 1 {
+				unencrypted = "1 backup taken before encryption was turned on is"
+			}
+			message := fmt.Sprintf("%s\n\n%s not encrypted and can be read without the passphrase. "+
+				"Delete the unencrypted backups? An encrypted backup is taken first.", encryptedText, unencrypted)
+			confirm := dialog.NewConfirm("History Encrypted", message, func(confirmed bool) {
+				if confirmed {
+					ec.deleteBackups(backups)
+				}
+			}, window)
+			confirm.SetConfirmText("Delete")
+			confirm.SetDismissText("Keep")
+			confirm.Show()
+		})
+	}()
+}
+
+// deleteBackups replaces unencrypted backups with a new, encrypted one.
+func (ec *EncryptionController) deleteBackups(backups []*backup.Backup) {
+	go func() {
+		_, err := ec.backups.Create(context.Background())
+		if err != nil {
+			err = fmt.Errorf("failed to take an encrypted backup, so the unencrypted ones were kept: %w", err)
+		}
+		for _, b := range backups {
+			if err != nil {
+				break
+			}
+			err = ec.backups.Delete(b)
+		}
+
+		fyne.Do(func() {
+			if err != nil {
+				ec.showError(err)
+				return
+			}
+			if window := ec.getWindow(); window != nil {
+				dialog.ShowInformation("Backups Deleted", "The unencrypted backups were deleted.", window)
+			}
+		})
+	}()
+}
+
+// ChangePassphrase re-encrypts the history with a new passphrase.
+func (ec *EncryptionController) ChangePassphrase(current, passphrase, confirmation string) {
+	if err := checkNewPassphrase(passphrase, confirmation); err != nil {
+		ec.showError(err)
+		return
+	}
+
+	ec.run("Changing passphrase...",
+		func(ctx context.Context) error {
+			return ec.repository.ChangePassphrase(ctx, current, passphrase)
+		},
+		ec.showDone("Passphrase Changed", "Your clipboard history is now encrypted with the new passphrase."))
+}
+
+// Disable decrypts the history and turns encryption off.
+func (ec *EncryptionController) Disable(current string) {
+	ec.run("Decrypting history...",
+		func(ctx context.Context) error {
+			return ec.repository.DisableEncryption(ctx, current)
+		},
+		ec.showDone("Encryption Turned Off", "Your clipboard history is no longer encrypted."))
+}
+
+// run rewrites the history in the background with clipboard monitoring
+// paused, showing progress until it is done, and then calls done.
+func (ec *EncryptionController) run(progressText string, rewrite func(context.Context) error, done func()) {
+	window := ec.getWindow()
+	if window == nil || ec.busy {
+		return
+	}
+
+	ec.busy = true
+	ec.pauseMonitor()
+
+	progress := dialog.NewCustomWithoutButtons(progressText, widget.NewProgressBarInfinite(), window)
+	progress.Show()
+
+	go func() {
+		err := rewrite(context.Background())
+
+		fyne.Do(func() {
+			progress.Hide()
+			ec.resumeMonitor()
+			ec.busy = false
+
+			if errors.Is(err, vault.ErrWrongPassphrase) {
+				ec.showError(errors.New("the current passphrase is wrong"))
+				return
+			}
+			if err != nil {
+				ec.showError(err)
+				return
+			}
+
+			ec.onChanged()
+			done()
+		})
+	}()
+}
+
+// showDone returns a func that tells the user a rewrite succeeded.
+func (ec *EncryptionController) showDone(title, text string) func() {
+	return func() {
+		if window := ec.getWindow(); window != nil {
+			dialog.ShowInformation(title, text, window)
+		}
+	}
+}
+
+func (ec *EncryptionController) showError(err error) {
+	if window := ec.getWindow(); window != nil {
+		dialog.ShowError(err, window)
+	}
+}
+
+func checkNewPassphrase(passphrase, confirmation string) error {
+	if passphrase == "" {
+		return errors.New("the passphrase must not be empty")
+	}
+	if passphrase != confirmation {
+		return errors.New("the passphrases do not match")
+	}
+	return nil
+}
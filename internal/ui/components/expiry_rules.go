@@ -0,0 +1,143 @@
+package components
+
+import (
+	"fmt"
+	"regexp"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"clipboardpro/internal/config"
+)
+
+// ExpiryRulesEditor edits the list of expiry rules in the settings.
+type ExpiryRulesEditor struct {
+	rows      []*expiryRuleRow
+	container *fyne.Container
+}
+
+type expiryRuleRow struct {
+	typeSelect   *widget.Select
+	patternEntry *widget.Entry
+	expireEntry  *widget.Entry
+	burnCheck    *widget.Check
+}
+
+func NewExpiryRulesEditor(rules []config.ExpiryRule) *ExpiryRulesEditor {
+	ee := &ExpiryRulesEditor{container: container.NewVBox()}
+	for _, rule := range rules {
+		ee.rows = append(ee.rows, newExpiryRuleRow(rule))
+	}
+	ee.refresh()
+	return ee
+}
+
+func (ee *ExpiryRulesEditor) Create() fyne.CanvasObject {
+	addButton := widget.NewButtonWithIcon("Add Rule", theme.ContentAddIcon(), func() {
+		ee.rows = append(ee.rows, newExpiryRuleRow(config.ExpiryRule{}))
+		ee.refresh()
+	})
+	addButton.Importance = widget.LowImportance
+
+	return container.NewVBox(ee.container, container.NewHBox(addButton))
+}
+
+// Rules returns the rules as currently entered.
+func (ee *ExpiryRulesEditor) Rules() ([]config.ExpiryRule, error) {
+	rules := make([]config.ExpiryRule, 0, len(ee.rows))
+	for i, row := range ee.rows {
+		rule, err := row.rule()
+		if err != nil {
+			return nil, fmt.Errorf("expiry rule %d: %w", i+1, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (ee *ExpiryRulesEditor) refresh() {
+	grid := container.NewGridWithColumns(5,
+		widget.NewLabelWithStyle("Type", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle("Text pattern", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle("Expire after (min)", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle("After copy", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabel(""),
+	)
+
+	for _, row := range ee.rows {
+		removeButton := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+			for i, r := range ee.rows {
+				if r == row {
+					ee.rows = append(ee.rows[:i], ee.rows[i+1:]...)
+					break
+				}
+			}
+			ee.refresh()
+		})
+		removeButton.Importance = widget.LowImportance
+
+		grid.Add(row.typeSelect)
+		grid.Add(row.patternEntry)
+		grid.Add(row.expireEntry)
+		grid.Add(row.burnCheck)
+		grid.Add(container.NewHBox(removeButton))
+	}
+
+	if len(ee.rows) == 0 {
+		ee.container.Objects = []fyne.CanvasObject{widget.NewLabel("No rules: items only expire when set from their menu.")}
+	} else {
+		ee.container.Objects = []fyne.CanvasObject{grid}
+	}
+	ee.container.Refresh()
+}
+
+func newExpiryRuleRow(rule config.ExpiryRule) *expiryRuleRow {
+	labels := make([]string, len(ruleTypes))
+	selected := ruleTypes[0].label
+	for i, t := range ruleTypes {
+		labels[i] = t.label
+		if t.itemType == rule.Type {
+			selected = t.label
+		}
+	}
+
+	row := &expiryRuleRow{
+		typeSelect:   widget.NewSelect(labels, nil),
+		patternEntry: widget.NewEntry(),
+		expireEntry:  newLimitEntry(rule.ExpireMinutes),
+		burnCheck:    widget.NewCheck("Delete", nil),
+	}
+	row.typeSelect.SetSelected(selected)
+	row.patternEntry.SetPlaceHolder("Any text")
+	row.patternEntry.SetText(rule.Pattern)
+	row.patternEntry.Validator = func(text string) error {
+		_, err := regexp.Compile(text)
+		return err
+	}
+	row.expireEntry.SetPlaceHolder("Never")
+	row.burnCheck.SetChecked(rule.BurnAfterCopy)
+	return row
+}
+
+func (row *expiryRuleRow) rule() (config.ExpiryRule, error) {
+	var rule config.ExpiryRule
+	for _, t := range ruleTypes {
+		if t.label == row.typeSelect.Selected {
+			rule.Type = t.itemType
+		}
+	}
+
+	if _, err := regexp.Compile(row.patternEntry.Text); err != nil {
+		return rule, fmt.Errorf("text pattern: %w", err)
+	}
+	rule.Pattern = row.patternEntry.Text
+
+	var err error
+	if rule.ExpireMinutes, err = parseLimit(row.expireEntry.Text); err != nil {
+		return rule, fmt.Errorf("expire after: %w", err)
+	}
+	rule.BurnAfterCopy = row.burnCheck.Checked
+	return rule, nil
+}
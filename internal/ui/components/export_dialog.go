@@ -0,0 +1,218 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"clipboardpro/internal/database"
+	"clipboardpro/internal/export"
+)
+
+const exportDateLayout = "2006-01-02"
+
+var (
+	exportFormatLabels = map[export.Format]string{
+		export.FormatJSONL:    "JSON Lines",
+		export.FormatCSV:      "CSV",
+		export.FormatMarkdown: "Markdown",
+		export.FormatZIP:      "ZIP archive with images",
+	}
+	exportImageLabels = map[export.ImageMode]string{
+		export.ImagesInline: "Embed as base64",
+		export.ImagesFiles:  "Save as separate files",
+		export.ImagesNone:   "Leave out",
+	}
+	exportTypeLabels = []string{"All items", "Text only", "Images only"}
+)
+
+// ExportDialog asks for the export format and filters, then for a file to
+// write the export to.
+type ExportDialog struct {
+	repository database.Store
+	parent     fyne.Window
+
+	formatSelect *widget.Select
+	imagesSelect *widget.Select
+	fromEntry    *widget.Entry
+	toEntry      *widget.Entry
+	typeSelect   *widget.Select
+	tagsEntry    *widget.Entry
+	pinnedCheck  *widget.Check
+
+	sensitiveCheck *widget.Check // Sensitive items are left out unless checked
+}
+
+func NewExportDialog(repository database.Store, parent fyne.Window) *ExportDialog {
+	return &ExportDialog{
+		repository: repository,
+		parent:     parent,
+	}
+}
+
+func (ed *ExportDialog) Show() {
+	formats := make([]string, len(export.Formats))
+	for i, f := range export.Formats {
+		formats[i] = exportFormatLabels[f]
+	}
+
+	ed.imagesSelect = widget.NewSelect([]string{
+		exportImageLabels[export.ImagesInline],
+		exportImageLabels[export.ImagesFiles],
+		exportImageLabels[export.ImagesNone],
+	}, nil)
+	ed.imagesSelect.SetSelected(exportImageLabels[export.ImagesInline])
+
+	ed.formatSelect = widget.NewSelect(formats, func(string) { ed.updateImageOptions() })
+	ed.formatSelect.SetSelected(exportFormatLabels[export.FormatJSONL])
+
+	ed.fromEntry = widget.NewEntry()
+	ed.fromEntry.SetPlaceHolder("YYYY-MM-DD")
+	ed.toEntry = widget.NewEntry()
+	ed.toEntry.SetPlaceHolder("YYYY-MM-DD")
+
+	ed.typeSelect = widget.NewSelect(exportTypeLabels, nil)
+	ed.typeSelect.SetSelected(exportTypeLabels[0])
+
+	ed.tagsEntry = widget.NewEntry()
+	ed.tagsEntry.SetPlaceHolder("Any tags")
+
+	ed.pinnedCheck = widget.NewCheck("Pinned items only", nil)
+	ed.sensitiveCheck = widget.NewCheck("Include sensitive items (JWTs, expiring items, items tagged sensitive)", nil)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Format", ed.formatSelect),
+		widget.NewFormItem("Images", ed.imagesSelect),
+		widget.NewFormItem("From", ed.fromEntry),
+		widget.NewFormItem("To", ed.toEntry),
+		widget.NewFormItem("Type", ed.typeSelect),
+		widget.NewFormItem("Tags", ed.tagsEntry),
+		widget.NewFormItem("", ed.pinnedCheck),
+		widget.NewFormItem("", ed.sensitiveCheck),
+	}
+
+	d := dialog.NewForm("Export History", "Export...", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		opts, err := ed.options()
+		if err != nil {
+			dialog.ShowError(err, ed.parent)
+			return
+		}
+		ed.chooseFile(opts)
+	}, ed.parent)
+	d.Resize(fyne.NewSize(450, 0))
+	d.Show()
+}
+
+// updateImageOptions enables the image choice only for formats that use it.
+func (ed *ExportDialog) updateImageOptions() {
+	switch ed.selectedFormat() {
+	case export.FormatJSONL:
+		ed.imagesSelect.Enable()
+	case export.FormatMarkdown:
+		ed.imagesSelect.Enable()
+		if ed.imagesSelect.Selected == exportImageLabels[export.ImagesInline] {
+			ed.imagesSelect.SetSelected(exportImageLabels[export.ImagesFiles])
+		}
+	default:
+		ed.imagesSelect.Disable()
+	}
+}
+
+func (ed *ExportDialog) selectedFormat() export.Format {
+	for f, label := range exportFormatLabels {
+		if label == ed.formatSelect.Selected {
+			return f
+		}
+	}
+	return export.FormatJSONL
+}
+
+func (ed *ExportDialog) options() (export.Options, error) {
+	opts := export.Options{
+		Format: ed.selectedFormat(),
+		Images: export.ImagesNone,
+		Filter: database.ExportFilter{
+			Tags:             ed.tagsEntry.Text,
+			PinnedOnly:       ed.pinnedCheck.Checked,
+			IncludeSensitive: ed.sensitiveCheck.Checked,
+		},
+	}
+
+	for mode, label := range exportImageLabels {
+		if label == ed.imagesSelect.Selected {
+			opts.Images = mode
+		}
+	}
+
+	switch ed.typeSelect.SelectedIndex() {
+	case 1:
+		opts.Filter.Type = "text"
+	case 2:
+		opts.Filter.Type = "image"
+	}
+
+	var err error
+	if opts.Filter.From, err = parseExportDate(ed.fromEntry.Text); err != nil {
+		return opts, fmt.Errorf("invalid start date: %w", err)
+	}
+	if opts.Filter.To, err = parseExportDate(ed.toEntry.Text); err != nil {
+		return opts, fmt.Errorf("invalid end date: %w", err)
+	}
+	if !opts.Filter.To.IsZero() {
+		opts.Filter.To = opts.Filter.To.AddDate(0, 0, 1) // Include the whole day
+	}
+
+	return opts, nil
+}
+
+func (ed *ExportDialog) chooseFile(opts export.Options) {
+	save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, ed.parent)
+			return
+		}
+		if writer == nil {
+			return // Cancelled
+		}
+		path := writer.URI().Path()
+		writer.Close()
+
+		go ed.export(path, opts)
+	}, ed.parent)
+
+	save.SetFileName(export.DefaultFileName(opts.Format, time.Now()))
+	save.Show()
+}
+
+func (ed *ExportDialog) export(path string, opts export.Options) {
+	result, err := export.Export(context.Background(), ed.repository, path, opts)
+
+	fyne.Do(func() {
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("failed to export history: %w", err), ed.parent)
+			return
+		}
+
+		message := fmt.Sprintf("Exported %d items to %s", result.Items, result.Path)
+		if result.Images > 0 {
+			message += fmt.Sprintf(" (%d images)", result.Images)
+		}
+		dialog.ShowInformation("Export Complete", message, ed.parent)
+	})
+}
+
+func parseExportDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation(exportDateLayout, value, time.Local)
+}
@@ -0,0 +1,165 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"clipboardpro/internal/database"
+	"clipboardpro/internal/importer"
+)
+
+// ImportDialog imports history from an export file or another clipboard
+// manager. The user picks a source, a file and a conflict policy and sees a
+// dry-run summary before anything is written.
+type ImportDialog struct {
+	repository database.Store
+	itemList   *ItemList
+	parent     fyne.Window
+	sources    []importer.Source
+
+	plan         *importer.Plan
+	generation   int
+	sourceSelect *widget.Select
+	pathEntry    *widget.Entry
+	policySelect *widget.Select
+	summaryLabel *widget.Label
+	confirm      *dialog.ConfirmDialog
+}
+
+func NewImportDialog(repository database.Store, itemList *ItemList, parent fyne.Window) *ImportDialog {
+	return &ImportDialog{
+		repository: repository,
+		itemList:   itemList,
+		parent:     parent,
+		sources:    importer.Sources(),
+	}
+}
+
+func (imp *ImportDialog) Show() {
+	sourceNames := make([]string, len(imp.sources))
+	for i, source := range imp.sources {
+		sourceNames[i] = source.Name()
+	}
+	policies := make([]string, len(importer.Policies))
+	for i, p := range importer.Policies {
+		policies[i] = p.Description()
+	}
+
+	imp.summaryLabel = widget.NewLabel("Choose a file to see what will be imported.")
+	imp.summaryLabel.Wrapping = fyne.TextWrapWord
+
+	imp.pathEntry = widget.NewEntry()
+	imp.pathEntry.SetPlaceHolder("History file")
+	imp.pathEntry.OnSubmitted = func(string) { imp.updatePlan() }
+
+	browseButton := widget.NewButtonWithIcon("", theme.FolderOpenIcon(), imp.browse)
+	previewButton := widget.NewButtonWithIcon("Preview", theme.SearchIcon(), imp.updatePlan)
+
+	imp.sourceSelect = widget.NewSelect(sourceNames, func(string) {
+		imp.pathEntry.SetText(imp.selectedSource().DefaultPath())
+		imp.updatePlan()
+	})
+	imp.policySelect = widget.NewSelect(policies, func(string) { imp.updatePlan() })
+
+	content := container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem("Import from", imp.sourceSelect),
+			widget.NewFormItem("File", container.NewBorder(nil, nil, nil, browseButton, imp.pathEntry)),
+			widget.NewFormItem("When an item exists", imp.policySelect),
+		),
+		widget.NewSeparator(),
+		container.NewBorder(nil, nil, nil, previewButton, imp.summaryLabel),
+	)
+
+	imp.confirm = dialog.NewCustomConfirm("Import History", "Import", "Cancel", content, func(ok bool) {
+		if ok && imp.plan != nil {
+			go imp.apply(imp.plan)
+		}
+	}, imp.parent)
+	imp.confirm.Resize(fyne.NewSize(600, 0))
+	imp.confirm.Show()
+
+	imp.policySelect.SetSelectedIndex(0)
+	imp.sourceSelect.SetSelectedIndex(0)
+}
+
+func (imp *ImportDialog) selectedSource() importer.Source {
+	if i := imp.sourceSelect.SelectedIndex(); i >= 0 {
+		return imp.sources[i]
+	}
+	return imp.sources[0]
+}
+
+func (imp *ImportDialog) browse() {
+	open := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, imp.parent)
+			return
+		}
+		if reader == nil {
+			return // Cancelled
+		}
+		imp.pathEntry.SetText(reader.URI().Path())
+		reader.Close()
+
+		imp.updatePlan()
+	}, imp.parent)
+	open.Show()
+}
+
+// updatePlan runs a dry run of the import with the selected options.
+func (imp *ImportDialog) updatePlan() {
+	if imp.policySelect.SelectedIndex() < 0 {
+		return
+	}
+	source := imp.selectedSource()
+	policy := importer.Policies[imp.policySelect.SelectedIndex()]
+	path := strings.TrimSpace(imp.pathEntry.Text)
+
+	imp.generation++
+	generation := imp.generation
+	imp.plan = nil
+
+	if path == "" {
+		imp.summaryLabel.SetText(fmt.Sprintf("No %s history was found. Choose the file to import.", source.Name()))
+		return
+	}
+	imp.summaryLabel.SetText("Reading history...")
+
+	go func() {
+		plan, err := importer.PlanSource(context.Background(), imp.repository, source, path, policy)
+
+		fyne.Do(func() {
+			if generation != imp.generation {
+				return // The options changed while this plan was prepared
+			}
+			if err != nil {
+				imp.summaryLabel.SetText(err.Error())
+				return
+			}
+			imp.plan = plan
+			imp.summaryLabel.SetText(plan.Summary.String())
+		})
+	}()
+}
+
+func (imp *ImportDialog) apply(plan *importer.Plan) {
+	report, err := plan.Apply(context.Background(), imp.repository)
+
+	fyne.Do(func() {
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("failed to import history: %w", err), imp.parent)
+			return
+		}
+
+		imp.itemList.Refresh()
+		dialog.ShowInformation("Import Complete", report.String(), imp.parent)
+	})
+}
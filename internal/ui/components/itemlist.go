@@ -2,6 +2,7 @@ package components
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,36 +12,46 @@ import (
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 
+	"clipboardpro/internal/classify"
 	"clipboardpro/internal/config"
 	"clipboardpro/internal/database"
 )
 
+// loadMoreThreshold is how many rows before the end of the list the next page is requested.
+const loadMoreThreshold = 10
+
 type ItemList struct {
 	controller  *ItemListController
 	app         AppInterface
 	container   *fyne.Container
 	list        *widget.List
 	statusLabel *widget.Label
+	undoButton  *widget.Button
 }
 
 type AppInterface interface {
-	GetRepository() *database.Repository
+	GetRepository() database.Store
 	CopyItemToClipboard(id int64) error
+	CopyTextToClipboard(text string) error
 	GetConfig() *config.Config
 	GetWindow() fyne.Window
+	RecordActivity()
 }
 
-func NewItemList(repository *database.Repository, app AppInterface) *ItemList {
+func NewItemList(repository database.Store, app AppInterface) *ItemList {
 	statusLabel := widget.NewLabel("Ready")
+	undoButton := widget.NewButtonWithIcon("Undo", theme.ContentUndoIcon(), nil)
 	itemList := &ItemList{
 		app:         app,
 		statusLabel: statusLabel,
+		undoButton:  undoButton,
 	}
 
 	itemList.controller = NewItemListController(
 		repository,
 		app,
 		statusLabel,
+		undoButton,
 		itemList.listRefresh,
 		itemList.getWindow,
 	)
@@ -56,13 +67,28 @@ func (il *ItemList) getWindow() fyne.Window {
 	return nil
 }
 
+// ScrollOffset returns how far the list is scrolled.
+func (il *ItemList) ScrollOffset() float32 {
+	if il.list == nil {
+		return 0
+	}
+	return il.list.GetScrollOffset()
+}
+
+// recordActivity postpones the idle lock while the list is being used.
+func (il *ItemList) recordActivity() {
+	if il.app != nil {
+		il.app.RecordActivity()
+	}
+}
+
 func (il *ItemList) Create() fyne.CanvasObject {
 	if il.container == nil {
-		// Create header with count and status
+		// Create header with count, sort mode and status
 		header := container.NewBorder(
 			nil, nil,
 			widget.NewLabelWithStyle("Clipboard History", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
-			il.statusLabel,
+			container.NewHBox(il.statusLabel, il.undoButton, il.createSortSelect()),
 		)
 
 		// Create list container with header
@@ -89,6 +115,7 @@ func (il *ItemList) createList() {
 	)
 
 	il.list.OnSelected = func(id widget.ListItemID) {
+		il.recordActivity()
 		if id < len(il.controller.GetItems()) {
 			il.controller.CopyItem(il.controller.GetItems()[id].ID)
 		}
@@ -96,6 +123,21 @@ func (il *ItemList) createList() {
 	}
 }
 
+func (il *ItemList) createSortSelect() *widget.Select {
+	modes := map[string]database.SortMode{
+		"Recent":    database.SortRecent,
+		"Most used": database.SortMostUsed,
+		"Frecency":  database.SortFrecency,
+	}
+
+	sortSelect := widget.NewSelect([]string{"Recent", "Most used", "Frecency"}, func(selected string) {
+		il.recordActivity()
+		il.controller.SetSortMode(modes[selected])
+	})
+	sortSelect.SetSelected("Recent")
+	return sortSelect
+}
+
 func (il *ItemList) listRefresh() {
 	il.list.Refresh()
 }
@@ -117,6 +159,9 @@ func (il *ItemList) createItemTemplate() fyne.CanvasObject {
 	size := widget.NewLabel("")
 	size.TextStyle = fyne.TextStyle{Monospace: true}
 
+	usage := widget.NewLabel("")
+	usage.TextStyle = fyne.TextStyle{Italic: true}
+
 	pinButton := widget.NewButtonWithIcon("", theme.ContentAddIcon(), nil)
 	pinButton.Importance = widget.LowImportance
 
@@ -126,16 +171,22 @@ func (il *ItemList) createItemTemplate() fyne.CanvasObject {
 	deleteButton := widget.NewButtonWithIcon("", theme.DeleteIcon(), nil)
 	deleteButton.Importance = widget.LowImportance
 
+	moreButton := widget.NewButtonWithIcon("", theme.MoreVerticalIcon(), nil)
+	moreButton.Importance = widget.LowImportance
+
 	actionContainer := container.NewHBox(
 		pinButton,
 		editButton,
 		deleteButton,
+		moreButton,
 	)
 
 	infoContainer := container.NewHBox(
 		timestamp,
 		widget.NewSeparator(),
 		size,
+		widget.NewSeparator(),
+		usage,
 		layout.NewSpacer(),
 	)
 
@@ -166,6 +217,11 @@ func (il *ItemList) updateItem(id widget.ListItemID, obj fyne.CanvasObject) {
 		return
 	}
 
+	// Fetch the next page when the user scrolls close to the end
+	if id >= len(items)-loadMoreThreshold {
+		il.controller.LoadMore()
+	}
+
 	item := items[id]
 	paddedContainer := obj.(*fyne.Container)
 	container := paddedContainer.Objects[0].(*fyne.Container)
@@ -181,17 +237,29 @@ func (il *ItemList) updateItem(id widget.ListItemID, obj fyne.CanvasObject) {
 
 	timestamp := infoContainer.Objects[0].(*widget.Label)
 	size := infoContainer.Objects[2].(*widget.Label)
+	usageSeparator := infoContainer.Objects[3]
+	usage := infoContainer.Objects[4].(*widget.Label)
 
 	pinButton := actionContainer.Objects[0].(*widget.Button)
 	editButton := actionContainer.Objects[1].(*widget.Button)
 	deleteButton := actionContainer.Objects[2].(*widget.Button)
+	moreButton := actionContainer.Objects[3].(*widget.Button)
 
-	icon.SetResource(il.getItemIcon(item.Type))
+	icon.SetResource(il.getItemIcon(item))
 	title.SetText(il.getItemTitle(item))
 	preview.SetText(il.getItemPreview(item))
-	timestamp.SetText(il.formatTimeAgo(item.Timestamp))
+	timestamp.SetText(il.formatTimeAgo(item.Timestamp) + il.formatExpiry(item) + formatTags(item))
 	size.SetText(il.formatBytes(item.Size))
 
+	if item.CopyCount > 0 {
+		usage.SetText(il.formatUsage(item))
+		usageSeparator.Show()
+		usage.Show()
+	} else {
+		usageSeparator.Hide()
+		usage.Hide()
+	}
+
 	if item.Pinned {
 		pinButton.SetIcon(theme.ContentRemoveIcon()) // Minus icon for unpinning
 		pinButton.SetText("Unpin")
@@ -201,22 +269,131 @@ func (il *ItemList) updateItem(id widget.ListItemID, obj fyne.CanvasObject) {
 	}
 
 	pinButton.OnTapped = func() {
-		il.controller.TogglePin(item.ID)
+		il.recordActivity()
+		il.controller.TogglePin(item)
 	}
 
 	editButton.OnTapped = func() {
+		il.recordActivity()
 		il.controller.EditTitle(item)
 	}
 
 	deleteButton.OnTapped = func() {
+		il.recordActivity()
 		il.controller.DeleteItem(item.ID)
 	}
+
+	moreButton.OnTapped = func() {
+		il.recordActivity()
+		il.showItemMenu(item, moreButton)
+	}
+}
+
+// showItemMenu shows the row menu with the less common actions for an item.
+func (il *ItemList) showItemMenu(item *database.ClipboardItem, button *widget.Button) {
+	window := il.getWindow()
+	if window == nil {
+		return
+	}
+
+	menuItems := []*fyne.MenuItem{
+		fyne.NewMenuItem("Details & Notes…", func() {
+			NewItemDetailDialog(il, item.ID, window).Show()
+		}),
+		fyne.NewMenuItem("Edit Tags…", func() {
+			il.controller.EditTags(item)
+		}),
+	}
+	if actions := il.createSmartActionMenuItems(item); len(actions) > 0 {
+		menuItems = append(menuItems, fyne.NewMenuItemSeparator())
+		menuItems = append(menuItems, actions...)
+	}
+	if item.Pinned {
+		moveUp := fyne.NewMenuItem("Move Up", func() {
+			il.controller.MovePinnedItem(item.ID, -1)
+		})
+		moveUp.Icon = theme.MoveUpIcon()
+
+		moveDown := fyne.NewMenuItem("Move Down", func() {
+			il.controller.MovePinnedItem(item.ID, 1)
+		})
+		moveDown.Icon = theme.MoveDownIcon()
+
+		menuItems = append(menuItems, fyne.NewMenuItemSeparator(), moveUp, moveDown)
+	}
+	menuItems = append(menuItems, fyne.NewMenuItemSeparator(), il.createExpiryMenuItem(item))
+
+	burnAfterCopy := fyne.NewMenuItem("Delete After First Copy", func() {
+		il.controller.ToggleBurnAfterCopy(item)
+	})
+	burnAfterCopy.Checked = item.BurnAfterCopy
+	menuItems = append(menuItems, burnAfterCopy)
+
+	if item.Type == "text" {
+		menuItems = append(menuItems,
+			fyne.NewMenuItemSeparator(),
+			fyne.NewMenuItem("Edit Content…", func() {
+				il.controller.EditContent(item.ID)
+			}),
+			il.createTransformMenuItem(item),
+			fyne.NewMenuItem("Join with Other Items…", func() {
+				il.ShowJoinDialog(item)
+			}),
+			fyne.NewMenuItem("Split into Items…", func() {
+				il.ShowSplitDialog(item)
+			}),
+			fyne.NewMenuItem("Revision History…", func() {
+				NewRevisionsDialog(il.controller.repository, il, item.ID, window).Show()
+			}),
+			fyne.NewMenuItem("Compare with…", func() {
+				NewCompareDialog(il, item, window).Show()
+			}),
+		)
+	}
+	widget.ShowPopUpMenuAtRelativePosition(fyne.NewMenu("", menuItems...), window.Canvas(),
+		fyne.NewPos(0, button.Size().Height), button)
+}
+
+// createExpiryMenuItem returns the row menu entry with the choices of when
+// an item expires.
+func (il *ItemList) createExpiryMenuItem(item *database.ClipboardItem) *fyne.MenuItem {
+	choices := []struct {
+		label    string
+		duration time.Duration
+	}{
+		{"In 1 Minute", time.Minute},
+		{"In 1 Hour", time.Hour},
+		{"In 1 Day", 24 * time.Hour},
+	}
+
+	var submenu []*fyne.MenuItem
+	for _, choice := range choices {
+		submenu = append(submenu, fyne.NewMenuItem(choice.label, func() {
+			il.controller.SetExpiry(item, choice.duration)
+		}))
+	}
+	submenu = append(submenu, fyne.NewMenuItem("Custom…", func() {
+		il.controller.PromptExpiry(item)
+	}))
+	if !item.ExpiresAt.IsZero() {
+		submenu = append(submenu, fyne.NewMenuItemSeparator(), fyne.NewMenuItem("Never", func() {
+			il.controller.SetExpiry(item, 0)
+		}))
+	}
+
+	expire := fyne.NewMenuItem("Expire", nil)
+	expire.ChildMenu = fyne.NewMenu("", submenu...)
+	return expire
 }
 
 func (il *ItemList) LoadRecentItems() {
 	il.controller.LoadRecentItems()
 }
 
+func (il *ItemList) RefreshIfChanged() {
+	il.controller.RefreshIfChanged()
+}
+
 func (il *ItemList) Search(query string) {
 	il.controller.Search(query)
 }
@@ -229,12 +406,22 @@ func (il *ItemList) Refresh() {
 	il.controller.Refresh()
 }
 
+func (il *ItemList) ClearAll() {
+	il.controller.ClearAll()
+}
+
+func (il *ItemList) Undo() {
+	il.controller.Undo()
+}
 
+func (il *ItemList) Redo() {
+	il.controller.Redo()
+}
 
-func (il *ItemList) getItemIcon(itemType string) fyne.Resource {
-	switch itemType {
+func (il *ItemList) getItemIcon(item *database.ClipboardItem) fyne.Resource {
+	switch item.Type {
 	case "text":
-		return theme.DocumentIcon()
+		return subtypeIcon(item.Subtype)
 	case "image":
 		return theme.FileImageIcon()
 	default:
@@ -242,6 +429,38 @@ func (il *ItemList) getItemIcon(itemType string) fyne.Resource {
 	}
 }
 
+// subtypeIcon returns the icon for a kind of text, see package classify.
+func subtypeIcon(subtype string) fyne.Resource {
+	switch subtype {
+	case classify.URL:
+		return theme.ComputerIcon()
+	case classify.Email:
+		return theme.MailComposeIcon()
+	case classify.Phone:
+		return theme.AccountIcon()
+	case classify.Color:
+		return theme.ColorPaletteIcon()
+	case classify.JSON, classify.YAML:
+		return theme.ListIcon()
+	case classify.XML, classify.HTML:
+		return theme.FileTextIcon()
+	case classify.Path:
+		return theme.FolderIcon()
+	case classify.Number:
+		return theme.GridIcon()
+	case classify.UUID:
+		return theme.InfoIcon()
+	case classify.IP:
+		return theme.StorageIcon()
+	case classify.JWT:
+		return theme.LoginIcon()
+	case classify.Code:
+		return theme.FileApplicationIcon()
+	default:
+		return theme.DocumentIcon()
+	}
+}
+
 func (il *ItemList) getItemTitle(item *database.ClipboardItem) string {
 	if item.Title != "" {
 		return item.Title
@@ -249,7 +468,7 @@ func (il *ItemList) getItemTitle(item *database.ClipboardItem) string {
 
 	switch item.Type {
 	case "text":
-		content := strings.ReplaceAll(item.Content, "\n", " ")
+		content := strings.ReplaceAll(item.PreviewText(), "\n", " ")
 		content = strings.TrimSpace(content)
 		if len(content) > 60 {
 			return content[:60] + "..."
@@ -268,7 +487,7 @@ func (il *ItemList) getItemTitle(item *database.ClipboardItem) string {
 func (il *ItemList) getItemPreview(item *database.ClipboardItem) string {
 	switch item.Type {
 	case "text":
-		content := strings.ReplaceAll(item.Content, "\n", " ")
+		content := strings.ReplaceAll(item.PreviewText(), "\n", " ")
 		content = strings.TrimSpace(content)
 		if len(content) > 120 {
 			content = content[:120] + "..."
@@ -319,6 +538,66 @@ func (il *ItemList) formatTimeAgo(timestamp time.Time) string {
 	return timestamp.Format("Jan 2, 2006")
 }
 
+func (il *ItemList) formatUsage(item *database.ClipboardItem) string {
+	if item.LastUsedAt.IsZero() {
+		return fmt.Sprintf("Used %d×", item.CopyCount)
+	}
+	return fmt.Sprintf("Used %d× (last: %s)", item.CopyCount, il.formatTimeAgo(item.LastUsedAt))
+}
+
+// formatExpiry describes when an item is deleted for good, if it is, as a
+// suffix for its timestamp.
+func (il *ItemList) formatExpiry(item *database.ClipboardItem) string {
+	var parts []string
+	if !item.ExpiresAt.IsZero() {
+		remaining := time.Until(item.ExpiresAt)
+		switch {
+		case remaining < time.Minute:
+			parts = append(parts, "expires in under a minute")
+		case remaining < time.Hour:
+			parts = append(parts, fmt.Sprintf("expires in %d min", int(remaining.Minutes())))
+		case remaining < 48*time.Hour:
+			parts = append(parts, fmt.Sprintf("expires in %d h", int(remaining.Hours())))
+		default:
+			parts = append(parts, "expires "+item.ExpiresAt.Format("Jan 2"))
+		}
+	}
+	if item.BurnAfterCopy {
+		parts = append(parts, "deleted after copy")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " • " + strings.Join(parts, " • ")
+}
+
+// formatTags lists the tags of an item as a suffix for its timestamp.
+func formatTags(item *database.ClipboardItem) string {
+	tags := database.SplitTags(item.Tags)
+	if len(tags) == 0 {
+		return ""
+	}
+	return " • #" + strings.Join(tags, " #")
+}
+
+// parseExpiryDuration parses a custom expiry such as "90s", "15m", "2h" or
+// "3d". A plain number is taken as minutes.
+func parseExpiryDuration(text string) (time.Duration, error) {
+	text = strings.TrimSpace(text)
+	if minutes, err := strconv.Atoi(text); err == nil && minutes > 0 {
+		return time.Duration(minutes) * time.Minute, nil
+	}
+	if days, ok := strings.CutSuffix(text, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n > 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	}
+	if d, err := time.ParseDuration(text); err == nil && d > 0 {
+		return d, nil
+	}
+	return 0, fmt.Errorf("enter a time such as 15m, 2h or 3d")
+}
+
 func (il *ItemList) formatBytes(bytes int) string {
 	if bytes < 1024 {
 		return fmt.Sprintf("%d B", bytes)
@@ -2,7 +2,9 @@ package components
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"fyne.io/fyne/v2"
@@ -10,27 +12,51 @@ import (
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/widget"
 
+	"clipboardpro/internal/clipboard"
 	"clipboardpro/internal/database"
 )
 
+// pageSize is the number of items fetched per page of the history list.
+const pageSize = 100
+
+// undoOfferDuration is how long the Undo button stays visible after a destructive action.
+const undoOfferDuration = 8 * time.Second
+
 type ItemListController struct {
-	repository  *database.Repository
+	repository  database.Store
 	app         AppInterface
-	statusLabel *widget.Label // Reference to the UI status label
+	statusLabel *widget.Label  // Reference to the UI status label
+	undoButton  *widget.Button // Transient button offering to undo the last action
+	journal     *ActionJournal
+	undoOffer   int // Incremented each time the Undo button is offered
 	items       []*database.ClipboardItem
+	nextPage    *database.Cursor // Cursor of the next page; nil when all items are loaded
+	total       int              // Number of items matching the current search
+	loadingMore bool
+	generation  int    // Incremented on every reload to discard stale pages
+	version     uint64 // Repository version the items were loaded at
+	current     bool   // Whether the items were loaded at version
 	searchTerm  string
-	listRefresh func() // Callback to refresh the UI list
+	sortMode    database.SortMode
+	listRefresh func()             // Callback to refresh the UI list
 	getWindow   func() fyne.Window // Callback to get the main window
 }
 
-func NewItemListController(repository *database.Repository, app AppInterface, statusLabel *widget.Label, listRefresh func(), getWindow func() fyne.Window) *ItemListController {
-	return &ItemListController{
+func NewItemListController(repository database.Store, app AppInterface, statusLabel *widget.Label, undoButton *widget.Button, listRefresh func(), getWindow func() fyne.Window) *ItemListController {
+	ilc := &ItemListController{
 		repository:  repository,
 		app:         app,
 		statusLabel: statusLabel,
+		undoButton:  undoButton,
+		journal:     NewActionJournal(100),
 		listRefresh: listRefresh,
 		getWindow:   getWindow,
+		sortMode:    database.SortRecent,
 	}
+
+	undoButton.OnTapped = ilc.Undo
+	undoButton.Hide()
+	return ilc
 }
 
 func (ilc *ItemListController) GetItems() []*database.ClipboardItem {
@@ -39,83 +65,176 @@ func (ilc *ItemListController) GetItems() []*database.ClipboardItem {
 
 // LoadRecentItems loads the most recent clipboard items from the database.
 func (ilc *ItemListController) LoadRecentItems() {
-	fyne.Do(func() {
-		ilc.statusLabel.SetText("Loading...")
-	})
+	ilc.reload()
+}
 
+// RefreshIfChanged reloads the items if the history changed since they were
+// loaded. Otherwise it only redraws them, which keeps their relative times
+// current without reading and decrypting every loaded item again.
+func (ilc *ItemListController) RefreshIfChanged() {
 	go func() {
-		ctx := context.Background()
-		items, err := ilc.repository.GetRecentItems(ctx, 100)
+		version, err := ilc.repository.Version(context.Background())
 
 		fyne.Do(func() {
-			if err != nil {
-				ilc.statusLabel.SetText("Error loading items")
-				window := ilc.getWindow()
-				if window != nil {
-					dialog.ShowError(fmt.Errorf("failed to load clipboard history: %w", err), window)
-				}
+			if err == nil && ilc.current && version == ilc.version {
+				ilc.listRefresh()
 				return
 			}
-
-			ilc.items = items
-			ilc.listRefresh()
-
-			// Update status
-			count := len(items)
-			if count == 0 {
-				ilc.statusLabel.SetText("No items yet")
-			} else if count == 1 {
-				ilc.statusLabel.SetText("1 item")
-			} else {
-				ilc.statusLabel.SetText(fmt.Sprintf("%d items", count))
-			}
+			ilc.reload()
 		})
 	}()
 }
 
 // Search searches for clipboard items based on a query.
 func (ilc *ItemListController) Search(query string) {
-	ilc.searchTerm = query
+	if query != ilc.searchTerm {
+		ilc.searchTerm = query
+		ilc.items = nil
+	}
 
 	if query == "" {
 		ilc.LoadRecentItems()
 		return
 	}
 
-	fyne.Do(func() {
-		ilc.statusLabel.SetText("Searching...")
-	})
+	ilc.reload()
+}
+
+// reload fetches the first page again. It fetches at least as many items as
+// are currently shown, so periodic refreshes don't collapse loaded pages.
+func (ilc *ItemListController) reload() {
+	ilc.generation++
+	generation := ilc.generation
+	query := database.ListQuery{
+		Search: ilc.searchTerm,
+		Sort:   ilc.sortMode,
+		Limit:  max(pageSize, len(ilc.items)),
+	}
+
+	if len(ilc.items) == 0 {
+		fyne.Do(func() {
+			if query.Search == "" {
+				ilc.statusLabel.SetText("Loading...")
+			} else {
+				ilc.statusLabel.SetText("Searching...")
+			}
+		})
+	}
+
+	ilc.current = false
 
 	go func() {
 		ctx := context.Background()
-		items, err := ilc.repository.SearchItems(ctx, query, 100)
+		// Read before the items, so a write in between is noticed next time
+		version, err := ilc.repository.Version(ctx)
+		var page *database.Page
+		if err == nil {
+			page, err = ilc.repository.ListItems(ctx, query)
+		}
+		total := 0
+		if err == nil {
+			total, err = ilc.repository.CountItems(ctx, query.Search)
+		}
 
 		fyne.Do(func() {
+			if generation != ilc.generation {
+				return // A newer load has started
+			}
+
+			if errors.Is(err, database.ErrLocked) {
+				ilc.items = nil
+				ilc.nextPage = nil
+				ilc.listRefresh()
+				ilc.statusLabel.SetText("History is locked")
+				return
+			}
 			if err != nil {
-				ilc.statusLabel.SetText("Search failed")
-				window := ilc.getWindow()
-				if window != nil {
-					dialog.ShowError(fmt.Errorf("Search failed: %w", err), window)
+				if query.Search == "" {
+					ilc.statusLabel.SetText("Error loading items")
+					ilc.showError(fmt.Errorf("failed to load clipboard history: %w", err))
+				} else {
+					ilc.statusLabel.SetText("Search failed")
+					ilc.showError(fmt.Errorf("Search failed: %w", err))
 				}
 				return
 			}
 
-			ilc.items = items
+			ilc.items = page.Items
+			ilc.nextPage = page.Next
+			ilc.total = total
+			ilc.version = version
+			ilc.current = true
 			ilc.listRefresh()
+			ilc.updateCountStatus()
+		})
+	}()
+}
 
-			// Update status
-			count := len(items)
-			if count == 0 {
-				ilc.statusLabel.SetText(fmt.Sprintf("No results for '%s'", query))
-			} else if count == 1 {
-				ilc.statusLabel.SetText("1 result")
-			} else {
-				ilc.statusLabel.SetText(fmt.Sprintf("%d results", count))
+// LoadMore appends the next page of items, if there is one.
+func (ilc *ItemListController) LoadMore() {
+	if ilc.nextPage == nil || ilc.loadingMore {
+		return
+	}
+
+	ilc.loadingMore = true
+	generation := ilc.generation
+	query := database.ListQuery{
+		Search: ilc.searchTerm,
+		Sort:   ilc.sortMode,
+		Limit:  pageSize,
+		After:  ilc.nextPage,
+	}
+
+	go func() {
+		ctx := context.Background()
+		page, err := ilc.repository.ListItems(ctx, query)
+
+		fyne.Do(func() {
+			ilc.loadingMore = false
+			if generation != ilc.generation {
+				return // The list was reloaded meanwhile
 			}
+
+			if err != nil {
+				ilc.statusLabel.SetText("Error loading more items")
+				return
+			}
+
+			ilc.items = append(ilc.items, page.Items...)
+			ilc.nextPage = page.Next
+			ilc.listRefresh()
+			ilc.updateCountStatus()
 		})
 	}()
 }
 
+func (ilc *ItemListController) updateCountStatus() {
+	count := len(ilc.items)
+
+	if ilc.searchTerm != "" {
+		if count == 0 {
+			ilc.statusLabel.SetText(fmt.Sprintf("No results for '%s'", ilc.searchTerm))
+		} else if ilc.total == 1 {
+			ilc.statusLabel.SetText("1 result")
+		} else if count < ilc.total {
+			ilc.statusLabel.SetText(fmt.Sprintf("%d of %d results", count, ilc.total))
+		} else {
+			ilc.statusLabel.SetText(fmt.Sprintf("%d results", ilc.total))
+		}
+		return
+	}
+
+	if count == 0 {
+		ilc.statusLabel.SetText("No items yet")
+	} else if ilc.total == 1 {
+		ilc.statusLabel.SetText("1 item")
+	} else if count < ilc.total {
+		ilc.statusLabel.SetText(fmt.Sprintf("%d of %d items", count, ilc.total))
+	} else {
+		ilc.statusLabel.SetText(fmt.Sprintf("%d items", ilc.total))
+	}
+}
+
 // IsSearching returns true if a search query is active.
 func (ilc *ItemListController) IsSearching() bool {
 	return ilc.searchTerm != ""
@@ -130,23 +249,38 @@ func (ilc *ItemListController) Refresh() {
 	}
 }
 
-// CopyItem copies the item with the given ID to the clipboard.
-func (ilc *ItemListController) CopyItem(id int64) {
-	if err := ilc.app.CopyItemToClipboard(id); err != nil {
-		fyne.Do(func() {
-			window := ilc.getWindow()
-			if window != nil {
-				dialog.ShowError(fmt.Errorf("failed to copy item: %w", err), window)
-			}
-		})
+// SetSortMode changes the ordering of the list and reloads it.
+func (ilc *ItemListController) SetSortMode(mode database.SortMode) {
+	if ilc.sortMode == mode {
 		return
 	}
+	ilc.sortMode = mode
+	ilc.items = nil
+	ilc.Refresh()
+}
 
-	fyne.Do(func() {
-		ilc.statusLabel.SetText("✓ Copied to clipboard")
-	})
-
+// CopyItem copies the item with the given ID to the clipboard. Template
+// items may prompt for input, so the copy runs in the background.
+func (ilc *ItemListController) CopyItem(id int64) {
 	go func() {
+		if err := ilc.app.CopyItemToClipboard(id); err != nil {
+			fyne.Do(func() {
+				if errors.Is(err, clipboard.ErrCopyCancelled) {
+					ilc.statusLabel.SetText("Copy cancelled")
+					return
+				}
+				window := ilc.getWindow()
+				if window != nil {
+					dialog.ShowError(fmt.Errorf("failed to copy item: %w", err), window)
+				}
+			})
+			return
+		}
+
+		fyne.Do(func() {
+			ilc.statusLabel.SetText("✓ Copied to clipboard")
+		})
+
 		time.Sleep(2 * time.Second)
 		fyne.Do(func() {
 			ilc.Refresh()
@@ -155,7 +289,8 @@ func (ilc *ItemListController) CopyItem(id int64) {
 }
 
 // TogglePin toggles the pinned status of an item.
-func (ilc *ItemListController) TogglePin(id int64) {
+func (ilc *ItemListController) TogglePin(item *database.ClipboardItem) {
+	id, wasPinned, oldPosition := item.ID, item.Pinned, item.Position
 	go func() {
 		ctx := context.Background()
 		if err := ilc.repository.TogglePin(ctx, id); err != nil {
@@ -168,6 +303,47 @@ func (ilc *ItemListController) TogglePin(id int64) {
 			return
 		}
 
+		ilc.journal.Record(Action{
+			Description: "pin change",
+			Undo: func(ctx context.Context) error {
+				return ilc.repository.SetPinned(ctx, id, wasPinned, oldPosition)
+			},
+			Redo: func(ctx context.Context) error { return ilc.repository.PinItem(ctx, id, !wasPinned) },
+		})
+
+		fyne.Do(func() {
+			ilc.Refresh()
+		})
+	}()
+}
+
+// MovePinnedItem moves a pinned item one slot up (offset < 0) or down (offset > 0).
+func (ilc *ItemListController) MovePinnedItem(id int64, offset int) {
+	go func() {
+		ctx := context.Background()
+		moved, err := ilc.repository.MovePinnedItem(ctx, id, offset)
+		if err != nil {
+			fyne.Do(func() {
+				ilc.showError(fmt.Errorf("failed to move item: %w", err))
+			})
+			return
+		}
+		if !moved {
+			return // Already at the top or bottom, so there is nothing to undo
+		}
+
+		move := func(offset int) func(context.Context) error {
+			return func(ctx context.Context) error {
+				_, err := ilc.repository.MovePinnedItem(ctx, id, offset)
+				return err
+			}
+		}
+		ilc.journal.Record(Action{
+			Description: "move",
+			Undo:        move(-offset),
+			Redo:        move(offset),
+		})
+
 		fyne.Do(func() {
 			ilc.Refresh()
 		})
@@ -183,7 +359,7 @@ func (ilc *ItemListController) DeleteItem(id int64) {
 
 	fyne.Do(func() {
 		dialog.ShowConfirm("Delete Item",
-			"Are you sure you want to permanently delete this clipboard item?",
+			"Move this clipboard item to the trash? You can restore it from the trash later.",
 			func(confirmed bool) {
 				if !confirmed {
 					return
@@ -198,8 +374,15 @@ func (ilc *ItemListController) DeleteItem(id int64) {
 						return
 					}
 
+					ilc.journal.Record(Action{
+						Description: "delete",
+						Undo:        func(ctx context.Context) error { return ilc.repository.RestoreItem(ctx, id) },
+						Redo:        func(ctx context.Context) error { return ilc.repository.DeleteItem(ctx, id) },
+					})
+
 					fyne.Do(func() {
-						ilc.statusLabel.SetText("Item deleted")
+						ilc.statusLabel.SetText("Item moved to trash")
+						ilc.offerUndo()
 						ilc.Refresh()
 					})
 				}()
@@ -229,20 +412,475 @@ func (ilc *ItemListController) EditTitle(item *database.ClipboardItem) {
 				return
 			}
 
+			id, oldTitle, newTitle := item.ID, item.Title, entry.Text
 			go func() {
 				ctx := context.Background()
-				if err := ilc.repository.UpdateTitle(ctx, item.ID, entry.Text); err != nil {
+				if err := ilc.repository.UpdateTitle(ctx, id, newTitle); err != nil {
 					fyne.Do(func() {
 						dialog.ShowError(fmt.Errorf("failed to update title: %w", err), window)
 					})
 					return
 				}
 
+				ilc.journal.Record(Action{
+					Description: "title edit",
+					Undo:        func(ctx context.Context) error { return ilc.repository.UpdateTitle(ctx, id, oldTitle) },
+					Redo:        func(ctx context.Context) error { return ilc.repository.UpdateTitle(ctx, id, newTitle) },
+				})
+
 				fyne.Do(func() {
 					ilc.statusLabel.SetText("Title updated")
+					ilc.offerUndo()
 					ilc.Refresh()
 				})
 			}()
 		}, window)
 	})
 }
+
+// EditTags allows editing the tags of an item.
+func (ilc *ItemListController) EditTags(item *database.ClipboardItem) {
+	window := ilc.getWindow()
+	if window == nil {
+		return
+	}
+
+	fyne.Do(func() {
+		entry := widget.NewEntry()
+		entry.SetText(strings.Join(database.SplitTags(item.Tags), ", "))
+		entry.SetPlaceHolder("work, snippets, …")
+
+		content := container.NewVBox(
+			widget.NewLabel("Separate tags with commas. Search for tagged items with tag:name."),
+			entry,
+		)
+
+		dialog.ShowCustomConfirm("Edit Tags", "Save", "Cancel", content, func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+
+			id, oldTags, newTags := item.ID, item.Tags, database.NormalizeTags(entry.Text)
+			if newTags == oldTags {
+				return
+			}
+			go func() {
+				ctx := context.Background()
+				if err := ilc.repository.UpdateTags(ctx, id, newTags); err != nil {
+					fyne.Do(func() {
+						dialog.ShowError(fmt.Errorf("failed to update tags: %w", err), window)
+					})
+					return
+				}
+
+				ilc.journal.Record(Action{
+					Description: "tag edit",
+					Undo:        func(ctx context.Context) error { return ilc.repository.UpdateTags(ctx, id, oldTags) },
+					Redo:        func(ctx context.Context) error { return ilc.repository.UpdateTags(ctx, id, newTags) },
+				})
+
+				fyne.Do(func() {
+					ilc.statusLabel.SetText("Tags updated")
+					ilc.offerUndo()
+					ilc.Refresh()
+				})
+			}()
+		}, window)
+	})
+}
+
+// SetExpiry makes an item expire after d from now, or never if d is 0.
+func (ilc *ItemListController) SetExpiry(item *database.ClipboardItem, d time.Duration) {
+	var expiresAt time.Time
+	if d > 0 {
+		expiresAt = time.Now().Add(d)
+	}
+
+	id, oldExpiresAt := item.ID, item.ExpiresAt
+	go func() {
+		ctx := context.Background()
+		if err := ilc.repository.SetExpiry(ctx, id, expiresAt); err != nil {
+			fyne.Do(func() {
+				ilc.showError(err)
+			})
+			return
+		}
+
+		ilc.journal.Record(Action{
+			Description: "expiry change",
+			Undo:        func(ctx context.Context) error { return ilc.repository.SetExpiry(ctx, id, oldExpiresAt) },
+			Redo:        func(ctx context.Context) error { return ilc.repository.SetExpiry(ctx, id, expiresAt) },
+		})
+
+		fyne.Do(func() {
+			if expiresAt.IsZero() {
+				ilc.statusLabel.SetText("Expiry removed")
+			} else {
+				ilc.statusLabel.SetText("Item expires " + expiresAt.Format("Jan 2 15:04"))
+			}
+			ilc.Refresh()
+		})
+	}()
+}
+
+// PromptExpiry asks for a custom time after which an item expires.
+func (ilc *ItemListController) PromptExpiry(item *database.ClipboardItem) {
+	window := ilc.getWindow()
+	if window == nil {
+		return
+	}
+
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder("e.g. 15m, 2h or 3d")
+	entry.Validator = func(text string) error {
+		_, err := parseExpiryDuration(text)
+		return err
+	}
+
+	content := container.NewVBox(
+		widget.NewLabel("Delete this item for good after:"),
+		entry,
+	)
+
+	dialog.ShowCustomConfirm("Expire Item", "Set", "Cancel", content, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		d, err := parseExpiryDuration(entry.Text)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		ilc.SetExpiry(item, d)
+	}, window)
+}
+
+// ToggleBurnAfterCopy switches whether an item is deleted for good once it
+// has been copied.
+func (ilc *ItemListController) ToggleBurnAfterCopy(item *database.ClipboardItem) {
+	id, burn := item.ID, !item.BurnAfterCopy
+	go func() {
+		ctx := context.Background()
+		if err := ilc.repository.SetBurnAfterCopy(ctx, id, burn); err != nil {
+			fyne.Do(func() {
+				ilc.showError(err)
+			})
+			return
+		}
+
+		ilc.journal.Record(Action{
+			Description: "delete after copy change",
+			Undo:        func(ctx context.Context) error { return ilc.repository.SetBurnAfterCopy(ctx, id, !burn) },
+			Redo:        func(ctx context.Context) error { return ilc.repository.SetBurnAfterCopy(ctx, id, burn) },
+		})
+
+		fyne.Do(func() {
+			if burn {
+				ilc.statusLabel.SetText("Item will be deleted after it is copied")
+			} else {
+				ilc.statusLabel.SetText("Item will be kept after it is copied")
+			}
+			ilc.Refresh()
+		})
+	}()
+}
+
+// ClearAll moves every item to the trash as a single undoable action.
+func (ilc *ItemListController) ClearAll() {
+	go func() {
+		ctx := context.Background()
+		ids, err := ilc.repository.ClearAllItems(ctx)
+		if err != nil {
+			fyne.Do(func() {
+				ilc.showError(fmt.Errorf("failed to clear all items: %w", err))
+			})
+			return
+		}
+
+		ilc.journal.Record(Action{
+			Description: "clear all",
+			Undo:        func(ctx context.Context) error { return ilc.repository.RestoreItems(ctx, ids) },
+			Redo: func(ctx context.Context) error {
+				return ilc.repository.TrashItems(ctx, ids, database.DeleteReasonClearAll)
+			},
+		})
+
+		fyne.Do(func() {
+			ilc.statusLabel.SetText("All items moved to trash")
+			ilc.offerUndo()
+			ilc.Refresh()
+		})
+	}()
+}
+
+// Undo reverts the most recent list action.
+func (ilc *ItemListController) Undo() {
+	go func() {
+		description, err := ilc.journal.Undo(context.Background())
+
+		fyne.Do(func() {
+			ilc.hideUndo()
+			if errors.Is(err, ErrNothingToUndo) {
+				ilc.statusLabel.SetText("Nothing to undo")
+				return
+			}
+			if err != nil {
+				ilc.showError(fmt.Errorf("failed to undo %s: %w", description, err))
+				return
+			}
+
+			ilc.statusLabel.SetText(fmt.Sprintf("Undid %s", description))
+			ilc.Refresh()
+		})
+	}()
+}
+
+// Redo re-applies the most recently undone list action.
+func (ilc *ItemListController) Redo() {
+	go func() {
+		description, err := ilc.journal.Redo(context.Background())
+
+		fyne.Do(func() {
+			if errors.Is(err, ErrNothingToRedo) {
+				ilc.statusLabel.SetText("Nothing to redo")
+				return
+			}
+			if err != nil {
+				ilc.showError(fmt.Errorf("failed to redo %s: %w", description, err))
+				return
+			}
+
+			ilc.statusLabel.SetText(fmt.Sprintf("Redid %s", description))
+			ilc.Refresh()
+		})
+	}()
+}
+
+// offerUndo shows the Undo button for a short time after a destructive action.
+func (ilc *ItemListController) offerUndo() {
+	ilc.undoOffer++
+	offer := ilc.undoOffer
+	ilc.undoButton.Show()
+
+	time.AfterFunc(undoOfferDuration, func() {
+		fyne.Do(func() {
+			if ilc.undoOffer == offer {
+				ilc.hideUndo()
+			}
+		})
+	})
+}
+
+func (ilc *ItemListController) hideUndo() {
+	ilc.undoOffer++
+	ilc.undoButton.Hide()
+}
+
+func (ilc *ItemListController) showError(err error) {
+	if window := ilc.getWindow(); window != nil {
+		dialog.ShowError(err, window)
+	}
+}
+
+// EditContent opens an editor for the content of a text item. Saving stores
+// the previous content as a revision.
+func (ilc *ItemListController) EditContent(id int64) {
+	window := ilc.getWindow()
+	if window == nil {
+		return
+	}
+
+	go func() {
+		ctx := context.Background()
+		item, err := ilc.repository.GetItemByID(ctx, id)
+		if err != nil {
+			fyne.Do(func() {
+				ilc.showError(fmt.Errorf("failed to load item: %w", err))
+			})
+			return
+		}
+
+		fyne.Do(func() {
+			entry := widget.NewMultiLineEntry()
+			entry.SetText(item.Content)
+			entry.Wrapping = fyne.TextWrapWord
+			entry.SetMinRowsVisible(12)
+
+			content := container.NewBorder(
+				widget.NewLabel("Edit the content of this item. The previous version is kept in its revision history."),
+				nil, nil, nil,
+				entry,
+			)
+
+			editor := dialog.NewCustomConfirm("Edit Content", "Save", "Cancel", content, func(confirmed bool) {
+				if !confirmed || entry.Text == item.Content {
+					return
+				}
+
+				ilc.saveContent(id, item.Content, entry.Text, false)
+			}, window)
+			editor.Resize(fyne.NewSize(700, 450))
+			editor.Show()
+		})
+	}()
+}
+
+// saveContent stores edited content as an undoable action. A trashed item
+// with the same content is only deleted once the user agrees.
+func (ilc *ItemListController) saveContent(id int64, oldContent, newContent string, replaceTrashed bool) {
+	go func() {
+		ctx := context.Background()
+		err := ilc.repository.UpdateContent(ctx, id, newContent, replaceTrashed)
+		if errors.Is(err, database.ErrDuplicateInTrash) {
+			fyne.Do(func() {
+				confirmReplaceTrashed(ilc.getWindow(), func() {
+					ilc.saveContent(id, oldContent, newContent, true)
+				})
+			})
+			return
+		}
+		if err != nil {
+			fyne.Do(func() {
+				ilc.showError(fmt.Errorf("failed to save content: %w", err))
+			})
+			return
+		}
+
+		// Undo and redo never delete trashed items without asking
+		ilc.journal.Record(Action{
+			Description: "content edit",
+			Undo:        func(ctx context.Context) error { return ilc.repository.UpdateContent(ctx, id, oldContent, false) },
+			Redo:        func(ctx context.Context) error { return ilc.repository.UpdateContent(ctx, id, newContent, false) },
+		})
+
+		fyne.Do(func() {
+			ilc.statusLabel.SetText("Content updated")
+			ilc.offerUndo()
+			ilc.Refresh()
+		})
+	}()
+}
+
+// confirmReplaceTrashed asks whether the trashed item that already has the
+// content being saved may be deleted for good, and calls replace if so.
+func confirmReplaceTrashed(window fyne.Window, replace func()) {
+	if window == nil {
+		return
+	}
+
+	confirm := dialog.NewConfirm("Item in Trash",
+		"An item in the trash already has this content. Delete it for good and save?",
+		func(confirmed bool) {
+			if confirmed {
+				replace()
+			}
+		}, window)
+	confirm.SetConfirmText("Delete and Save")
+	confirm.Show()
+}
+
+// SaveNotes stores the notes of an item as an undoable action. onSaved is
+// called on the UI goroutine once the notes have been written.
+func (ilc *ItemListController) SaveNotes(id int64, oldNotes, newNotes string, onSaved func()) {
+	go func() {
+		ctx := context.Background()
+		if err := ilc.repository.UpdateNotes(ctx, id, newNotes); err != nil {
+			fyne.Do(func() {
+				ilc.showError(fmt.Errorf("failed to save notes: %w", err))
+			})
+			return
+		}
+
+		ilc.journal.Record(Action{
+			Description: "notes edit",
+			Undo:        func(ctx context.Context) error { return ilc.repository.UpdateNotes(ctx, id, oldNotes) },
+			Redo:        func(ctx context.Context) error { return ilc.repository.UpdateNotes(ctx, id, newNotes) },
+		})
+
+		fyne.Do(func() {
+			onSaved()
+			ilc.statusLabel.SetText("Notes saved")
+			ilc.offerUndo()
+			ilc.Refresh()
+		})
+	}()
+}
+
+// CopyText puts text on the clipboard without adding it to the history.
+func (ilc *ItemListController) CopyText(text string) {
+	if err := ilc.app.CopyTextToClipboard(text); err != nil {
+		ilc.showError(fmt.Errorf("failed to copy text: %w", err))
+		return
+	}
+	ilc.statusLabel.SetText("✓ Copied to clipboard")
+}
+
+// SaveNewItem adds text to the history as a new item, for example the
+// result of an action on another item.
+func (ilc *ItemListController) SaveNewItem(text string) {
+	ilc.SaveNewItems([]string{text})
+}
+
+// SaveNewItems adds texts to the history as new items, as one undoable
+// action. The first text ends up at the top of the list.
+func (ilc *ItemListController) SaveNewItems(texts []string) {
+	go func() {
+		ctx := context.Background()
+
+		// Save the last text first so the list shows them in order
+		var ids []int64
+		existing := 0
+		for i := len(texts) - 1; i >= 0; i-- {
+			item := &database.ClipboardItem{
+				Type:    "text",
+				Content: texts[i],
+				Size:    len(texts[i]),
+			}
+			if err := ilc.repository.SaveClipboardItem(ctx, item); err != nil {
+				fyne.Do(func() {
+					ilc.showError(fmt.Errorf("failed to save item: %w", err))
+					ilc.Refresh()
+				})
+				return
+			}
+
+			// An item with the same content is moved to the top instead
+			if item.ID == 0 {
+				existing++
+			} else {
+				ids = append(ids, item.ID)
+			}
+		}
+
+		if len(ids) > 0 {
+			ilc.journal.Record(Action{
+				Description: "new items",
+				Undo: func(ctx context.Context) error {
+					return ilc.repository.TrashItems(ctx, ids, database.DeleteReasonUser)
+				},
+				Redo: func(ctx context.Context) error { return ilc.repository.RestoreItems(ctx, ids) },
+			})
+		}
+
+		var status string
+		switch {
+		case len(texts) == 1 && existing == 1:
+			status = "Already in history, moved to the top"
+		case len(texts) == 1:
+			status = "Saved as a new item"
+		case existing > 0:
+			status = fmt.Sprintf("Saved %d new items, %d already in history", len(ids), existing)
+		default:
+			status = fmt.Sprintf("Saved %d new items", len(ids))
+		}
+
+		fyne.Do(func() {
+			ilc.statusLabel.SetText(status)
+			if len(ids) > 0 {
+				ilc.offerUndo()
+			}
+			ilc.Refresh()
+		})
+	}()
+}
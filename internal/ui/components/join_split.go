@@ -0,0 +1,295 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"clipboardpro/internal/database"
+	"clipboardpro/internal/transform"
+)
+
+// separatorChoice is a separator offered for joining or splitting items.
+// An empty value for splitting means runs of whitespace.
+type separatorChoice struct {
+	label string
+	value string
+}
+
+const customSeparator = "Custom…"
+
+var (
+	joinSeparators = []separatorChoice{
+		{"Newline", "\n"},
+		{"Blank line", "\n\n"},
+		{"Comma", ","},
+		{"Comma and space", ", "},
+		{"Tab", "\t"},
+		{"Space", " "},
+		{customSeparator, ""},
+	}
+	splitSeparators = []separatorChoice{
+		{"Lines", "\n"},
+		{"Comma", ","},
+		{"Semicolon", ";"},
+		{"Tab", "\t"},
+		{"Whitespace", ""},
+		{customSeparator, ""},
+	}
+)
+
+// createSeparatorSelect returns a choice of separators with an entry for a
+// custom one, which understands \n and \t. separator returns the current
+// choice.
+func createSeparatorSelect(choices []separatorChoice, onChanged func()) (fyne.CanvasObject, func() string) {
+	custom := widget.NewEntry()
+	custom.SetPlaceHolder(`Separator, e.g. " | " or \n---\n`)
+	custom.OnChanged = func(string) { onChanged() }
+	custom.Hide()
+
+	labels := make([]string, len(choices))
+	for i, choice := range choices {
+		labels[i] = choice.label
+	}
+
+	selected := choices[0]
+	choice := widget.NewSelect(labels, func(label string) {
+		for _, c := range choices {
+			if c.label == label {
+				selected = c
+			}
+		}
+		if selected.label == customSeparator {
+			custom.Show()
+		} else {
+			custom.Hide()
+		}
+		onChanged()
+	})
+	choice.SetSelectedIndex(0)
+
+	separator := func() string {
+		if selected.label == customSeparator {
+			return transform.UnescapeSeparator(custom.Text)
+		}
+		return selected.value
+	}
+
+	return container.NewBorder(nil, nil, widget.NewLabel("Separator:"), nil, container.NewVBox(choice, custom)), separator
+}
+
+// ShowJoinDialog lets the user pick text items from the list, in order,
+// and join them into a new item. first is checked to begin with, if set.
+func (il *ItemList) ShowJoinDialog(first *database.ClipboardItem) {
+	window := il.getWindow()
+	if window == nil {
+		return
+	}
+
+	var candidates []*database.ClipboardItem
+	for _, item := range il.controller.GetItems() {
+		if item.Type == "text" {
+			candidates = append(candidates, item)
+		}
+	}
+	if len(candidates) < 2 {
+		dialog.ShowInformation("Join Items", "There are not enough text items in the list to join.", window)
+		return
+	}
+
+	// The order in which items are checked is the order they are joined in
+	var order []int64
+	if first != nil && first.Type == "text" {
+		order = append(order, first.ID)
+	}
+
+	summary := widget.NewLabel("")
+	joinButton := widget.NewButtonWithIcon("Join as New Item", theme.ContentAddIcon(), nil)
+	joinButton.Importance = widget.HighImportance
+
+	var separatorSelect fyne.CanvasObject
+	var separator func() string
+	var list *widget.List
+	update := func() {
+		summary.SetText(fmt.Sprintf("%d items selected. Items are joined in the order they were checked.", len(order)))
+		if len(order) < 2 || separator == nil {
+			joinButton.Disable()
+		} else {
+			joinButton.Enable()
+		}
+		if list != nil {
+			list.Refresh()
+		}
+	}
+	separatorSelect, separator = createSeparatorSelect(joinSeparators, update)
+
+	list = widget.NewList(
+		func() int { return len(candidates) },
+		func() fyne.CanvasObject {
+			position := widget.NewLabel("")
+			position.TextStyle = fyne.TextStyle{Monospace: true}
+			title := widget.NewLabel("")
+			title.Truncation = fyne.TextTruncateEllipsis
+			return container.NewBorder(nil, nil, container.NewHBox(widget.NewCheck("", nil), position), nil, title)
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			item := candidates[id]
+			row := obj.(*fyne.Container)
+			title := row.Objects[0].(*widget.Label)
+			left := row.Objects[1].(*fyne.Container)
+			check := left.Objects[0].(*widget.Check)
+			position := left.Objects[1].(*widget.Label)
+
+			title.SetText(il.getItemTitle(item))
+			index := slices.Index(order, item.ID)
+			if index >= 0 {
+				position.SetText(fmt.Sprintf("%2d", index+1))
+			} else {
+				position.SetText("  ")
+			}
+
+			check.OnChanged = nil
+			check.SetChecked(index >= 0)
+			check.OnChanged = func(checked bool) {
+				order = slices.DeleteFunc(order, func(id int64) bool { return id == item.ID })
+				if checked {
+					order = append(order, item.ID)
+				}
+				update()
+			}
+		},
+	)
+
+	reverseButton := widget.NewButtonWithIcon("Reverse Order", theme.MoveDownIcon(), func() {
+		slices.Reverse(order)
+		update()
+	})
+	clearButton := widget.NewButtonWithIcon("Clear", theme.ContentClearIcon(), func() {
+		order = nil
+		update()
+	})
+
+	content := container.NewBorder(
+		container.NewVBox(separatorSelect, container.NewHBox(reverseButton, clearButton), summary),
+		container.NewHBox(joinButton),
+		nil, nil,
+		list,
+	)
+
+	d := dialog.NewCustom("Join Items", "Close", content, window)
+	joinButton.OnTapped = func() {
+		ids := slices.Clone(order)
+		sep := separator()
+		d.Hide()
+		il.joinItems(ids, sep)
+	}
+	update()
+	d.Resize(fyne.NewSize(600, 550))
+	d.Show()
+}
+
+// joinItems saves the full contents of the items, joined by separator, as
+// a new item.
+func (il *ItemList) joinItems(ids []int64, separator string) {
+	go func() {
+		ctx := context.Background()
+		parts := make([]string, len(ids))
+		for i, id := range ids {
+			item, err := il.controller.repository.GetItemByID(ctx, id)
+			if err != nil {
+				fyne.Do(func() {
+					il.controller.showError(fmt.Errorf("failed to load item: %w", err))
+				})
+				return
+			}
+			parts[i] = item.Content
+		}
+
+		il.controller.SaveNewItem(strings.Join(parts, separator))
+	}()
+}
+
+// ShowSplitDialog splits a text item into new items at a separator.
+func (il *ItemList) ShowSplitDialog(item *database.ClipboardItem) {
+	il.withFullItem(item, func(item *database.ClipboardItem, window fyne.Window) {
+		trim := widget.NewCheck("Trim whitespace", nil)
+		trim.SetChecked(true)
+		skipEmpty := widget.NewCheck("Skip empty parts", nil)
+		skipEmpty.SetChecked(true)
+
+		preview := widget.NewLabel("")
+		preview.Wrapping = fyne.TextWrapWord
+		preview.TextStyle = fyne.TextStyle{Monospace: true}
+		summary := widget.NewLabel("")
+
+		splitButton := widget.NewButtonWithIcon("Split into New Items", theme.ContentAddIcon(), nil)
+		splitButton.Importance = widget.HighImportance
+
+		var parts []string
+		var separator func() string
+		update := func() {
+			if separator == nil {
+				return
+			}
+			parts = transform.Split(item.Content, separator(), trim.Checked, skipEmpty.Checked)
+			summary.SetText(fmt.Sprintf("%d parts", len(parts)))
+
+			const previewParts = 20
+			var lines []string
+			for i, part := range parts[:min(len(parts), previewParts)] {
+				lines = append(lines, fmt.Sprintf("%2d  %s", i+1, truncateText(strings.ReplaceAll(part, "\n", " "), 80)))
+			}
+			if len(parts) > previewParts {
+				lines = append(lines, fmt.Sprintf("… and %d more", len(parts)-previewParts))
+			}
+			preview.SetText(strings.Join(lines, "\n"))
+
+			if len(parts) < 2 {
+				splitButton.Disable()
+			} else {
+				splitButton.Enable()
+			}
+		}
+		trim.OnChanged = func(bool) { update() }
+		skipEmpty.OnChanged = func(bool) { update() }
+
+		var separatorSelect fyne.CanvasObject
+		separatorSelect, separator = createSeparatorSelect(splitSeparators, update)
+
+		content := container.NewBorder(
+			container.NewVBox(separatorSelect, container.NewHBox(trim, skipEmpty), summary),
+			container.NewHBox(splitButton),
+			nil, nil,
+			container.NewScroll(preview),
+		)
+
+		d := dialog.NewCustom("Split Item", "Close", content, window)
+		splitButton.OnTapped = func() {
+			if len(parts) < 2 {
+				dialog.ShowError(errors.New("the item does not split into several parts"), window)
+				return
+			}
+			d.Hide()
+			il.controller.SaveNewItems(parts)
+		}
+		update()
+		d.Resize(fyne.NewSize(600, 500))
+		d.Show()
+	})
+}
+
+func truncateText(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "…"
+}
@@ -0,0 +1,82 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	ErrNothingToUndo = errors.New("nothing to undo")
+	ErrNothingToRedo = errors.New("nothing to redo")
+)
+
+// Action is a reversible list operation recorded in an ActionJournal.
+type Action struct {
+	Description string
+	Undo        func(ctx context.Context) error
+	Redo        func(ctx context.Context) error
+}
+
+// ActionJournal keeps bounded undo and redo stacks of list actions.
+type ActionJournal struct {
+	mu    sync.Mutex
+	undo  []Action
+	redo  []Action
+	limit int
+}
+
+func NewActionJournal(limit int) *ActionJournal {
+	return &ActionJournal{limit: limit}
+}
+
+// Record adds an action that has just been performed. Recording a new action
+// discards everything that could previously be redone.
+func (j *ActionJournal) Record(action Action) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.undo = append(j.undo, action)
+	if len(j.undo) > j.limit {
+		j.undo = j.undo[len(j.undo)-j.limit:]
+	}
+	j.redo = nil
+}
+
+// Undo reverts the most recent action and returns its description.
+func (j *ActionJournal) Undo(ctx context.Context) (string, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if len(j.undo) == 0 {
+		return "", ErrNothingToUndo
+	}
+
+	action := j.undo[len(j.undo)-1]
+	if err := action.Undo(ctx); err != nil {
+		return action.Description, err
+	}
+
+	j.undo = j.undo[:len(j.undo)-1]
+	j.redo = append(j.redo, action)
+	return action.Description, nil
+}
+
+// Redo re-applies the most recently undone action and returns its description.
+func (j *ActionJournal) Redo(ctx context.Context) (string, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if len(j.redo) == 0 {
+		return "", ErrNothingToRedo
+	}
+
+	action := j.redo[len(j.redo)-1]
+	if err := action.Redo(ctx); err != nil {
+		return action.Description, err
+	}
+
+	j.redo = j.redo[:len(j.redo)-1]
+	j.undo = append(j.undo, action)
+	return action.Description, nil
+}
@@ -0,0 +1,104 @@
+package components
+
+import (
+	"errors"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"clipboardpro/internal/vault"
+)
+
+// LockScreen replaces the main window contents while the app is locked and
+// asks for the PIN or passphrase.
+type LockScreen struct {
+	verify       func(secret string) error // Callback to check the PIN or passphrase
+	onUnlocked   func()                    // Callback once the right secret was entered
+	entry        *widget.Entry
+	unlockButton *widget.Button
+	messageLabel *widget.Label
+	content      fyne.CanvasObject
+}
+
+func NewLockScreen(verify func(string) error, onUnlocked func()) *LockScreen {
+	ls := &LockScreen{
+		verify:     verify,
+		onUnlocked: onUnlocked,
+	}
+
+	ls.entry = widget.NewPasswordEntry()
+	ls.entry.SetPlaceHolder("PIN or passphrase")
+	ls.entry.OnSubmitted = func(string) { ls.submit() }
+
+	ls.unlockButton = widget.NewButtonWithIcon("Unlock", theme.LoginIcon(), ls.submit)
+	ls.unlockButton.Importance = widget.HighImportance
+
+	ls.messageLabel = widget.NewLabel("")
+	ls.messageLabel.Importance = widget.DangerImportance
+	ls.messageLabel.Alignment = fyne.TextAlignCenter
+	ls.messageLabel.Hide()
+
+	icon := widget.NewIcon(theme.VisibilityOffIcon())
+
+	form := container.NewVBox(
+		container.NewCenter(container.NewGridWrap(fyne.NewSize(64, 64), icon)),
+		widget.NewLabelWithStyle("ClipBoard Pro is locked", fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle("Clipboard capture continues in the background.", fyne.TextAlignCenter, fyne.TextStyle{Italic: true}),
+		container.NewGridWrap(fyne.NewSize(300, ls.entry.MinSize().Height), ls.entry),
+		ls.messageLabel,
+		container.NewCenter(ls.unlockButton),
+	)
+	ls.content = container.NewCenter(form)
+
+	return ls
+}
+
+func (ls *LockScreen) Create() fyne.CanvasObject {
+	return ls.content
+}
+
+// Reset clears the screen for the next time the app locks and focuses the
+// entry.
+func (ls *LockScreen) Reset(canvas fyne.Canvas) {
+	ls.entry.SetText("")
+	ls.messageLabel.Hide()
+	ls.entry.Enable()
+	ls.unlockButton.Enable()
+	canvas.Focus(ls.entry)
+}
+
+func (ls *LockScreen) submit() {
+	if ls.entry.Disabled() {
+		return
+	}
+
+	secret := ls.entry.Text
+	ls.entry.Disable()
+	ls.unlockButton.Disable()
+	ls.messageLabel.Hide()
+
+	// Checking the secret takes a moment by design
+	go func() {
+		err := ls.verify(secret)
+
+		fyne.Do(func() {
+			ls.entry.Enable()
+			ls.unlockButton.Enable()
+
+			if err != nil {
+				if errors.Is(err, vault.ErrWrongPassphrase) {
+					ls.messageLabel.SetText("Wrong PIN or passphrase")
+				} else {
+					ls.messageLabel.SetText(err.Error())
+				}
+				ls.messageLabel.Show()
+				ls.entry.SetText("")
+				return
+			}
+
+			ls.onUnlocked()
+		})
+	}()
+}
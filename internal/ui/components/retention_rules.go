@@ -0,0 +1,194 @@
+package components
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"clipboardpro/internal/config"
+)
+
+// ruleTypes maps the type choices shown for a rule to item types.
+var ruleTypes = []struct {
+	label    string
+	itemType string
+}{
+	{"All types", ""},
+	{"Text", "text"},
+	{"Images", "image"},
+}
+
+// RetentionRulesEditor edits the list of retention rules in the settings.
+type RetentionRulesEditor struct {
+	rows      []*retentionRuleRow
+	container *fyne.Container
+}
+
+type retentionRuleRow struct {
+	typeSelect     *widget.Select
+	tagEntry       *widget.Entry
+	sourceAppEntry *widget.Entry
+	sensitiveCheck *widget.Check
+	ageEntry       *widget.Entry
+	countEntry     *widget.Entry
+	sizeEntry      *widget.Entry
+}
+
+func NewRetentionRulesEditor(rules []config.RetentionRule) *RetentionRulesEditor {
+	re := &RetentionRulesEditor{container: container.NewVBox()}
+	for _, rule := range rules {
+		re.rows = append(re.rows, newRetentionRuleRow(rule))
+	}
+	re.refresh()
+	return re
+}
+
+func (re *RetentionRulesEditor) Create() fyne.CanvasObject {
+	addButton := widget.NewButtonWithIcon("Add Rule", theme.ContentAddIcon(), func() {
+		re.rows = append(re.rows, newRetentionRuleRow(config.RetentionRule{}))
+		re.refresh()
+	})
+	addButton.Importance = widget.LowImportance
+
+	return container.NewVBox(re.container, container.NewHBox(addButton))
+}
+
+// Rules returns the rules as currently entered.
+func (re *RetentionRulesEditor) Rules() ([]config.RetentionRule, error) {
+	rules := make([]config.RetentionRule, 0, len(re.rows))
+	for i, row := range re.rows {
+		rule, err := row.rule()
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i+1, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (re *RetentionRulesEditor) refresh() {
+	grid := container.NewGridWithColumns(8,
+		widget.NewLabelWithStyle("Type", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle("Tag", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle("Copied from", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabel(""),
+		widget.NewLabelWithStyle("Max age (days)", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle("Max items", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle("Max size (MB)", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabel(""),
+	)
+
+	for _, row := range re.rows {
+		removeButton := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+			for i, r := range re.rows {
+				if r == row {
+					re.rows = append(re.rows[:i], re.rows[i+1:]...)
+					break
+				}
+			}
+			re.refresh()
+		})
+		removeButton.Importance = widget.LowImportance
+
+		grid.Add(row.typeSelect)
+		grid.Add(row.tagEntry)
+		grid.Add(row.sourceAppEntry)
+		grid.Add(row.sensitiveCheck)
+		grid.Add(row.ageEntry)
+		grid.Add(row.countEntry)
+		grid.Add(row.sizeEntry)
+		grid.Add(container.NewHBox(removeButton))
+	}
+
+	if len(re.rows) == 0 {
+		re.container.Objects = []fyne.CanvasObject{widget.NewLabel("No rules: items are only removed by the storage quota.")}
+	} else {
+		re.container.Objects = []fyne.CanvasObject{grid}
+	}
+	re.container.Refresh()
+}
+
+func newRetentionRuleRow(rule config.RetentionRule) *retentionRuleRow {
+	labels := make([]string, len(ruleTypes))
+	selected := ruleTypes[0].label
+	for i, t := range ruleTypes {
+		labels[i] = t.label
+		if t.itemType == rule.Type {
+			selected = t.label
+		}
+	}
+
+	row := &retentionRuleRow{
+		typeSelect:     widget.NewSelect(labels, nil),
+		tagEntry:       widget.NewEntry(),
+		sourceAppEntry: widget.NewEntry(),
+		sensitiveCheck: widget.NewCheck("Sensitive only", nil),
+		ageEntry:       newLimitEntry(rule.MaxAgeDays),
+		countEntry:     newLimitEntry(rule.MaxCount),
+		sizeEntry:      newLimitEntry(int(rule.MaxBytes / (1024 * 1024))),
+	}
+	row.typeSelect.SetSelected(selected)
+	row.tagEntry.SetPlaceHolder("Any tag")
+	row.tagEntry.SetText(rule.Tag)
+	row.sourceAppEntry.SetPlaceHolder("Any app")
+	row.sourceAppEntry.SetText(rule.SourceApp)
+	row.sensitiveCheck.SetChecked(rule.Sensitive)
+	return row
+}
+
+func (row *retentionRuleRow) rule() (config.RetentionRule, error) {
+	var rule config.RetentionRule
+	for _, t := range ruleTypes {
+		if t.label == row.typeSelect.Selected {
+			rule.Type = t.itemType
+		}
+	}
+	rule.Tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(row.tagEntry.Text), "#"))
+	rule.SourceApp = strings.TrimSpace(row.sourceAppEntry.Text)
+	rule.Sensitive = row.sensitiveCheck.Checked
+
+	var err error
+	if rule.MaxAgeDays, err = parseLimit(row.ageEntry.Text); err != nil {
+		return rule, fmt.Errorf("max age: %w", err)
+	}
+	if rule.MaxCount, err = parseLimit(row.countEntry.Text); err != nil {
+		return rule, fmt.Errorf("max items: %w", err)
+	}
+	sizeMB, err := parseLimit(row.sizeEntry.Text)
+	if err != nil {
+		return rule, fmt.Errorf("max size: %w", err)
+	}
+	rule.MaxBytes = int64(sizeMB) * 1024 * 1024
+	return rule, nil
+}
+
+// newLimitEntry returns an entry for an optional limit, left empty when
+// the limit is off.
+func newLimitEntry(value int) *widget.Entry {
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder("No limit")
+	if value > 0 {
+		entry.SetText(strconv.Itoa(value))
+	}
+	entry.Validator = func(text string) error {
+		_, err := parseLimit(text)
+		return err
+	}
+	return entry
+}
+
+func parseLimit(text string) (int, error) {
+	if text == "" {
+		return 0, nil
+	}
+	value, err := strconv.Atoi(text)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("must be a positive number")
+	}
+	return value, nil
+}
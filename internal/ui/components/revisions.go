@@ -0,0 +1,174 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"clipboardpro/internal/database"
+	"clipboardpro/internal/diff"
+)
+
+// RevisionsDialog shows the previous versions of a text item, the difference
+// between a selected version and the current content, and allows reverting.
+type RevisionsDialog struct {
+	repository   database.Store
+	itemList     *ItemList
+	itemID       int64
+	parent       fyne.Window
+	item         *database.ClipboardItem
+	revisions    []*database.ItemRevision
+	selected     int
+	list         *widget.List
+	diffText     *widget.RichText
+	revertButton *widget.Button
+	statusLabel  *widget.Label
+}
+
+func NewRevisionsDialog(repository database.Store, itemList *ItemList, itemID int64, parent fyne.Window) *RevisionsDialog {
+	rd := &RevisionsDialog{
+		repository:  repository,
+		itemList:    itemList,
+		itemID:      itemID,
+		parent:      parent,
+		selected:    -1,
+		diffText:    newDiffText(nil),
+		statusLabel: widget.NewLabel("Loading..."),
+	}
+
+	rd.revertButton = widget.NewButtonWithIcon("Revert to Selected", theme.ContentUndoIcon(), rd.revertSelected)
+	rd.revertButton.Disable()
+
+	rd.createList()
+	return rd
+}
+
+func (rd *RevisionsDialog) Show() {
+	split := container.NewHSplit(
+		rd.list,
+		container.NewScroll(rd.diffText),
+	)
+	split.Offset = 0.3
+
+	content := container.NewBorder(
+		rd.statusLabel,
+		container.NewHBox(rd.revertButton),
+		nil, nil,
+		split,
+	)
+
+	d := dialog.NewCustom("Revision History", "Close", content, rd.parent)
+	d.Resize(fyne.NewSize(800, 500))
+	d.Show()
+
+	rd.load()
+}
+
+func (rd *RevisionsDialog) createList() {
+	rd.list = widget.NewList(
+		func() int {
+			return len(rd.revisions)
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			if id >= len(rd.revisions) {
+				return
+			}
+			revision := rd.revisions[id]
+			obj.(*widget.Label).SetText(fmt.Sprintf("%s • %s",
+				rd.itemList.formatTimeAgo(revision.CreatedAt), rd.itemList.formatBytes(revision.Size)))
+		},
+	)
+
+	rd.list.OnSelected = func(id widget.ListItemID) {
+		if id >= len(rd.revisions) || rd.item == nil {
+			return
+		}
+		rd.selected = id
+		setDiffSegments(rd.diffText, diff.Lines(rd.revisions[id].Content, rd.item.Content))
+		rd.revertButton.Enable()
+	}
+}
+
+func (rd *RevisionsDialog) load() {
+	go func() {
+		ctx := context.Background()
+		item, err := rd.repository.GetItemByID(ctx, rd.itemID)
+		var revisions []*database.ItemRevision
+		if err == nil {
+			revisions, err = rd.repository.GetRevisions(ctx, rd.itemID)
+		}
+
+		fyne.Do(func() {
+			if err != nil {
+				rd.statusLabel.SetText("Error loading revisions")
+				dialog.ShowError(fmt.Errorf("failed to load revisions: %w", err), rd.parent)
+				return
+			}
+
+			rd.item = item
+			rd.revisions = revisions
+			rd.selected = -1
+			rd.revertButton.Disable()
+			rd.list.UnselectAll()
+			rd.list.Refresh()
+			setDiffSegments(rd.diffText, nil)
+
+			switch len(revisions) {
+			case 0:
+				rd.statusLabel.SetText("This item has not been edited")
+			case 1:
+				rd.statusLabel.SetText("1 previous version • select it to compare with the current content")
+			default:
+				rd.statusLabel.SetText(fmt.Sprintf("%d previous versions • select one to compare with the current content", len(revisions)))
+			}
+		})
+	}()
+}
+
+func (rd *RevisionsDialog) revertSelected() {
+	if rd.selected < 0 || rd.selected >= len(rd.revisions) {
+		return
+	}
+	revision := rd.revisions[rd.selected]
+
+	dialog.ShowConfirm("Revert Content",
+		"Replace the current content with the selected version? The current content will be kept as a revision.",
+		func(confirmed bool) {
+			if confirmed {
+				rd.revert(revision.ID, false)
+			}
+		}, rd.parent)
+}
+
+func (rd *RevisionsDialog) revert(revisionID int64, replaceTrashed bool) {
+	go func() {
+		ctx := context.Background()
+		err := rd.repository.RevertToRevision(ctx, revisionID, replaceTrashed)
+		if errors.Is(err, database.ErrDuplicateInTrash) {
+			fyne.Do(func() {
+				confirmReplaceTrashed(rd.parent, func() { rd.revert(revisionID, true) })
+			})
+			return
+		}
+		if err != nil {
+			fyne.Do(func() {
+				dialog.ShowError(fmt.Errorf("failed to revert: %w", err), rd.parent)
+			})
+			return
+		}
+
+		fyne.Do(func() {
+			rd.itemList.Refresh()
+			rd.load()
+		})
+	}()
+}
@@ -43,7 +43,7 @@ func (sb *SearchBar) Create() fyne.CanvasObject {
 
 func (sb *SearchBar) createSearchBar() {
 	sb.entry = widget.NewEntry()
-	sb.entry.SetPlaceHolder("Search clipboard history...")
+	sb.entry.SetPlaceHolder("Search clipboard history... (filter with type:url, type:code, tag:work)")
 
 	sb.clearButton = widget.NewButtonWithIcon("", theme.ContentClearIcon(), func() {
 		sb.Clear()
@@ -51,6 +51,8 @@ func (sb *SearchBar) createSearchBar() {
 	sb.clearButton.Hide() // Initially hidden
 
 	sb.entry.OnChanged = func(text string) {
+		sb.itemList.recordActivity()
+
 		// Show/hide clear button
 		if text == "" {
 			sb.clearButton.Hide()
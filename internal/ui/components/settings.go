@@ -8,21 +8,32 @@ import (
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 
 	"clipboardpro/internal/config"
+	"clipboardpro/internal/retention"
 )
 
 type SettingsDialog struct {
-	controller *SettingsController
-	config     *config.Config
-	parent     fyne.Window
+	controller       *SettingsController
+	config           *config.Config
+	parent           fyne.Window
+	onShowBackups    func()
+	onShowEncryption func()
+	onPreviewCleanup func(retention.Policy)
+	storage          *StoragePanel
 }
 
-func NewSettingsDialog(cfg *config.Config, parent fyne.Window, onSave func(*config.Config)) *SettingsDialog {
+func NewSettingsDialog(cfg *config.Config, parent fyne.Window, onSave func(*config.Config), onShowBackups, onShowEncryption func(),
+	onPreviewCleanup func(retention.Policy), storage *StoragePanel) *SettingsDialog {
 	sd := &SettingsDialog{
-		config: cfg,
-		parent: parent,
+		config:           cfg,
+		parent:           parent,
+		onShowBackups:    onShowBackups,
+		onShowEncryption: onShowEncryption,
+		onPreviewCleanup: onPreviewCleanup,
+		storage:          storage,
 	}
 	sd.controller = NewSettingsController(cfg, parent, onSave)
 	return sd
@@ -35,8 +46,10 @@ func (sd *SettingsDialog) Show() {
 }
 
 func (sd *SettingsDialog) createContent() fyne.CanvasObject {
-	maxItemsEntry := sd.createNumericEntry(strconv.Itoa(sd.config.MaxHistoryItems))
-	maxDaysEntry := sd.createNumericEntry(strconv.Itoa(sd.config.MaxHistoryDays))
+	rulesEditor := NewRetentionRulesEditor(sd.config.RetentionRules)
+	quotaEntry := sd.createNumericEntry(strconv.Itoa(sd.config.StorageQuotaMB))
+	trashDaysEntry := sd.createNumericEntry(strconv.Itoa(sd.config.TrashRetentionDays))
+	expiryEditor := NewExpiryRulesEditor(sd.config.ExpiryRules)
 
 	darkModeCheck := sd.createCheckbox("Use dark theme", sd.config.DarkMode)
 
@@ -44,13 +57,30 @@ func (sd *SettingsDialog) createContent() fyne.CanvasObject {
 	checkUpdatesOnStartupCheck := sd.createCheckbox("Check for updates on startup", sd.config.CheckUpdatesOnStartup)
 	autoDownloadUpdatesCheck := sd.createCheckbox("Automatically download updates", sd.config.AutoDownloadUpdates)
 
+	// Backup settings
+	backupEnabledCheck := sd.createCheckbox("Back up history automatically", sd.config.BackupEnabled)
+	backupIntervalEntry := sd.createNumericEntry(strconv.Itoa(sd.config.BackupIntervalHours))
+	backupKeepCountEntry := sd.createNumericEntry(strconv.Itoa(sd.config.BackupKeepCount))
+	backupKeepDaysEntry := sd.createNumericEntry(strconv.Itoa(sd.config.BackupKeepDays))
+
+	// Lock settings
+	lockEnabledCheck := sd.createCheckbox("Lock with a PIN or passphrase", sd.config.LockEnabled)
+	lockIdleEntry := sd.createNumericEntry(strconv.Itoa(sd.config.LockIdleMinutes))
+	lockOnFocusLossCheck := sd.createCheckbox("Lock when the window loses focus", sd.config.LockOnFocusLoss)
+
 	tabs := container.NewAppTabs(
-		sd.createStorageTab(maxItemsEntry, maxDaysEntry),
+		sd.createRetentionTab(rulesEditor, quotaEntry, trashDaysEntry),
+		sd.createExpiryTab(expiryEditor),
+		container.NewTabItem("Storage", sd.storage.Create()),
+		sd.createBackupsTab(backupEnabledCheck, backupIntervalEntry, backupKeepCountEntry, backupKeepDaysEntry),
+		sd.createSecurityTab(lockEnabledCheck, lockIdleEntry, lockOnFocusLossCheck),
 		sd.createAppearanceTab(darkModeCheck),
 		sd.createUpdatesTab(checkUpdatesOnStartupCheck, autoDownloadUpdatesCheck),
 	)
 
-	saveButton := sd.createSaveButton(maxItemsEntry, maxDaysEntry, darkModeCheck, checkUpdatesOnStartupCheck, autoDownloadUpdatesCheck)
+	saveButton := sd.createSaveButton(rulesEditor, quotaEntry, trashDaysEntry, expiryEditor, darkModeCheck, checkUpdatesOnStartupCheck, autoDownloadUpdatesCheck,
+		backupEnabledCheck, backupIntervalEntry, backupKeepCountEntry, backupKeepDaysEntry,
+		lockEnabledCheck, lockIdleEntry, lockOnFocusLossCheck)
 	resetButton := sd.createResetButton()
 
 	buttonContainer := container.NewHBox(
@@ -89,19 +119,112 @@ func (sd *SettingsDialog) createCheckbox(text string, checked bool) *widget.Chec
 	return check
 }
 
-func (sd *SettingsDialog) createStorageTab(maxItemsEntry, maxDaysEntry *widget.Entry) *container.TabItem {
+func (sd *SettingsDialog) createRetentionTab(rulesEditor *RetentionRulesEditor, quotaEntry, trashDaysEntry *widget.Entry) *container.TabItem {
+	rulesInfo := widget.NewLabel("Unpinned items older or beyond the limits of the first rule matching their type are moved to the trash. " +
+		"The storage quota then removes the oldest unpinned items until the history fits.")
+	rulesInfo.Wrapping = fyne.TextWrapWord
+
 	storageForm := &widget.Form{
 		Items: []*widget.FormItem{
-			widget.NewFormItem("Maximum items to keep", maxItemsEntry),
-			widget.NewFormItem("Delete items older than (days)", maxDaysEntry),
+			widget.NewFormItem("Storage quota (MB, 0 = none)", quotaEntry),
+			widget.NewFormItem("Empty trash after (days)", trashDaysEntry),
 		},
 	}
-	return container.NewTabItem("Storage", container.NewVBox(
-		widget.NewLabelWithStyle("Clipboard History Storage", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+
+	previewButton := widget.NewButtonWithIcon("Preview Cleanup...", theme.SearchIcon(), func() {
+		sd.controller.PreviewCleanup(rulesEditor, quotaEntry, sd.onPreviewCleanup)
+	})
+
+	return container.NewTabItem("Retention", container.NewVBox(
+		widget.NewLabelWithStyle("Retention Rules", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		rulesInfo,
+		rulesEditor.Create(),
 		storageForm,
+		container.NewHBox(previewButton),
+	))
+}
+
+func (sd *SettingsDialog) createExpiryTab(expiryEditor *ExpiryRulesEditor) *container.TabItem {
+	info := widget.NewLabel("Newly captured items matching a rule expire after the given time or once copied, " +
+		"and are then deleted for good without going through the trash. Patterns are regular expressions " +
+		"matched against the text, such as ^\\d{6}$ for one-time codes. The first matching rule applies.")
+	info.Wrapping = fyne.TextWrapWord
+
+	return container.NewTabItem("Expiry", container.NewVBox(
+		widget.NewLabelWithStyle("Expiry Rules", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		info,
+		expiryEditor.Create(),
+	))
+}
+
+func (sd *SettingsDialog) createBackupsTab(backupEnabledCheck *widget.Check, backupIntervalEntry, backupKeepCountEntry, backupKeepDaysEntry *widget.Entry) *container.TabItem {
+	backupForm := &widget.Form{
+		Items: []*widget.FormItem{
+			widget.NewFormItem("", backupEnabledCheck),
+			widget.NewFormItem("Back up every (hours)", backupIntervalEntry),
+			widget.NewFormItem("Backups to keep", backupKeepCountEntry),
+			widget.NewFormItem("Delete backups older than (days, 0 = never)", backupKeepDaysEntry),
+		},
+	}
+
+	manageButton := widget.NewButtonWithIcon("Manage Backups...", theme.StorageIcon(), sd.onShowBackups)
+
+	return container.NewTabItem("Backups", container.NewVBox(
+		widget.NewLabelWithStyle("Database Backups", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		backupForm,
+		container.NewHBox(manageButton),
+	))
+}
+
+func (sd *SettingsDialog) createSecurityTab(lockEnabledCheck *widget.Check, lockIdleEntry *widget.Entry, lockOnFocusLossCheck *widget.Check) *container.TabItem {
+	lockForm := &widget.Form{
+		Items: []*widget.FormItem{
+			widget.NewFormItem("", lockEnabledCheck),
+			widget.NewFormItem("Lock after idle (minutes, 0 = never)", lockIdleEntry),
+			widget.NewFormItem("", lockOnFocusLossCheck),
+		},
+	}
+
+	secretButton := widget.NewButtonWithIcon("Set PIN or Passphrase...", theme.LoginIcon(), sd.showSetLockSecret)
+
+	lockInfo := widget.NewLabel("The lock screen hides your history. Clipboard capture keeps running while it is locked.")
+	lockInfo.Wrapping = fyne.TextWrapWord
+
+	infoText := widget.NewLabel("Encrypt your clipboard history with a passphrase so it cannot be read from disk without it.")
+	infoText.Wrapping = fyne.TextWrapWord
+
+	encryptionButton := widget.NewButtonWithIcon("Manage Encryption...", theme.AccountIcon(), sd.onShowEncryption)
+
+	return container.NewTabItem("Security", container.NewVBox(
+		widget.NewLabelWithStyle("App Lock", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		lockInfo,
+		lockForm,
+		container.NewHBox(secretButton),
+		widget.NewSeparator(),
+		widget.NewLabelWithStyle("Encryption at Rest", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		infoText,
+		container.NewHBox(encryptionButton),
 	))
 }
 
+func (sd *SettingsDialog) showSetLockSecret() {
+	secretEntry := widget.NewPasswordEntry()
+	confirmEntry := widget.NewPasswordEntry()
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("PIN or passphrase", secretEntry),
+		widget.NewFormItem("Confirm", confirmEntry),
+	}
+
+	form := dialog.NewForm("Set PIN or Passphrase", "Set", "Cancel", items, func(confirmed bool) {
+		if confirmed {
+			sd.controller.SetLockSecret(secretEntry.Text, confirmEntry.Text)
+		}
+	}, sd.parent)
+	form.Resize(fyne.NewSize(400, form.MinSize().Height))
+	form.Show()
+}
+
 func (sd *SettingsDialog) createAppearanceTab(darkModeCheck *widget.Check) *container.TabItem {
 	appearanceForm := &widget.Form{
 		Items: []*widget.FormItem{
@@ -134,9 +257,13 @@ func (sd *SettingsDialog) createUpdatesTab(checkUpdatesOnStartupCheck, autoDownl
 	))
 }
 
-func (sd *SettingsDialog) createSaveButton(maxItemsEntry, maxDaysEntry *widget.Entry, darkModeCheck, checkUpdatesOnStartupCheck, autoDownloadUpdatesCheck *widget.Check) *widget.Button {
+func (sd *SettingsDialog) createSaveButton(rulesEditor *RetentionRulesEditor, quotaEntry, trashDaysEntry *widget.Entry, expiryEditor *ExpiryRulesEditor, darkModeCheck, checkUpdatesOnStartupCheck, autoDownloadUpdatesCheck,
+	backupEnabledCheck *widget.Check, backupIntervalEntry, backupKeepCountEntry, backupKeepDaysEntry *widget.Entry,
+	lockEnabledCheck *widget.Check, lockIdleEntry *widget.Entry, lockOnFocusLossCheck *widget.Check) *widget.Button {
 	saveButton := widget.NewButton("Save Settings", func() {
-		sd.controller.SaveSettings(maxItemsEntry, maxDaysEntry, darkModeCheck, checkUpdatesOnStartupCheck, autoDownloadUpdatesCheck)
+		sd.controller.SaveSettings(rulesEditor, quotaEntry, trashDaysEntry, expiryEditor, darkModeCheck, checkUpdatesOnStartupCheck, autoDownloadUpdatesCheck,
+			backupEnabledCheck, backupIntervalEntry, backupKeepCountEntry, backupKeepDaysEntry,
+			lockEnabledCheck, lockIdleEntry, lockOnFocusLossCheck)
 	})
 	saveButton.Importance = widget.HighImportance
 	return saveButton
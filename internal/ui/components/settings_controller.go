@@ -1,6 +1,8 @@
 package components
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
 
 	"fyne.io/fyne/v2"
@@ -8,6 +10,8 @@ import (
 	"fyne.io/fyne/v2/widget"
 
 	"clipboardpro/internal/config"
+	"clipboardpro/internal/retention"
+	"clipboardpro/internal/vault"
 )
 
 type SettingsController struct {
@@ -24,30 +28,129 @@ func NewSettingsController(cfg *config.Config, parent fyne.Window, onSave func(*
 	}
 }
 
-func (sc *SettingsController) SaveSettings(maxItemsEntry, maxDaysEntry *widget.Entry, darkModeCheck, checkUpdatesOnStartupCheck, autoDownloadUpdatesCheck *widget.Check) {
+func (sc *SettingsController) SaveSettings(rulesEditor *RetentionRulesEditor, quotaEntry, trashDaysEntry *widget.Entry, expiryEditor *ExpiryRulesEditor, darkModeCheck, checkUpdatesOnStartupCheck, autoDownloadUpdatesCheck,
+	backupEnabledCheck *widget.Check, backupIntervalEntry, backupKeepCountEntry, backupKeepDaysEntry *widget.Entry,
+	lockEnabledCheck *widget.Check, lockIdleEntry *widget.Entry, lockOnFocusLossCheck *widget.Check) {
 	// Validate inputs
-	maxItems, err := strconv.Atoi(maxItemsEntry.Text)
+	rules, err := rulesEditor.Rules()
 	if err != nil {
 		dialog.ShowError(err, sc.parent)
 		return
 	}
 
-	maxDays, err := strconv.Atoi(maxDaysEntry.Text)
+	quota, err := strconv.Atoi(quotaEntry.Text)
 	if err != nil {
 		dialog.ShowError(err, sc.parent)
 		return
 	}
 
+	expiryRules, err := expiryEditor.Rules()
+	if err != nil {
+		dialog.ShowError(err, sc.parent)
+		return
+	}
+
+	trashDays, err := strconv.Atoi(trashDaysEntry.Text)
+	if err != nil {
+		dialog.ShowError(err, sc.parent)
+		return
+	}
+
+	backupInterval, err := strconv.Atoi(backupIntervalEntry.Text)
+	if err != nil {
+		dialog.ShowError(err, sc.parent)
+		return
+	}
+
+	backupKeepCount, err := strconv.Atoi(backupKeepCountEntry.Text)
+	if err != nil {
+		dialog.ShowError(err, sc.parent)
+		return
+	}
+
+	backupKeepDays, err := strconv.Atoi(backupKeepDaysEntry.Text)
+	if err != nil {
+		dialog.ShowError(err, sc.parent)
+		return
+	}
+
+	lockIdle, err := strconv.Atoi(lockIdleEntry.Text)
+	if err != nil {
+		dialog.ShowError(err, sc.parent)
+		return
+	}
+
+	if lockEnabledCheck.Checked && sc.config.LockSecretHash == "" {
+		dialog.ShowError(errors.New("set a PIN or passphrase before turning on the lock"), sc.parent)
+		return
+	}
+
 	// Create new config
 	newConfig := &config.Config{}
 	*newConfig = *sc.config
 
-	newConfig.MaxHistoryItems = maxItems
-	newConfig.MaxHistoryDays = maxDays
+	newConfig.RetentionRules = rules
+	newConfig.StorageQuotaMB = quota
+	newConfig.TrashRetentionDays = trashDays
+	newConfig.ExpiryRules = expiryRules
 	newConfig.DarkMode = darkModeCheck.Checked
 	newConfig.CheckUpdatesOnStartup = checkUpdatesOnStartupCheck.Checked
 	newConfig.AutoDownloadUpdates = autoDownloadUpdatesCheck.Checked
+	newConfig.BackupEnabled = backupEnabledCheck.Checked
+	newConfig.BackupIntervalHours = backupInterval
+	newConfig.BackupKeepCount = backupKeepCount
+	newConfig.BackupKeepDays = backupKeepDays
+	newConfig.LockEnabled = lockEnabledCheck.Checked
+	newConfig.LockIdleMinutes = lockIdle
+	newConfig.LockOnFocusLoss = lockOnFocusLossCheck.Checked
+
+	sc.save(newConfig)
+}
+
+// PreviewCleanup shows what the next cleanup would remove with the rules
+// and quota as currently entered, before they are saved.
+func (sc *SettingsController) PreviewCleanup(rulesEditor *RetentionRulesEditor, quotaEntry *widget.Entry, onPreview func(retention.Policy)) {
+	rules, err := rulesEditor.Rules()
+	if err != nil {
+		dialog.ShowError(err, sc.parent)
+		return
+	}
+
+	quota, err := strconv.Atoi(quotaEntry.Text)
+	if err != nil {
+		dialog.ShowError(err, sc.parent)
+		return
+	}
+
+	onPreview(retention.Policy{Rules: rules, QuotaBytes: int64(max(quota, 0)) * 1024 * 1024})
+}
+
+// SetLockSecret stores the hash of a new PIN or passphrase for the lock
+// screen. Other settings are saved separately.
+func (sc *SettingsController) SetLockSecret(secret, confirmation string) {
+	if err := checkNewPassphrase(secret, confirmation); err != nil {
+		dialog.ShowError(err, sc.parent)
+		return
+	}
+
+	hash, err := vault.HashSecret(secret)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("failed to set PIN or passphrase: %w", err), sc.parent)
+		return
+	}
+
+	newConfig := &config.Config{}
+	*newConfig = *sc.config
+	newConfig.LockSecretHash = hash
+
+	sc.save(newConfig)
+	dialog.ShowInformation("Lock", "The PIN or passphrase was set.", sc.parent)
+}
 
+// save hands a new configuration to the application and keeps it, so
+// later changes made in the same dialog build on it.
+func (sc *SettingsController) save(newConfig *config.Config) {
+	sc.config = newConfig
 	sc.onSave(newConfig)
 }
 
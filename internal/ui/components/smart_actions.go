@@ -0,0 +1,277 @@
+package components
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"clipboardpro/internal/classify"
+	"clipboardpro/internal/database"
+)
+
+// SmartAction is a one-click action offered for items of a subtype, such as
+// opening a URL or pretty-printing JSON.
+type SmartAction struct {
+	Label string
+	Icon  fyne.Resource
+	// Run acts on the full content of a text item. It is called on the UI
+	// goroutine.
+	Run func(il *ItemList, item *database.ClipboardItem, window fyne.Window) error
+}
+
+// smartActions holds the registered actions by subtype, in the order they
+// are offered.
+var smartActions = map[string][]SmartAction{}
+
+// RegisterSmartAction offers an action for text items of a subtype.
+func RegisterSmartAction(subtype string, action SmartAction) {
+	smartActions[subtype] = append(smartActions[subtype], action)
+}
+
+// SmartActionsFor returns the actions offered for an item.
+func SmartActionsFor(item *database.ClipboardItem) []SmartAction {
+	if item.Type != "text" {
+		return nil
+	}
+	return smartActions[item.Subtype]
+}
+
+func init() {
+	RegisterSmartAction(classify.URL, SmartAction{"Open in Browser", theme.ComputerIcon(), openURL})
+	RegisterSmartAction(classify.Path, SmartAction{"Show in File Manager", theme.FolderOpenIcon(), showInFileManager})
+	RegisterSmartAction(classify.Email, SmartAction{"Compose Email", theme.MailComposeIcon(), composeEmail})
+	RegisterSmartAction(classify.Color, SmartAction{"Show Colour Swatch", theme.ColorPaletteIcon(), showColorSwatch})
+	RegisterSmartAction(classify.JSON, SmartAction{"Pretty-Print JSON", theme.ListIcon(), prettyPrintJSON})
+	RegisterSmartAction(classify.JWT, SmartAction{"Decode JWT", theme.VisibilityIcon(), decodeJWT})
+}
+
+// runSmartAction runs action on the full item.
+func (il *ItemList) runSmartAction(action SmartAction, item *database.ClipboardItem) {
+	il.withFullItem(item, func(item *database.ClipboardItem, window fyne.Window) {
+		if err := action.Run(il, item, window); err != nil {
+			dialog.ShowError(fmt.Errorf("%s failed: %w", action.Label, err), window)
+		}
+	})
+}
+
+// withFullItem calls fn on the UI goroutine with item, loading its full
+// content first if it is a list row.
+func (il *ItemList) withFullItem(item *database.ClipboardItem, fn func(*database.ClipboardItem, fyne.Window)) {
+	window := il.getWindow()
+	if window == nil {
+		return
+	}
+	if item.Content != "" {
+		fn(item, window)
+		return
+	}
+
+	go func() {
+		full, err := il.controller.repository.GetItemByID(context.Background(), item.ID)
+		fyne.Do(func() {
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("failed to load item: %w", err), window)
+				return
+			}
+			fn(full, window)
+		})
+	}()
+}
+
+// createSmartActionMenuItems returns the row menu entries of the actions
+// offered for an item.
+func (il *ItemList) createSmartActionMenuItems(item *database.ClipboardItem) []*fyne.MenuItem {
+	var menuItems []*fyne.MenuItem
+	for _, action := range SmartActionsFor(item) {
+		menuItem := fyne.NewMenuItem(action.Label, func() {
+			il.runSmartAction(action, item)
+		})
+		menuItem.Icon = action.Icon
+		menuItems = append(menuItems, menuItem)
+	}
+	return menuItems
+}
+
+// createSmartActionButtons returns a button for each action offered for a
+// fully loaded item, or nil if there are none.
+func (il *ItemList) createSmartActionButtons(item *database.ClipboardItem) fyne.CanvasObject {
+	actions := SmartActionsFor(item)
+	if len(actions) == 0 {
+		return nil
+	}
+
+	buttons := container.NewHBox()
+	for _, action := range actions {
+		buttons.Add(widget.NewButtonWithIcon(action.Label, action.Icon, func() {
+			il.runSmartAction(action, item)
+		}))
+	}
+	return buttons
+}
+
+func openURL(_ *ItemList, item *database.ClipboardItem, _ fyne.Window) error {
+	text := strings.TrimSpace(item.Content)
+	if strings.HasPrefix(strings.ToLower(text), "www.") {
+		text = "https://" + text
+	}
+
+	u, err := url.Parse(text)
+	if err != nil {
+		return err
+	}
+	return fyne.CurrentApp().OpenURL(u)
+}
+
+// showInFileManager opens the folder of a file, or the folder itself.
+func showInFileManager(_ *ItemList, item *database.ClipboardItem, _ fyne.Window) error {
+	path := strings.TrimSpace(item.Content)
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		path = filepath.Join(home, rest)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		path = filepath.Dir(path)
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	u, err := url.Parse(storage.NewFileURI(abs).String())
+	if err != nil {
+		return err
+	}
+	return fyne.CurrentApp().OpenURL(u)
+}
+
+func composeEmail(_ *ItemList, item *database.ClipboardItem, _ fyne.Window) error {
+	address := strings.TrimSpace(item.Content)
+	address = strings.TrimPrefix(strings.TrimPrefix(address, "mailto:"), "MAILTO:")
+	return fyne.CurrentApp().OpenURL(&url.URL{Scheme: "mailto", Opaque: address})
+}
+
+func showColorSwatch(il *ItemList, item *database.ClipboardItem, window fyne.Window) error {
+	c, err := classify.ParseColor(item.Content)
+	if err != nil {
+		return err
+	}
+	hex, rgb := classify.FormatColor(c)
+
+	swatch := canvas.NewRectangle(c)
+	swatch.SetMinSize(fyne.NewSize(240, 160))
+	swatch.StrokeColor = theme.Color(theme.ColorNameSeparator)
+	swatch.StrokeWidth = 1
+	swatch.CornerRadius = theme.InputRadiusSize()
+
+	copyButton := func(value string) *widget.Button {
+		return widget.NewButtonWithIcon(value, theme.ContentCopyIcon(), func() {
+			il.controller.CopyText(value)
+		})
+	}
+
+	content := container.NewVBox(swatch, container.NewGridWithColumns(2, copyButton(hex), copyButton(rgb)))
+	dialog.ShowCustom("Colour Swatch", "Close", content, window)
+	return nil
+}
+
+func prettyPrintJSON(il *ItemList, item *database.ClipboardItem, window fyne.Window) error {
+	var out bytes.Buffer
+	if err := json.Indent(&out, []byte(strings.TrimSpace(item.Content)), "", "  "); err != nil {
+		return err
+	}
+	il.showTextResult("Pretty-Printed JSON", out.String(), window)
+	return nil
+}
+
+func decodeJWT(il *ItemList, item *database.ClipboardItem, window fyne.Window) error {
+	header, payload, err := classify.DecodeJWT(item.Content)
+	if err != nil {
+		return err
+	}
+
+	var out strings.Builder
+	for _, part := range []struct {
+		name string
+		data []byte
+	}{{"Header", header}, {"Payload", payload}} {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, part.data, "", "  "); err != nil {
+			return err
+		}
+		fmt.Fprintf(&out, "// %s\n%s\n\n", part.name, indented.String())
+	}
+
+	// Spell out the standard time claims
+	var claims map[string]any
+	if json.Unmarshal(payload, &claims) == nil {
+		for _, claim := range []struct{ key, name string }{
+			{"iat", "Issued"}, {"nbf", "Not before"}, {"exp", "Expires"},
+		} {
+			if seconds, ok := claims[claim.key].(float64); ok {
+				fmt.Fprintf(&out, "// %s: %s\n", claim.name, time.Unix(int64(seconds), 0).Format(time.RFC1123))
+			}
+		}
+	}
+	out.WriteString("// The signature has not been verified.")
+
+	il.showTextResult("Decoded JWT", out.String(), window)
+	return nil
+}
+
+// showTextResult shows text produced from an item, with buttons to copy it
+// or save it as a new item.
+func (il *ItemList) showTextResult(title, text string, window fyne.Window) {
+	if text == "" {
+		dialog.ShowError(errors.New("the result is empty"), window)
+		return
+	}
+
+	result := widget.NewLabel(text)
+	result.Wrapping = fyne.TextWrapWord
+	result.TextStyle = fyne.TextStyle{Monospace: true}
+
+	var d dialog.Dialog
+	copyButton := widget.NewButtonWithIcon("Copy", theme.ContentCopyIcon(), func() {
+		il.controller.CopyText(text)
+		d.Hide()
+	})
+	saveButton := widget.NewButtonWithIcon("Save as New Item", theme.DocumentSaveIcon(), func() {
+		il.controller.SaveNewItem(text)
+		d.Hide()
+	})
+	saveButton.Importance = widget.HighImportance
+
+	content := container.NewBorder(
+		nil,
+		container.NewHBox(copyButton, saveButton),
+		nil, nil,
+		container.NewScroll(result),
+	)
+
+	d = dialog.NewCustom(title, "Close", content, window)
+	d.Resize(fyne.NewSize(650, 480))
+	d.Show()
+}
@@ -0,0 +1,162 @@
+package components
+
+import (
+	"fmt"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"clipboardpro/internal/database"
+)
+
+// StoragePanel shows how much space the database takes and what takes it,
+// and runs maintenance tasks on it.
+type StoragePanel struct {
+	controller  *StorageController
+	itemList    *ItemList
+	parent      fyne.Window
+	summary     *widget.Label
+	types       *fyne.Container
+	largest     *fyne.Container
+	progress    *widget.ProgressBarInfinite
+	statusLabel *widget.Label
+	buttons     []*widget.Button
+}
+
+func NewStoragePanel(repository database.Store, itemList *ItemList, pauseMonitor, resumeMonitor func(), parent fyne.Window) *StoragePanel {
+	sp := &StoragePanel{
+		itemList:    itemList,
+		parent:      parent,
+		summary:     widget.NewLabel("Loading..."),
+		types:       container.NewGridWithColumns(4),
+		largest:     container.NewVBox(),
+		progress:    widget.NewProgressBarInfinite(),
+		statusLabel: widget.NewLabel(""),
+	}
+	sp.summary.Wrapping = fyne.TextWrapWord
+	sp.progress.Hide()
+
+	sp.controller = NewStorageController(
+		repository,
+		sp.statusLabel,
+		sp.showStats,
+		sp.setBusy,
+		pauseMonitor,
+		resumeMonitor,
+		func() fyne.Window { return sp.parent },
+	)
+	return sp
+}
+
+// Create builds the panel and starts measuring the database.
+func (sp *StoragePanel) Create() fyne.CanvasObject {
+	sp.buttons = []*widget.Button{
+		sp.createTaskButton("Compact", theme.ContentCutIcon(), database.TaskVacuum),
+		sp.createTaskButton("Analyze", theme.SearchIcon(), database.TaskAnalyze),
+		sp.createTaskButton("Check Integrity", theme.ConfirmIcon(), database.TaskIntegrityCheck),
+		sp.createTaskButton("Rebuild Indexes", theme.ViewRefreshIcon(), database.TaskReindex),
+	}
+
+	refreshButton := widget.NewButtonWithIcon("", theme.ViewRefreshIcon(), sp.controller.LoadStats)
+	refreshButton.Importance = widget.LowImportance
+
+	taskButtons := container.NewHBox()
+	for _, button := range sp.buttons {
+		taskButtons.Add(button)
+	}
+
+	sp.controller.LoadStats()
+
+	return container.NewVBox(
+		container.NewBorder(nil, nil, nil, refreshButton,
+			widget.NewLabelWithStyle("Database", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})),
+		sp.summary,
+		sp.types,
+		widget.NewLabelWithStyle("Largest Items", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		sp.largest,
+		widget.NewSeparator(),
+		widget.NewLabelWithStyle("Maintenance", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		taskButtons,
+		sp.progress,
+		sp.statusLabel,
+	)
+}
+
+func (sp *StoragePanel) createTaskButton(label string, icon fyne.Resource, task database.MaintenanceTask) *widget.Button {
+	return widget.NewButtonWithIcon(label, icon, func() {
+		sp.controller.Run(task)
+	})
+}
+
+func (sp *StoragePanel) showStats() {
+	stats := sp.controller.GetStats()
+	if stats == nil {
+		return
+	}
+
+	location := stats.Path + " takes " + sp.itemList.formatBytes(int(stats.FileSize)) + " on disk"
+	if stats.Path == "" {
+		location = "The history is kept in memory only and takes " + sp.itemList.formatBytes(int(stats.FileSize))
+	}
+	sp.summary.SetText(fmt.Sprintf("%s, of which %s is free in %d unused pages.",
+		location, sp.itemList.formatBytes(int(stats.FreeBytes())), stats.FreePages))
+
+	bold := fyne.TextStyle{Bold: true}
+	sp.types.Objects = []fyne.CanvasObject{
+		widget.NewLabelWithStyle("Type", fyne.TextAlignLeading, bold),
+		widget.NewLabelWithStyle("Items", fyne.TextAlignLeading, bold),
+		widget.NewLabelWithStyle("In trash", fyne.TextAlignLeading, bold),
+		widget.NewLabelWithStyle("Size", fyne.TextAlignLeading, bold),
+	}
+	for _, t := range stats.Types {
+		sp.types.Add(widget.NewLabel(t.Type))
+		sp.types.Add(widget.NewLabel(strconv.Itoa(t.Items)))
+		sp.types.Add(widget.NewLabel(strconv.Itoa(t.Trashed)))
+		sp.types.Add(widget.NewLabel(sp.itemList.formatBytes(int(t.Bytes + t.TrashedBytes))))
+	}
+	sp.types.Add(widget.NewLabel("revisions"))
+	sp.types.Add(widget.NewLabel(strconv.Itoa(stats.Revisions)))
+	sp.types.Add(widget.NewLabel("-"))
+	sp.types.Add(widget.NewLabel(sp.itemList.formatBytes(int(stats.RevisionBytes))))
+	sp.types.Refresh()
+
+	sp.largest.Objects = nil
+	for _, item := range stats.Largest {
+		details := sp.itemList.formatBytes(item.Size)
+		if item.IsTrashed() {
+			details += " • in trash"
+		}
+
+		title := widget.NewLabel(sp.itemList.getItemTitle(item))
+		title.Truncation = fyne.TextTruncateEllipsis
+		sp.largest.Add(container.NewBorder(nil, nil,
+			widget.NewIcon(sp.itemList.getItemIcon(item)), widget.NewLabel(details), title))
+	}
+	if len(stats.Largest) == 0 {
+		sp.largest.Add(widget.NewLabel("The history is empty."))
+	}
+	sp.largest.Refresh()
+}
+
+// setBusy shows the progress bar while a task runs and keeps another from
+// being started.
+func (sp *StoragePanel) setBusy(busy bool) {
+	for _, button := range sp.buttons {
+		if busy {
+			button.Disable()
+		} else {
+			button.Enable()
+		}
+	}
+
+	if busy {
+		sp.progress.Show()
+		sp.progress.Start()
+	} else {
+		sp.progress.Stop()
+		sp.progress.Hide()
+	}
+}
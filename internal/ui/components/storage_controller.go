@@ -0,0 +1,128 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"clipboardpro/internal/database"
+)
+
+// largestItemsShown is how many of the largest items the storage tab lists.
+const largestItemsShown = 5
+
+type StorageController struct {
+	repository    database.Store
+	statusLabel   *widget.Label
+	stats         *database.StorageStats
+	busy          bool
+	onStats       func()             // Callback to show new statistics
+	onBusy        func(busy bool)    // Callback when a task starts or finishes
+	pauseMonitor  func()             // Callback to stop capturing the clipboard during a task
+	resumeMonitor func()             // Callback to start capturing again
+	getWindow     func() fyne.Window // Callback to get the parent window
+}
+
+func NewStorageController(repository database.Store, statusLabel *widget.Label, onStats func(), onBusy func(bool), pauseMonitor, resumeMonitor func(), getWindow func() fyne.Window) *StorageController {
+	return &StorageController{
+		repository:    repository,
+		statusLabel:   statusLabel,
+		onStats:       onStats,
+		onBusy:        onBusy,
+		pauseMonitor:  pauseMonitor,
+		resumeMonitor: resumeMonitor,
+		getWindow:     getWindow,
+	}
+}
+
+func (sc *StorageController) GetStats() *database.StorageStats {
+	return sc.stats
+}
+
+// LoadStats measures the database in the background.
+func (sc *StorageController) LoadStats() {
+	go func() {
+		stats, err := sc.repository.StorageStats(context.Background(), largestItemsShown)
+
+		fyne.Do(func() {
+			if err != nil {
+				sc.statusLabel.SetText("Error reading storage statistics")
+				sc.showError(err)
+				return
+			}
+
+			sc.stats = stats
+			sc.onStats()
+			if !sc.busy {
+				sc.statusLabel.SetText("")
+			}
+		})
+	}()
+}
+
+// Run performs a maintenance task in the background, with clipboard
+// capture paused so the task does not compete with new items for the
+// database.
+func (sc *StorageController) Run(task database.MaintenanceTask) {
+	if sc.busy {
+		return
+	}
+	sc.setBusy(true)
+	sc.statusLabel.SetText(task.Description() + "...")
+	sc.pauseMonitor()
+
+	go func() {
+		problems, err := sc.repository.RunMaintenance(context.Background(), task)
+
+		fyne.Do(func() {
+			sc.resumeMonitor()
+			sc.setBusy(false)
+			sc.LoadStats()
+
+			if err != nil {
+				sc.statusLabel.SetText(task.Description() + " failed")
+				sc.showError(err)
+				return
+			}
+
+			sc.statusLabel.SetText(task.Description() + " finished")
+			if task == database.TaskIntegrityCheck {
+				sc.showIntegrityResult(problems)
+			}
+		})
+	}()
+}
+
+func (sc *StorageController) showIntegrityResult(problems []string) {
+	window := sc.getWindow()
+	if window == nil {
+		return
+	}
+
+	if len(problems) == 0 {
+		dialog.ShowInformation("Integrity Check", "No problems were found in the database.", window)
+		return
+	}
+	shown := problems
+	if len(shown) > 10 {
+		shown = append(shown[:10:10], "...")
+	}
+	dialog.ShowInformation("Integrity Check",
+		fmt.Sprintf("The database has %d problems. Restore a backup if items are missing or damaged.\n\n%s",
+			len(problems), strings.Join(shown, "\n")), window)
+}
+
+func (sc *StorageController) setBusy(busy bool) {
+	sc.busy = busy
+	sc.onBusy(busy)
+}
+
+func (sc *StorageController) showError(err error) {
+	if window := sc.getWindow(); window != nil {
+		dialog.ShowError(err, window)
+	}
+}
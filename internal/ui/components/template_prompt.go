@@ -0,0 +1,150 @@
+package components
+
+import (
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// TemplatePrompt asks for template input values and previews the expanded
+// text before it is copied. Its methods block until the user responds, so
+// they must not be called from the UI goroutine.
+type TemplatePrompt struct {
+	getWindow func() fyne.Window
+
+	mu      sync.Mutex
+	pending map[dialog.Dialog]func() // Open prompts and how to cancel them
+}
+
+func NewTemplatePrompt(getWindow func() fyne.Window) *TemplatePrompt {
+	return &TemplatePrompt{getWindow: getWindow}
+}
+
+// Cancel closes the open prompts as if the user had cancelled them, so the
+// copies waiting on them give up. It is called from the UI goroutine, for
+// example when the app locks.
+func (tp *TemplatePrompt) Cancel() {
+	tp.mu.Lock()
+	pending := tp.pending
+	tp.pending = nil
+	tp.mu.Unlock()
+
+	for prompt, cancel := range pending {
+		cancel()
+		prompt.Hide()
+	}
+}
+
+// open shows a prompt and keeps it cancellable until done is called.
+func (tp *TemplatePrompt) open(prompt dialog.Dialog, cancel func()) {
+	tp.mu.Lock()
+	if tp.pending == nil {
+		tp.pending = make(map[dialog.Dialog]func())
+	}
+	tp.pending[prompt] = cancel
+	tp.mu.Unlock()
+
+	prompt.Show()
+}
+
+func (tp *TemplatePrompt) done(prompt dialog.Dialog) {
+	tp.mu.Lock()
+	delete(tp.pending, prompt)
+	tp.mu.Unlock()
+}
+
+// answer delivers the first answer to a prompt. Hiding a cancelled prompt
+// can answer it a second time, which is dropped.
+func answer[T any](result chan T, value T) {
+	select {
+	case result <- value:
+	default:
+	}
+}
+
+// ResolveInputs shows a form with one entry per input label.
+func (tp *TemplatePrompt) ResolveInputs(labels []string) (map[string]string, bool) {
+	result := make(chan map[string]string, 1)
+
+	fyne.Do(func() {
+		window := tp.getWindow()
+		if window == nil {
+			result <- nil
+			return
+		}
+
+		entries := make([]*widget.Entry, len(labels))
+		formItems := make([]*widget.FormItem, len(labels))
+		for i, label := range labels {
+			entries[i] = widget.NewEntry()
+			formItems[i] = widget.NewFormItem(label, entries[i])
+		}
+
+		var form *dialog.FormDialog
+		form = dialog.NewForm("Template Input", "Continue", "Cancel", formItems, func(confirmed bool) {
+			tp.done(form)
+			if !confirmed {
+				answer(result, nil)
+				return
+			}
+
+			values := make(map[string]string, len(labels))
+			for i, label := range labels {
+				values[label] = entries[i].Text
+			}
+			answer(result, values)
+		}, window)
+		form.Resize(fyne.NewSize(400, form.MinSize().Height))
+		tp.open(form, func() { answer(result, nil) })
+
+		if len(entries) > 0 {
+			window.Canvas().Focus(entries[0])
+		}
+	})
+
+	values := <-result
+	return values, values != nil
+}
+
+// ConfirmExpansion previews the expanded text, which can still be adjusted
+// before it is copied.
+func (tp *TemplatePrompt) ConfirmExpansion(expanded string) (string, bool) {
+	type response struct {
+		text string
+		ok   bool
+	}
+	result := make(chan response, 1)
+
+	fyne.Do(func() {
+		window := tp.getWindow()
+		if window == nil {
+			result <- response{}
+			return
+		}
+
+		preview := widget.NewMultiLineEntry()
+		preview.SetText(expanded)
+		preview.Wrapping = fyne.TextWrapWord
+		preview.SetMinRowsVisible(8)
+
+		content := container.NewBorder(
+			widget.NewLabel("The template expands to the following text:"),
+			nil, nil, nil,
+			preview,
+		)
+
+		var confirm *dialog.ConfirmDialog
+		confirm = dialog.NewCustomConfirm("Template Preview", "Copy", "Cancel", content, func(confirmed bool) {
+			tp.done(confirm)
+			answer(result, response{text: preview.Text, ok: confirmed})
+		}, window)
+		confirm.Resize(fyne.NewSize(500, 350))
+		tp.open(confirm, func() { answer(result, response{}) })
+	})
+
+	r := <-result
+	return r.text, r.ok
+}
@@ -13,15 +13,23 @@ type Toolbar struct {
 	onClearAll     func()
 	onShowAbout    func()
 	onCheckUpdates func()
+	onShowTrash    func()
+	onExport       func()
+	onImport       func()
+	onLock         func()
 }
 
-func NewToolbar(itemList *ItemList, onShowSettings, onClearAll, onShowAbout, onCheckUpdates func()) *Toolbar {
+func NewToolbar(itemList *ItemList, onShowSettings, onClearAll, onShowAbout, onCheckUpdates, onShowTrash, onExport, onImport, onLock func()) *Toolbar {
 	tb := &Toolbar{
 		itemList:       itemList,
 		onShowSettings: onShowSettings,
 		onClearAll:     onClearAll,
 		onShowAbout:    onShowAbout,
 		onCheckUpdates: onCheckUpdates,
+		onShowTrash:    onShowTrash,
+		onExport:       onExport,
+		onImport:       onImport,
+		onLock:         onLock,
 	}
 
 	tb.createToolbar()
@@ -37,11 +45,18 @@ func (tb *Toolbar) createToolbar() {
 		widget.NewToolbarAction(theme.ViewRefreshIcon(), func() {
 			tb.itemList.Refresh()
 		}),
+		widget.NewToolbarAction(theme.ListIcon(), func() {
+			tb.itemList.ShowJoinDialog(nil)
+		}),
 		widget.NewToolbarSeparator(),
 		widget.NewToolbarAction(theme.DownloadIcon(), tb.onCheckUpdates),
 		widget.NewToolbarSeparator(),
+		widget.NewToolbarAction(theme.FolderOpenIcon(), tb.onImport),
+		widget.NewToolbarAction(theme.DocumentSaveIcon(), tb.onExport),
 		widget.NewToolbarAction(theme.SettingsIcon(), tb.onShowSettings),
+		widget.NewToolbarAction(theme.VisibilityOffIcon(), tb.onLock),
 		widget.NewToolbarSpacer(),
+		widget.NewToolbarAction(theme.DeleteIcon(), tb.onShowTrash),
 		widget.NewToolbarAction(theme.ContentClearIcon(), tb.onClearAll),
 		widget.NewToolbarAction(theme.InfoIcon(), tb.onShowAbout),
 	)
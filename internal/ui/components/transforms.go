@@ -0,0 +1,46 @@
+package components
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+
+	"clipboardpro/internal/database"
+	"clipboardpro/internal/transform"
+)
+
+// createTransformMenuItem returns the row menu entry with the transforms
+// of text items, grouped in submenus.
+func (il *ItemList) createTransformMenuItem(item *database.ClipboardItem) *fyne.MenuItem {
+	var groups []*fyne.MenuItem
+	for _, group := range transform.Groups {
+		var transforms []*fyne.MenuItem
+		for _, t := range group.Transforms {
+			transforms = append(transforms, fyne.NewMenuItem(t.Name, func() {
+				il.applyTransform(t, item)
+			}))
+		}
+
+		groupItem := fyne.NewMenuItem(group.Name, nil)
+		groupItem.ChildMenu = fyne.NewMenu("", transforms...)
+		groups = append(groups, groupItem)
+	}
+
+	transformItem := fyne.NewMenuItem("Transform", nil)
+	transformItem.ChildMenu = fyne.NewMenu("", groups...)
+	return transformItem
+}
+
+// applyTransform shows the result of a transform of the item's content,
+// to be copied or saved as a new item.
+func (il *ItemList) applyTransform(t transform.Transform, item *database.ClipboardItem) {
+	il.withFullItem(item, func(item *database.ClipboardItem, window fyne.Window) {
+		result, err := t.Apply(item.Content)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s failed: %w", t.Name, err), window)
+			return
+		}
+		il.showTextResult(t.Name, result, window)
+	})
+}
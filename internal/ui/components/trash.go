@@ -0,0 +1,140 @@
+package components
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"clipboardpro/internal/database"
+)
+
+// TrashDialog lists deleted items and lets the user restore or purge them.
+type TrashDialog struct {
+	controller  *TrashController
+	itemList    *ItemList
+	parent      fyne.Window
+	list        *widget.List
+	statusLabel *widget.Label
+}
+
+func NewTrashDialog(repository database.Store, itemList *ItemList, parent fyne.Window) *TrashDialog {
+	td := &TrashDialog{
+		itemList:    itemList,
+		parent:      parent,
+		statusLabel: widget.NewLabel("Loading..."),
+	}
+
+	td.controller = NewTrashController(
+		repository,
+		td.statusLabel,
+		func() { td.list.Refresh() },
+		itemList.Refresh,
+		func() fyne.Window { return td.parent },
+	)
+
+	td.createList()
+	return td
+}
+
+func (td *TrashDialog) Show() {
+	emptyButton := widget.NewButtonWithIcon("Empty Trash", theme.DeleteIcon(), td.controller.EmptyTrash)
+	emptyButton.Importance = widget.DangerImportance
+
+	info := widget.NewLabel(fmt.Sprintf("Items are permanently deleted after %d days in the trash.",
+		td.itemList.app.GetConfig().TrashRetentionDays))
+	info.Wrapping = fyne.TextWrapWord
+
+	content := container.NewBorder(
+		container.NewVBox(
+			container.NewBorder(nil, nil, td.statusLabel, emptyButton),
+			info,
+			widget.NewSeparator(),
+		),
+		nil, nil, nil,
+		td.list,
+	)
+
+	d := dialog.NewCustom("Trash", "Close", content, td.parent)
+	d.Resize(fyne.NewSize(700, 500))
+	d.Show()
+
+	td.controller.LoadItems()
+}
+
+func (td *TrashDialog) createList() {
+	td.list = widget.NewList(
+		func() int {
+			return len(td.controller.GetItems())
+		},
+		func() fyne.CanvasObject {
+			return td.createItemTemplate()
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			td.updateItem(id, item)
+		},
+	)
+}
+
+func (td *TrashDialog) createItemTemplate() fyne.CanvasObject {
+	icon := widget.NewIcon(theme.DocumentIcon())
+
+	title := widget.NewLabel("")
+	title.TextStyle = fyne.TextStyle{Bold: true}
+	title.Truncation = fyne.TextTruncateEllipsis
+
+	details := widget.NewLabel("")
+	details.TextStyle = fyne.TextStyle{Italic: true}
+
+	restoreButton := widget.NewButtonWithIcon("Restore", theme.ContentUndoIcon(), nil)
+	restoreButton.Importance = widget.LowImportance
+
+	purgeButton := widget.NewButtonWithIcon("", theme.DeleteIcon(), nil)
+	purgeButton.Importance = widget.LowImportance
+
+	return container.NewBorder(
+		nil, nil,
+		icon,
+		container.NewHBox(restoreButton, purgeButton),
+		container.NewVBox(title, container.NewHBox(details, layout.NewSpacer())),
+	)
+}
+
+func (td *TrashDialog) updateItem(id widget.ListItemID, obj fyne.CanvasObject) {
+	items := td.controller.GetItems()
+	if id >= len(items) {
+		return
+	}
+
+	item := items[id]
+	row := obj.(*fyne.Container)
+	textContainer := row.Objects[0].(*fyne.Container)
+	icon := row.Objects[1].(*widget.Icon)
+	actionContainer := row.Objects[2].(*fyne.Container)
+
+	title := textContainer.Objects[0].(*widget.Label)
+	details := textContainer.Objects[1].(*fyne.Container).Objects[0].(*widget.Label)
+	restoreButton := actionContainer.Objects[0].(*widget.Button)
+	purgeButton := actionContainer.Objects[1].(*widget.Button)
+
+	icon.SetResource(td.itemList.getItemIcon(item))
+	title.SetText(td.itemList.getItemTitle(item))
+
+	reason := item.DeleteReason
+	if reason == "" {
+		reason = database.DeleteReasonUser
+	}
+	details.SetText(fmt.Sprintf("%s • %s • %s", reason, td.itemList.formatTimeAgo(item.DeletedAt), td.itemList.formatBytes(item.Size)))
+
+	restoreButton.OnTapped = func() {
+		td.controller.Restore(item.ID)
+	}
+
+	purgeButton.OnTapped = func() {
+		td.controller.Purge(item.ID)
+	}
+}
@@ -0,0 +1,147 @@
+package components
+
+import (
+	"context"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"clipboardpro/internal/database"
+)
+
+type TrashController struct {
+	repository  database.Store
+	statusLabel *widget.Label
+	items       []*database.ClipboardItem
+	listRefresh func()             // Callback to refresh the trash list
+	onChanged   func()             // Callback after items leave the trash
+	getWindow   func() fyne.Window // Callback to get the parent window
+}
+
+func NewTrashController(repository database.Store, statusLabel *widget.Label, listRefresh, onChanged func(), getWindow func() fyne.Window) *TrashController {
+	return &TrashController{
+		repository:  repository,
+		statusLabel: statusLabel,
+		listRefresh: listRefresh,
+		onChanged:   onChanged,
+		getWindow:   getWindow,
+	}
+}
+
+func (tc *TrashController) GetItems() []*database.ClipboardItem {
+	return tc.items
+}
+
+// LoadItems loads the items currently in the trash.
+func (tc *TrashController) LoadItems() {
+	go func() {
+		ctx := context.Background()
+		items, err := tc.repository.GetTrashedItems(ctx, 500)
+
+		fyne.Do(func() {
+			if err != nil {
+				tc.statusLabel.SetText("Error loading trash")
+				tc.showError(fmt.Errorf("failed to load trash: %w", err))
+				return
+			}
+
+			tc.items = items
+			tc.listRefresh()
+
+			count := len(items)
+			if count == 0 {
+				tc.statusLabel.SetText("Trash is empty")
+			} else if count == 1 {
+				tc.statusLabel.SetText("1 item")
+			} else {
+				tc.statusLabel.SetText(fmt.Sprintf("%d items", count))
+			}
+		})
+	}()
+}
+
+// Restore moves an item back into the clipboard history.
+func (tc *TrashController) Restore(id int64) {
+	go func() {
+		ctx := context.Background()
+		if err := tc.repository.RestoreItem(ctx, id); err != nil {
+			fyne.Do(func() {
+				tc.showError(fmt.Errorf("failed to restore item: %w", err))
+			})
+			return
+		}
+
+		fyne.Do(func() {
+			tc.onChanged()
+			tc.LoadItems()
+		})
+	}()
+}
+
+// Purge permanently deletes an item after user confirmation.
+func (tc *TrashController) Purge(id int64) {
+	window := tc.getWindow()
+	if window == nil {
+		return
+	}
+
+	dialog.ShowConfirm("Delete Forever",
+		"Are you sure you want to permanently delete this item? This action cannot be undone.",
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+
+			go func() {
+				ctx := context.Background()
+				if err := tc.repository.PurgeItem(ctx, id); err != nil {
+					fyne.Do(func() {
+						tc.showError(fmt.Errorf("failed to delete item: %w", err))
+					})
+					return
+				}
+
+				fyne.Do(func() {
+					tc.LoadItems()
+				})
+			}()
+		}, window)
+}
+
+// EmptyTrash permanently deletes everything in the trash after user confirmation.
+func (tc *TrashController) EmptyTrash() {
+	window := tc.getWindow()
+	if window == nil {
+		return
+	}
+
+	dialog.ShowConfirm("Empty Trash",
+		"Are you sure you want to permanently delete all items in the trash? This action cannot be undone.",
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+
+			go func() {
+				ctx := context.Background()
+				if err := tc.repository.EmptyTrash(ctx); err != nil {
+					fyne.Do(func() {
+						tc.showError(fmt.Errorf("failed to empty trash: %w", err))
+					})
+					return
+				}
+
+				fyne.Do(func() {
+					tc.LoadItems()
+				})
+			}()
+		}, window)
+}
+
+func (tc *TrashController) showError(err error) {
+	if window := tc.getWindow(); window != nil {
+		dialog.ShowError(err, window)
+	}
+}
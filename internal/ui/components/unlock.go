@@ -0,0 +1,82 @@
+package components
+
+import (
+	"errors"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"clipboardpro/internal/vault"
+)
+
+// UnlockDialog asks for the passphrase of an encrypted history and keeps
+// asking until it is correct or the user quits.
+type UnlockDialog struct {
+	unlock     func(passphrase string) error // Callback to check the passphrase and unlock
+	onUnlocked func()                        // Callback once the history is readable
+	onQuit     func()                        // Callback when the user gives up
+	parent     fyne.Window
+}
+
+func NewUnlockDialog(unlock func(string) error, onUnlocked, onQuit func(), parent fyne.Window) *UnlockDialog {
+	return &UnlockDialog{
+		unlock:     unlock,
+		onUnlocked: onUnlocked,
+		onQuit:     onQuit,
+		parent:     parent,
+	}
+}
+
+func (ud *UnlockDialog) Show() {
+	ud.show("")
+}
+
+func (ud *UnlockDialog) show(message string) {
+	passphraseEntry := widget.NewPasswordEntry()
+	passphraseEntry.SetPlaceHolder("Passphrase")
+
+	messageLabel := widget.NewLabel(message)
+	messageLabel.Importance = widget.DangerImportance
+	if message == "" {
+		messageLabel.Hide()
+	}
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("", widget.NewLabel("Your clipboard history is encrypted.")),
+		widget.NewFormItem("Passphrase", passphraseEntry),
+		widget.NewFormItem("", messageLabel),
+	}
+
+	form := dialog.NewForm("Unlock History", "Unlock", "Quit", items, func(confirmed bool) {
+		if !confirmed {
+			ud.onQuit()
+			return
+		}
+
+		progress := dialog.NewCustomWithoutButtons("Unlock History", widget.NewProgressBarInfinite(), ud.parent)
+		progress.Show()
+
+		// Deriving the key takes a moment by design
+		passphrase := passphraseEntry.Text
+		go func() {
+			err := ud.unlock(passphrase)
+
+			fyne.Do(func() {
+				progress.Hide()
+				switch {
+				case errors.Is(err, vault.ErrWrongPassphrase):
+					ud.show("Wrong passphrase, please try again.")
+				case err != nil:
+					ud.show(err.Error())
+				default:
+					ud.onUnlocked()
+				}
+			})
+		}()
+	}, ud.parent)
+	form.Resize(fyne.NewSize(400, form.MinSize().Height))
+	form.Show()
+
+	ud.parent.Canvas().Focus(passphraseEntry)
+}
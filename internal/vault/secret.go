@@ -0,0 +1,63 @@
+package vault
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// HashSecret hashes a PIN or passphrase for storage, in the PHC string
+// format used by other Argon2id implementations.
+func HashSecret(secret string) (string, error) {
+	params, err := NewParams()
+	if err != nil {
+		return "", err
+	}
+
+	hash := argon2.IDKey([]byte(secret), params.Salt, params.Time, params.Memory, params.Threads, keyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, params.Memory, params.Time, params.Threads,
+		base64.RawStdEncoding.EncodeToString(params.Salt),
+		base64.RawStdEncoding.EncodeToString(hash)), nil
+}
+
+// VerifySecret checks a PIN or passphrase against a hash made by
+// HashSecret. ErrWrongPassphrase is returned if it does not match.
+func VerifySecret(secret, encoded string) error {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return errors.New("invalid secret hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return errors.New("unsupported secret hash version")
+	}
+
+	var params Params
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Time, &params.Threads); err != nil {
+		return fmt.Errorf("invalid secret hash: %w", err)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return fmt.Errorf("invalid secret hash: %w", err)
+	}
+	want, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return fmt.Errorf("invalid secret hash: %w", err)
+	}
+	if params.Time == 0 || params.Memory == 0 || params.Threads == 0 || len(want) == 0 {
+		return errors.New("invalid secret hash")
+	}
+
+	got := argon2.IDKey([]byte(secret), salt, params.Time, params.Memory, params.Threads, uint32(len(want)))
+	if subtle.ConstantTimeCompare(got, want) != 1 {
+		return ErrWrongPassphrase
+	}
+	return nil
+}
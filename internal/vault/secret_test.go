@@ -0,0 +1,51 @@
+package vault
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestVerifySecret(t *testing.T) {
+	hash, err := HashSecret("1234")
+	if err != nil {
+		t.Fatalf("HashSecret: %v", err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$") {
+		t.Errorf("HashSecret = %q, not in the PHC format", hash)
+	}
+
+	other, _ := HashSecret("1234")
+	if other == hash {
+		t.Error("HashSecret gave the same hash twice; the salt is not random")
+	}
+
+	tests := []struct {
+		name    string
+		secret  string
+		encoded string
+		wantErr error
+		invalid bool // The hash itself is rejected
+	}{
+		{name: "correct", secret: "1234", encoded: hash},
+		{name: "wrong", secret: "4321", encoded: hash, wantErr: ErrWrongPassphrase},
+		{name: "empty hash", secret: "1234", encoded: "", invalid: true},
+		{name: "other algorithm", secret: "1234", encoded: strings.Replace(hash, "argon2id", "argon2i", 1), invalid: true},
+		{name: "bad salt", secret: "1234", encoded: "$argon2id$v=19$m=64,t=1,p=1$!!$AAAA", invalid: true},
+		{name: "zero cost", secret: "1234", encoded: "$argon2id$v=19$m=0,t=1,p=1$AAAA$AAAA", invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifySecret(tt.secret, tt.encoded)
+			switch {
+			case tt.invalid:
+				if err == nil || errors.Is(err, ErrWrongPassphrase) {
+					t.Errorf("VerifySecret error = %v, want an invalid hash error", err)
+				}
+			case !errors.Is(err, tt.wantErr):
+				t.Errorf("VerifySecret error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
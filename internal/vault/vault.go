@@ -0,0 +1,185 @@
+// Package vault encrypts data with a key derived from a passphrase, using
+// Argon2id for key derivation and AES-256-GCM for encryption.
+package vault
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// ErrWrongPassphrase is returned when a passphrase does not match the one
+// data was encrypted with.
+var ErrWrongPassphrase = errors.New("wrong passphrase")
+
+const (
+	keyLength  = 32
+	saltLength = 16
+
+	// stringPrefix marks encrypted strings, which are base64 encoded.
+	stringPrefix = "enc:v1:"
+)
+
+// bytesPrefix marks encrypted binary data.
+var bytesPrefix = []byte("CPENC1")
+
+// Params are the key derivation parameters, stored alongside encrypted data.
+type Params struct {
+	Salt    []byte `json:"salt"`
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory_kib"`
+	Threads uint8  `json:"threads"`
+}
+
+// NewParams returns parameters with a fresh random salt and the
+// recommended Argon2id settings.
+func NewParams() (Params, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return Params{}, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	return Params{
+		Salt:    salt,
+		Time:    3,
+		Memory:  64 * 1024,
+		Threads: 4,
+	}, nil
+}
+
+// Key encrypts and decrypts data and computes keyed hashes.
+type Key struct {
+	aead    cipher.AEAD
+	hashKey []byte
+}
+
+// DeriveKey derives a key from a passphrase. It is deliberately slow.
+func DeriveKey(passphrase string, params Params) (*Key, error) {
+	if len(params.Salt) == 0 || params.Time == 0 || params.Memory == 0 || params.Threads == 0 {
+		return nil, errors.New("invalid key derivation parameters")
+	}
+
+	secret := argon2.IDKey([]byte(passphrase), params.Salt, params.Time, params.Memory, params.Threads, keyLength)
+
+	block, err := aes.NewCipher(secret)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// A separate key for hashes, so hashes reveal nothing about the
+	// encryption key
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("clipboardpro hash key"))
+
+	return &Key{aead: aead, hashKey: mac.Sum(nil)}, nil
+}
+
+// Seal encrypts data. The result holds a random nonce followed by the
+// ciphertext.
+func (k *Key) Seal(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, k.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return k.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Open decrypts data produced by Seal.
+func (k *Key) Open(sealed []byte) ([]byte, error) {
+	size := k.aead.NonceSize()
+	if len(sealed) < size {
+		return nil, errors.New("encrypted data is too short")
+	}
+
+	plaintext, err := k.aead.Open(nil, sealed[:size], sealed[size:], nil)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return plaintext, nil
+}
+
+// EncryptString encrypts a string into a printable form. Empty strings are
+// left empty.
+func (k *Key) EncryptString(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+
+	sealed, err := k.Seal([]byte(s))
+	if err != nil {
+		return "", err
+	}
+	return stringPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptString reverses EncryptString. Strings that were never encrypted
+// are returned unchanged.
+func (k *Key) DecryptString(s string) (string, error) {
+	if !IsEncryptedString(s) {
+		return s, nil
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(s[len(stringPrefix):])
+	if err != nil {
+		return "", fmt.Errorf("malformed encrypted value: %w", err)
+	}
+	plaintext, err := k.Open(sealed)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// EncryptBytes encrypts binary data. Empty data is left empty.
+func (k *Key) EncryptBytes(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return data, nil
+	}
+
+	sealed, err := k.Seal(data)
+	if err != nil {
+		return nil, err
+	}
+	return append(bytes.Clone(bytesPrefix), sealed...), nil
+}
+
+// DecryptBytes reverses EncryptBytes. Data that was never encrypted is
+// returned unchanged.
+func (k *Key) DecryptBytes(data []byte) ([]byte, error) {
+	if !IsEncryptedBytes(data) {
+		return data, nil
+	}
+	return k.Open(data[len(bytesPrefix):])
+}
+
+// Hash returns a keyed hash of value, hex encoded. It lets encrypted data be
+// matched by content without storing a plain hash of it.
+func (k *Key) Hash(value string) string {
+	mac := hmac.New(sha256.New, k.hashKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// IsEncryptedString reports whether s was produced by EncryptString.
+func IsEncryptedString(s string) bool {
+	return strings.HasPrefix(s, stringPrefix)
+}
+
+// IsEncryptedBytes reports whether data was produced by EncryptBytes.
+func IsEncryptedBytes(data []byte) bool {
+	return bytes.HasPrefix(data, bytesPrefix)
+}
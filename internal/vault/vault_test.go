@@ -0,0 +1,159 @@
+package vault
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// testParams keeps key derivation fast; the salt is fixed so tests can
+// derive the same key twice.
+func testParams() Params {
+	return Params{Salt: []byte("0123456789abcdef"), Time: 1, Memory: 64, Threads: 1}
+}
+
+func deriveKey(t *testing.T, passphrase string, params Params) *Key {
+	t.Helper()
+	key, err := DeriveKey(passphrase, params)
+	if err != nil {
+		t.Fatalf("DeriveKey: %v", err)
+	}
+	return key
+}
+
+func TestSealOpen(t *testing.T) {
+	key := deriveKey(t, "correct horse", testParams())
+
+	for _, plaintext := range [][]byte{{}, []byte("x"), []byte("clipboard history"), bytes.Repeat([]byte{0xff}, 4096)} {
+		sealed, err := key.Seal(plaintext)
+		if err != nil {
+			t.Fatalf("Seal: %v", err)
+		}
+		if len(plaintext) > 8 && bytes.Contains(sealed, plaintext) {
+			t.Errorf("sealed data contains the plaintext %q", plaintext)
+		}
+
+		opened, err := deriveKey(t, "correct horse", testParams()).Open(sealed)
+		if err != nil {
+			t.Fatalf("Open: %v", err)
+		}
+		if !bytes.Equal(opened, plaintext) {
+			t.Errorf("Open = %q, want %q", opened, plaintext)
+		}
+	}
+}
+
+func TestSealUsesFreshNonces(t *testing.T) {
+	key := deriveKey(t, "correct horse", testParams())
+
+	first, _ := key.Seal([]byte("same"))
+	second, _ := key.Seal([]byte("same"))
+	if bytes.Equal(first, second) {
+		t.Error("sealing the same plaintext twice gave the same result")
+	}
+}
+
+func TestOpenRejects(t *testing.T) {
+	params := testParams()
+	sealed, err := deriveKey(t, "correct horse", params).Seal([]byte("secret"))
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+
+	otherSalt := testParams()
+	otherSalt.Salt = []byte("fedcba9876543210")
+
+	tampered := bytes.Clone(sealed)
+	tampered[len(tampered)-1] ^= 1
+
+	tests := []struct {
+		name   string
+		key    *Key
+		sealed []byte
+	}{
+		{"wrong passphrase", deriveKey(t, "battery staple", params), sealed},
+		{"wrong salt", deriveKey(t, "correct horse", otherSalt), sealed},
+		{"tampered data", deriveKey(t, "correct horse", params), tampered},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.key.Open(tt.sealed); !errors.Is(err, ErrWrongPassphrase) {
+				t.Errorf("Open error = %v, want ErrWrongPassphrase", err)
+			}
+		})
+	}
+
+	if _, err := deriveKey(t, "correct horse", params).Open(sealed[:4]); err == nil {
+		t.Error("Open accepted truncated data")
+	}
+}
+
+func TestStringsAndBytes(t *testing.T) {
+	key := deriveKey(t, "correct horse", testParams())
+
+	tests := []string{"", "hello", "enc:v1 is not a prefix here", "ünïcødé"}
+	for _, plaintext := range tests {
+		encrypted, err := key.EncryptString(plaintext)
+		if err != nil {
+			t.Fatalf("EncryptString(%q): %v", plaintext, err)
+		}
+		if plaintext != "" && !IsEncryptedString(encrypted) {
+			t.Errorf("EncryptString(%q) = %q, not marked as encrypted", plaintext, encrypted)
+		}
+		if decrypted, err := key.DecryptString(encrypted); err != nil || decrypted != plaintext {
+			t.Errorf("DecryptString(EncryptString(%q)) = %q, %v", plaintext, decrypted, err)
+		}
+
+		data, err := key.EncryptBytes([]byte(plaintext))
+		if err != nil {
+			t.Fatalf("EncryptBytes(%q): %v", plaintext, err)
+		}
+		if decrypted, err := key.DecryptBytes(data); err != nil || string(decrypted) != plaintext {
+			t.Errorf("DecryptBytes(EncryptBytes(%q)) = %q, %v", plaintext, decrypted, err)
+		}
+	}
+
+	// Values stored before encryption was enabled pass through
+	if got, err := key.DecryptString("plain"); err != nil || got != "plain" {
+		t.Errorf("DecryptString(plain) = %q, %v", got, err)
+	}
+	if got, err := key.DecryptBytes([]byte{1, 2, 3}); err != nil || !bytes.Equal(got, []byte{1, 2, 3}) {
+		t.Errorf("DecryptBytes(plain) = %v, %v", got, err)
+	}
+
+	encrypted, _ := key.EncryptString("secret")
+	if _, err := deriveKey(t, "battery staple", testParams()).DecryptString(encrypted); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("DecryptString with the wrong key: error = %v, want ErrWrongPassphrase", err)
+	}
+}
+
+func TestHash(t *testing.T) {
+	key := deriveKey(t, "correct horse", testParams())
+	other := deriveKey(t, "battery staple", testParams())
+
+	if key.Hash("a") != deriveKey(t, "correct horse", testParams()).Hash("a") {
+		t.Error("Hash differs between keys derived from the same passphrase")
+	}
+	if key.Hash("a") == key.Hash("b") {
+		t.Error("Hash is the same for different values")
+	}
+	if key.Hash("a") == other.Hash("a") {
+		t.Error("Hash is the same for different keys")
+	}
+}
+
+func TestDeriveKeyRejectsInvalidParams(t *testing.T) {
+	tests := []Params{
+		{},
+		{Salt: []byte("salt"), Time: 0, Memory: 64, Threads: 1},
+		{Salt: []byte("salt"), Time: 1, Memory: 0, Threads: 1},
+		{Salt: []byte("salt"), Time: 1, Memory: 64, Threads: 0},
+	}
+
+	for _, params := range tests {
+		if _, err := DeriveKey("passphrase", params); err == nil {
+			t.Errorf("DeriveKey accepted %+v", params)
+		}
+	}
+}
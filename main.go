@@ -1,16 +1,29 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
 	"os"
 
 	"clipboardpro/internal/app"
+	"clipboardpro/internal/cli"
 )
 
 func main() {
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
 
-	clipboardApp, err := app.NewClipboardProApp()
+	if len(os.Args) > 1 && cli.IsCommand(os.Args[1]) {
+		if err := cli.Run(context.Background(), os.Args[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	ephemeral := flag.Bool("ephemeral", false, "keep the history in memory only; nothing is saved to disk")
+	flag.Parse()
+
+	clipboardApp, err := app.NewClipboardProApp(app.Options{Ephemeral: *ephemeral})
 	if err != nil {
 		log.Fatalf("Failed to create application: %v", err)
 		os.Exit(1)